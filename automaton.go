@@ -0,0 +1,470 @@
+package sensitive
+
+import "unicode/utf8"
+
+// automaton 词典的只读遍历接口。Trie和DoubleArray都实现了该接口，
+// 各种匹配算法只依赖它，从而在不同的存储结构上得到完全一致的结果
+type automaton[S any] interface {
+	// start 返回根状态
+	start() S
+	// next 沿字符r转移，不存在时返回false
+	next(state S, r rune) (S, bool)
+	// end 判断状态是否为某个词的结束
+	end(state S) bool
+}
+
+// replaceRunes 原地替换runes中的词语，返回是否有改动
+func replaceRunes[S any](a automaton[S], runes []rune, character rune) bool {
+	var (
+		changed bool
+		parent  = a.start()
+		current S
+		length  = len(runes)
+		left    = 0
+		found   bool
+	)
+
+	for position := 0; position < length; position++ {
+		current, found = a.next(parent, runes[position])
+
+		if !found || (!a.end(current) && position == length-1) {
+			parent = a.start()
+			position = left
+			left++
+			continue
+		}
+
+		if a.end(current) && left <= position {
+			for i := left; i <= position; i++ {
+				runes[i] = character
+			}
+			changed = true
+		}
+
+		parent = current
+	}
+	return changed
+}
+
+// filterRunes 将过滤后的结果追加到dst后返回
+func filterRunes[S any](a automaton[S], dst, runes []rune) []rune {
+	var (
+		parent      = a.start()
+		current     S
+		left        = 0
+		found       bool
+		length      = len(runes)
+		resultRunes = dst
+	)
+
+	for position := 0; position < length; position++ {
+		current, found = a.next(parent, runes[position])
+
+		if !found || (!a.end(current) && position == length-1) {
+			resultRunes = append(resultRunes, runes[left])
+			parent = a.start()
+			position = left
+			left++
+			continue
+		}
+
+		if a.end(current) {
+			left = position + 1
+			parent = a.start()
+		} else {
+			parent = current
+		}
+
+	}
+
+	return append(resultRunes, runes[left:]...)
+}
+
+func validateRunes[S any](a automaton[S], runes []rune) (bool, string) {
+	var (
+		parent  = a.start()
+		current S
+		length  = len(runes)
+		left    = 0
+		found   bool
+	)
+
+	for position := 0; position < length; position++ {
+		current, found = a.next(parent, runes[position])
+
+		if !found || (!a.end(current) && position == length-1) {
+			parent = a.start()
+			position = left
+			left++
+			continue
+		}
+
+		if a.end(current) && left <= position {
+			return false, string(runes[left : position+1])
+		}
+
+		parent = current
+	}
+
+	return true, ""
+}
+
+func validateWithWildcard[S any](a automaton[S], runes []rune, wildcard rune) (bool, string) {
+	for curl := 0; curl < len(runes); curl++ {
+		patter := ""
+		if dfs(a, runes, a.start(), curl, wildcard, "", &patter) {
+			return false, patter
+		}
+	}
+	return true, ""
+}
+
+func dfs[S any](a automaton[S], runes []rune, parent S, curl int, wildcard rune, str string, patter *string) bool {
+	if a.end(parent) {
+		*patter = str
+		return true
+	}
+	if curl >= len(runes) {
+		return false
+	}
+
+	// 匹配到了
+	if current, found := a.next(parent, runes[curl]); found {
+		if dfs(a, runes, current, curl+1, wildcard, str+string(runes[curl]), patter) {
+			return true
+		}
+	}
+
+	// 先看有没有*
+	if current1, found1 := a.next(parent, wildcard); found1 {
+		if dfs(a, runes, current1, curl+1, wildcard, str+string(wildcard), patter) {
+			return true
+		}
+
+		if current2, found2 := a.next(current1, runes[curl]); found2 {
+			if dfs(a, runes, current2, curl+1, wildcard, str+string(wildcard)+string(runes[curl]), patter) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateWithWildcardN 同validateWithWildcard，词中的wildcard可匹配0到max个任意字符
+func validateWithWildcardN[S any](a automaton[S], runes []rune, wildcard rune, max int) (bool, string) {
+	word := make([]rune, 0, 16)
+	for start := 0; start < len(runes); start++ {
+		if pattern, ok := matchWildcard(a, runes, a.start(), start, wildcard, max, word); ok {
+			return false, pattern
+		}
+	}
+	return true, ""
+}
+
+// matchWildcard 从state出发匹配runes[pos:]，命中时返回词典中的词(含wildcard)。
+// 优先逐字匹配，再依次尝试wildcard匹配0到max个字符
+func matchWildcard[S any](a automaton[S], runes []rune, state S, pos int, wildcard rune, max int, word []rune) (string, bool) {
+	if a.end(state) {
+		return string(word), true
+	}
+	if pos < len(runes) {
+		if next, ok := a.next(state, runes[pos]); ok {
+			if pattern, ok := matchWildcard(a, runes, next, pos+1, wildcard, max, append(word, runes[pos])); ok {
+				return pattern, true
+			}
+		}
+	}
+	if next, ok := a.next(state, wildcard); ok {
+		for k := 0; k <= max && pos+k <= len(runes); k++ {
+			if pattern, ok := matchWildcard(a, runes, next, pos+k, wildcard, max, append(word, wildcard)); ok {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
+
+func findAllRunes[S any](a automaton[S], runes []rune) []string {
+	var matches []string
+	var (
+		parent  = a.start()
+		current S
+		length  = len(runes)
+		left    = 0
+		found   bool
+	)
+
+	for position := 0; position < length; position++ {
+		current, found = a.next(parent, runes[position])
+
+		if !found {
+			parent = a.start()
+			position = left
+			left++
+			continue
+		}
+
+		if a.end(current) && left <= position {
+			matches = append(matches, string(runes[left:position+1]))
+		}
+
+		if position == length-1 {
+			parent = a.start()
+			position = left
+			left++
+			continue
+		}
+
+		parent = current
+	}
+
+	var i = 0
+	if count := len(matches); count > 0 {
+		set := make(map[string]struct{})
+		for i < count {
+			_, ok := set[matches[i]]
+			if !ok {
+				set[matches[i]] = struct{}{}
+				i++
+				continue
+			}
+			count--
+			copy(matches[i:], matches[i+1:])
+		}
+		return matches[:count]
+	}
+
+	return nil
+}
+
+// matchAt 按策略返回从start开始命中词语的结束位置(不含)，未命中返回-1
+func matchAt[S any](a automaton[S], runes []rune, start int, policy MatchPolicy) int {
+	var (
+		parent = a.start()
+		end    = -1
+	)
+
+	for position := start; position < len(runes); position++ {
+		current, found := a.next(parent, runes[position])
+		if !found {
+			break
+		}
+		if a.end(current) {
+			end = position + 1
+			if policy == MatchShortest {
+				break
+			}
+		}
+		parent = current
+	}
+
+	return end
+}
+
+// scan 从左到右按策略找出互不重叠的命中区间
+func scan[S any](a automaton[S], runes []rune, policy MatchPolicy) [][2]int {
+	return scanLimit(a, runes, policy, 0)
+}
+
+// scanLimit 同scan，找到limit个命中后即停止，limit不大于0时不限制
+func scanLimit[S any](a automaton[S], runes []rune, policy MatchPolicy, limit int) [][2]int {
+	var spans [][2]int
+	for left := 0; left < len(runes); left++ {
+		if end := matchAt(a, runes, left, policy); end > 0 {
+			spans = append(spans, [2]int{left, end})
+			if len(spans) == limit {
+				break
+			}
+			left = end - 1
+		}
+	}
+	return spans
+}
+
+// matchLimit 按策略从左到右返回前limit个互不重叠的命中，找到后即停止扫描，
+// limit不大于0时不限制，MatchDefault按最长匹配处理
+func matchLimit[S any](a automaton[S], text string, policy MatchPolicy, limit int) []Match {
+	if policy == MatchDefault {
+		policy = MatchLongest
+	}
+	var (
+		runes   = []rune(text)
+		matches []Match
+	)
+	for _, span := range scanLimit(a, runes, policy, limit) {
+		matches = append(matches, Match{Word: string(runes[span[0]:span[1]]), Start: span[0], End: span[1]})
+	}
+	return matches
+}
+
+// scanOverlap 找出所有位置上的全部命中区间，包括相互重叠的
+func scanOverlap[S any](a automaton[S], runes []rune) [][2]int {
+	var spans [][2]int
+	for left := 0; left < len(runes); left++ {
+		parent := a.start()
+		for position := left; position < len(runes); position++ {
+			current, found := a.next(parent, runes[position])
+			if !found {
+				break
+			}
+			if a.end(current) {
+				spans = append(spans, [2]int{left, position + 1})
+			}
+			parent = current
+		}
+	}
+	return spans
+}
+
+func replaceWithPolicy[S any](a automaton[S], text string, character rune, policy MatchPolicy) string {
+	buf := getRunes(text)
+	defer putRunes(buf)
+	runes := *buf
+
+	if policy == MatchDefault {
+		if !replaceRunes(a, runes, character) {
+			return text
+		}
+		return string(runes)
+	}
+
+	spans := scan(a, runes, policy)
+	if len(spans) == 0 {
+		return text
+	}
+	for _, span := range spans {
+		for i := span[0]; i < span[1]; i++ {
+			runes[i] = character
+		}
+	}
+	return string(runes)
+}
+
+func filterWithPolicy[S any](a automaton[S], text string, policy MatchPolicy) string {
+	buf := getRunes(text)
+	defer putRunes(buf)
+	runes := *buf
+
+	result := getBuffer(len(runes))
+	defer putRunes(result)
+
+	if policy == MatchDefault {
+		*result = filterRunes(a, *result, runes)
+	} else {
+		left := 0
+		for _, span := range scan(a, runes, policy) {
+			*result = append(*result, runes[left:span[0]]...)
+			left = span[1]
+		}
+		*result = append(*result, runes[left:]...)
+	}
+
+	if len(*result) == len(runes) {
+		return text
+	}
+	return string(*result)
+}
+
+func findAllWithPolicy[S any](a automaton[S], text string, policy MatchPolicy) []string {
+	runes := []rune(text)
+	if policy == MatchDefault {
+		return findAllRunes(a, runes)
+	}
+
+	var (
+		matches []string
+		set     = make(map[string]struct{})
+	)
+	for _, span := range scan(a, runes, policy) {
+		word := string(runes[span[0]:span[1]])
+		if _, ok := set[word]; ok {
+			continue
+		}
+		set[word] = struct{}{}
+		matches = append(matches, word)
+	}
+	return matches
+}
+
+func findAllWithIndex[S any](a automaton[S], text string, policy MatchPolicy, overlap bool) []Match {
+	var (
+		runes = []rune(text)
+		spans [][2]int
+	)
+	if overlap {
+		spans = scanOverlap(a, runes)
+	} else {
+		if policy == MatchDefault {
+			policy = MatchLongest
+		}
+		spans = scan(a, runes, policy)
+	}
+
+	var matches []Match
+	for _, span := range spans {
+		matches = append(matches, Match{
+			Word:  string(runes[span[0]:span[1]]),
+			Start: span[0],
+			End:   span[1],
+		})
+	}
+	return matches
+}
+
+// hasWord 判断word是否为词典中的一个完整的词
+func hasWord[S any](a automaton[S], word string) bool {
+	if word == "" {
+		return false
+	}
+	state, ok := descend(a, word)
+	return ok && a.end(state)
+}
+
+// descend 从起始状态沿prefix前进，返回到达的状态
+func descend[S any](a automaton[S], prefix string) (S, bool) {
+	state := a.start()
+	for _, r := range prefix {
+		next, ok := a.next(state, r)
+		if !ok {
+			return state, false
+		}
+		state = next
+	}
+	return state, true
+}
+
+// scanBytes 直接在UTF-8字节上从左到右按策略找出互不重叠的命中，
+// 返回字节区间，MatchDefault按最长匹配处理
+func scanBytes[S any](a automaton[S], text []byte, policy MatchPolicy) [][2]int {
+	var spans [][2]int
+	for left := 0; left < len(text); {
+		var (
+			parent = a.start()
+			end    = -1
+		)
+		for position := left; position < len(text); {
+			r, size := utf8.DecodeRune(text[position:])
+			current, found := a.next(parent, r)
+			if !found {
+				break
+			}
+			position += size
+			if a.end(current) {
+				end = position
+				if policy == MatchShortest {
+					break
+				}
+			}
+			parent = current
+		}
+
+		if end > 0 {
+			spans = append(spans, [2]int{left, end})
+			left = end
+			continue
+		}
+		_, size := utf8.DecodeRune(text[left:])
+		left += size
+	}
+	return spans
+}
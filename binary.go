@@ -0,0 +1,124 @@
+//go:build !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// binaryFormat MarshalBinary输出格式的版本号
+const binaryFormat = 1
+
+// ErrBinaryFormat UnmarshalBinary的输入不是MarshalBinary的输出或版本不受支持
+var ErrBinaryFormat = errors.New("sensitive: unsupported binary format")
+
+// binaryState MarshalBinary保存的内容
+type binaryState struct {
+	Format int
+	// Dict 词典的双数组，格式同SaveCompiled
+	Dict    []byte
+	Meta    map[string]binaryMeta
+	Actions map[string]Action
+	Policy  MatchPolicy
+	Overlap bool
+}
+
+type binaryMeta struct {
+	Category string
+	Priority int
+	Level    int
+}
+
+// MarshalBinary 实现encoding.BinaryMarshaler，保存词典、词语的分类、优先级和
+// 等级、分类动作以及匹配策略，可以通过RPC传给其他进程或缓存到Redis等处，
+// 之后用UnmarshalBinary恢复而不必重新解析词典文件。回调、分词器、噪音等
+// 其余设置不会保存
+func (filter *Filter) MarshalBinary() ([]byte, error) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	var dict bytes.Buffer
+	if _, err := filter.compiledCopy().WriteTo(&dict); err != nil {
+		return nil, err
+	}
+	state := binaryState{
+		Format:  binaryFormat,
+		Dict:    dict.Bytes(),
+		Actions: filter.actions,
+		Policy:  filter.policy,
+		Overlap: filter.overlap,
+	}
+	if len(filter.meta) > 0 {
+		state.Meta = make(map[string]binaryMeta, len(filter.meta))
+		for word, meta := range filter.meta {
+			state.Meta[word] = binaryMeta{Category: meta.category, Priority: meta.priority, Level: meta.level}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现encoding.BinaryUnmarshaler，用MarshalBinary的输出替换
+// 当前的词典、词语信息、分类动作和匹配策略，作为一次加载记入加载状态。
+// filter为零值时(如由gob解码生成)使用New的默认设置
+func (filter *Filter) UnmarshalBinary(data []byte) error {
+	var state binaryState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return filter.recordLoad("", fmt.Errorf("%w: %v", ErrBinaryFormat, err))
+	}
+	if state.Format != binaryFormat {
+		return filter.recordLoad("", fmt.Errorf("%w: version %d", ErrBinaryFormat, state.Format))
+	}
+	da, err := ReadDoubleArray(bytes.NewReader(state.Dict))
+	if err != nil {
+		return filter.recordLoad("", err)
+	}
+
+	filter.mu.Lock()
+	if filter.trie == nil && filter.da == nil && filter.store == nil {
+		// gob解码时filter为零值，补上New设置的默认值
+		filter.noiseSet = newRuneSet(DefaultNoiseRunes)
+		filter.usernameSeps = newRuneSet(DefaultUsernameSeparators)
+		filter.maxWordLength = DefaultMaxWordLength
+		filter.maxLineLength = DefaultMaxLineLength
+		filter.maxSnapshots = DefaultMaxSnapshots
+	}
+	filter.setDict(da)
+	filter.meta = nil
+	filter.usePriority = false
+	for word, meta := range state.Meta {
+		filter.setMeta(word, func(m *wordMeta) {
+			*m = wordMeta{category: meta.Category, priority: meta.Priority, level: meta.Level}
+		})
+		if meta.Priority != 0 {
+			filter.usePriority = true
+		}
+	}
+	filter.actions = state.Actions
+	filter.policy = state.Policy
+	filter.overlap = state.Overlap
+	filter.deadlines = nil
+	filter.schedules = nil
+	filter.rebuildPrefilter()
+	filter.pending = Delta{Reset: true}
+	filter.commit()
+	filter.mu.Unlock()
+	return filter.recordLoad("", nil)
+}
+
+// GobEncode 实现gob.GobEncoder，同MarshalBinary
+func (filter *Filter) GobEncode() ([]byte, error) {
+	return filter.MarshalBinary()
+}
+
+// GobDecode 实现gob.GobDecoder，同UnmarshalBinary
+func (filter *Filter) GobDecode(data []byte) error {
+	return filter.UnmarshalBinary(data)
+}
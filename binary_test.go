@@ -0,0 +1,61 @@
+//go:build !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	src := New()
+	if err := src.Load(strings.NewReader("垃圾\n加微信|ad|2\n")); err != nil {
+		t.Fatal(err)
+	}
+	src.SetCategoryAction("ad", Action{Kind: ActionReplaceString, String: "[广告]"})
+	src.SetMatchPolicy(MatchLongest)
+
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := New()
+	dst.AddWord("旧词")
+	if err := dst.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := dst.Replace("旧词垃圾请加微信", '*'); got != "旧词**请[广告]" {
+		t.Errorf("replace after unmarshal, got %s", got)
+	}
+	if dst.Level("加微信") != 2 || !dst.Ready() {
+		t.Errorf("unmarshal meta, got level %d ready %v", dst.Level("加微信"), dst.Ready())
+	}
+
+	if err := dst.UnmarshalBinary([]byte("garbage")); !errors.Is(err, ErrBinaryFormat) {
+		t.Errorf("unmarshal garbage, got %v, expect %v", err, ErrBinaryFormat)
+	}
+}
+
+func TestGobFilter(t *testing.T) {
+	src := New()
+	src.AddWord("垃圾")
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(src); err != nil {
+		t.Fatal(err)
+	}
+	var dst *Filter
+	if err := gob.NewDecoder(&buf).Decode(&dst); err != nil {
+		t.Fatal(err)
+	}
+	if got := dst.Replace("好垃圾", '*'); got != "好**" {
+		t.Errorf("replace after gob decode, got %s", got)
+	}
+	dst.AddWord("东西")
+	if valid, _ := dst.Validate("东 西"); valid {
+		t.Errorf("decoded filter without default noise")
+	}
+}
@@ -0,0 +1,97 @@
+package sensitive
+
+import "unicode/utf8"
+
+// ReplaceBytes 和谐[]byte中的敏感词
+func ReplaceBytes(dst, text []byte, repl rune) []byte {
+	return Default().ReplaceBytes(dst, text, repl)
+}
+
+// ReplaceBytes 将text中的敏感词逐字符替换为repl，结果追加到dst后返回，
+// 传入有足够容量的dst可避免分配。直接在UTF-8字节上匹配，不转换为string或[]rune。
+// 命中按匹配策略从左到右选取、互不重叠(MatchDefault按最长匹配处理)
+func (filter *Filter) ReplaceBytes(dst, text []byte, repl rune) []byte {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	if filter.skip(string(text)) {
+		return append(dst, text...)
+	}
+	if filter.spanMode() || filter.throughNoise {
+		result, _ := filter.rewrite(string(text), Action{Kind: ActionReplace, Rune: repl})
+		return append(dst, result...)
+	}
+
+	left := 0
+	for _, span := range filter.matcher().scanBytes(text, filter.bytesPolicy()) {
+		dst = append(dst, text[left:span[0]]...)
+		for i := span[0]; i < span[1]; {
+			_, size := utf8.DecodeRune(text[i:])
+			dst = utf8.AppendRune(dst, repl)
+			i += size
+		}
+		left = span[1]
+	}
+	return append(dst, text[left:]...)
+}
+
+// FilterBytes 过滤[]byte中的敏感词
+func FilterBytes(dst, text []byte) []byte {
+	return Default().FilterBytes(dst, text)
+}
+
+// FilterBytes 删除text中的敏感词，结果追加到dst后返回，其余同ReplaceBytes
+func (filter *Filter) FilterBytes(dst, text []byte) []byte {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	if filter.skip(string(text)) {
+		return append(dst, text...)
+	}
+	if filter.spanMode() || filter.throughNoise {
+		result, _ := filter.rewrite(string(text), Action{Kind: ActionBlock})
+		return append(dst, result...)
+	}
+
+	left := 0
+	for _, span := range filter.matcher().scanBytes(text, filter.bytesPolicy()) {
+		dst = append(dst, text[left:span[0]]...)
+		left = span[1]
+	}
+	return append(dst, text[left:]...)
+}
+
+// FindInBytes 检测[]byte中的敏感词
+func FindInBytes(text []byte) (bool, string) {
+	return Default().FindInBytes(text)
+}
+
+// FindInBytes 同FindIn，直接在UTF-8字节上匹配
+func (filter *Filter) FindInBytes(text []byte) (bool, string) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	text = filter.removeNoiseBytes(text)
+	if filter.skip(string(text)) {
+		return false, ""
+	}
+	if filter.spanMode() {
+		if matches := filter.allMatches(string(text)); len(matches) > 0 {
+			return true, matches[0].Word
+		}
+		return false, ""
+	}
+
+	if spans := filter.matcher().scanBytes(text, MatchShortest); len(spans) > 0 {
+		return true, string(text[spans[0][0]:spans[0][1]])
+	}
+	return false, ""
+}
+
+// bytesPolicy []byte接口使用的匹配策略，调用方需持有锁
+func (filter *Filter) bytesPolicy() MatchPolicy {
+	if filter.policy == MatchDefault {
+		return MatchLongest
+	}
+	return filter.policy
+}
@@ -0,0 +1,35 @@
+package sensitive
+
+import "testing"
+
+func TestBytesAPI(t *testing.T) {
+	filter := New()
+	filter.AddWord("一个", "个东", "东西", "bad")
+
+	buf := make([]byte, 0, 64)
+	if got := string(filter.ReplaceBytes(buf, []byte("我有一个东东西, bad"), '*')); got != "我有**东**, ***" {
+		t.Errorf("replace bytes, got %s", got)
+	}
+	if got := string(filter.FilterBytes(nil, []byte("我有一个东东西, bad"))); got != "我有东, " {
+		t.Errorf("filter bytes, got %s", got)
+	}
+	if found, word := filter.FindInBytes([]byte("两 个东西")); !found || word != "个东" {
+		t.Errorf("findin bytes, got %v %s", found, word)
+	}
+	if found, _ := filter.FindInBytes([]byte("没有")); found {
+		t.Errorf("findin bytes clean text should not be found")
+	}
+}
+
+func BenchmarkReplaceBytes(b *testing.B) {
+	filter := New()
+	filter.LoadWordDict("./dict/dict.txt")
+	text := []byte("这篇文章真的好垃圾啊，我为长者续一秒，hello world")
+	buf := make([]byte, 0, 256)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = filter.ReplaceBytes(buf[:0], text, '*')
+	}
+}
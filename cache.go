@@ -0,0 +1,198 @@
+package sensitive
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+)
+
+// 缓存的查询种类，与文本一起组成缓存的键
+const (
+	cacheFindIn byte = iota
+	cacheFindAll
+	cacheReplace
+)
+
+// resultCache 按文本缓存查询结果的LRU缓存，词典版本变化时整体失效
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	version uint64
+	seed    maphash.Seed
+	items   map[uint64]*list.Element
+	order   *list.List
+	hits    uint64
+	misses  uint64
+}
+
+type cacheEntry struct {
+	hash  uint64
+	kind  byte
+	repl  rune
+	text  string
+	value interface{}
+}
+
+type findInResult struct {
+	found bool
+	word  string
+}
+
+// CacheStats 结果缓存的统计
+type CacheStats struct {
+	Size   int
+	Hits   uint64
+	Misses uint64
+}
+
+// SetCache 设置默认过滤器的结果缓存
+func SetCache(size int) {
+	Default().SetCache(size)
+}
+
+// SetCache 开启最多保存size条结果的LRU缓存，FindIn、FindAll和Replace对相同的
+// 文本直接返回缓存的结果，适合刷屏等大量重复文本的场景。词典以及噪音、
+// 分类动作、例外规则等影响查询结果的配置的任何修改都使缓存失效。size<=0时关闭
+func (filter *Filter) SetCache(size int) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if size <= 0 {
+		filter.cache = nil
+		return
+	}
+	filter.cache = newResultCache(size)
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{
+		size:  size,
+		seed:  maphash.MakeSeed(),
+		items: make(map[uint64]*list.Element),
+		order: list.New(),
+	}
+}
+
+// GetCacheStats 返回默认过滤器的缓存统计
+func GetCacheStats() CacheStats {
+	return Default().CacheStats()
+}
+
+// CacheStats 返回结果缓存的统计，未开启缓存时返回零值
+func (filter *Filter) CacheStats() CacheStats {
+	filter.mu.RLock()
+	cache := filter.cache
+	filter.mu.RUnlock()
+	if snap := filter.published.Load(); snap != nil {
+		// ConcurrencySnapshot模式下查询使用副本的缓存
+		cache = snap.cache
+	}
+	if cache == nil {
+		return CacheStats{}
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return CacheStats{Size: cache.order.Len(), Hits: cache.hits, Misses: cache.misses}
+}
+
+func (c *resultCache) hash(kind byte, repl rune, text string) uint64 {
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	h.WriteByte(kind)
+	h.WriteString(string(repl))
+	h.WriteString(text)
+	return h.Sum64()
+}
+
+// clear 清空缓存的结果
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[uint64]*list.Element)
+	c.order.Init()
+}
+
+// get 返回缓存的结果，version为当前的词典版本
+func (c *resultCache) get(version uint64, kind byte, repl rune, text string) (interface{}, bool) {
+	hash := c.hash(kind, repl, text)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != version {
+		c.items = make(map[uint64]*list.Element)
+		c.order.Init()
+		c.version = version
+	}
+	if elem, ok := c.items[hash]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if entry.kind == kind && entry.repl == repl && entry.text == text {
+			c.order.MoveToFront(elem)
+			c.hits++
+			return entry.value, true
+		}
+	}
+	c.misses++
+	return nil, false
+}
+
+// put 保存查询结果，version为查询时的词典版本
+func (c *resultCache) put(version uint64, kind byte, repl rune, text string, value interface{}) {
+	hash := c.hash(kind, repl, text)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != version {
+		return
+	}
+	if elem, ok := c.items[hash]; ok {
+		elem.Value = &cacheEntry{hash: hash, kind: kind, repl: repl, text: text, value: value}
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[hash] = c.order.PushFront(&cacheEntry{hash: hash, kind: kind, repl: repl, text: text, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).hash)
+	}
+}
+
+// findInCached 带缓存的findIn，调用方需持有锁
+func (filter *Filter) findInCached(text string) (bool, string) {
+	if filter.cache == nil {
+		return filter.findIn(text)
+	}
+	if v, ok := filter.cache.get(filter.version, cacheFindIn, 0, text); ok {
+		r := v.(findInResult)
+		return r.found, r.word
+	}
+	found, word := filter.findIn(text)
+	filter.cache.put(filter.version, cacheFindIn, 0, text, findInResult{found, word})
+	return found, word
+}
+
+// findAllCached 带缓存的findAll，返回的切片可由调用方修改，调用方需持有锁
+func (filter *Filter) findAllCached(text string) []string {
+	if filter.cache == nil {
+		return filter.findAll(text)
+	}
+	if v, ok := filter.cache.get(filter.version, cacheFindAll, 0, text); ok {
+		return append([]string(nil), v.([]string)...)
+	}
+	words := filter.findAll(text)
+	filter.cache.put(filter.version, cacheFindAll, 0, text, append([]string(nil), words...))
+	return words
+}
+
+// replaceCached 带缓存的replace，调用方需持有锁
+func (filter *Filter) replaceCached(text string, repl rune) string {
+	if filter.cache == nil {
+		return filter.replace(text, repl)
+	}
+	if v, ok := filter.cache.get(filter.version, cacheReplace, repl, text); ok {
+		return v.(string)
+	}
+	result := filter.replace(text, repl)
+	filter.cache.put(filter.version, cacheReplace, repl, text, result)
+	return result
+}
@@ -0,0 +1,67 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情")
+	filter.SetCache(2)
+
+	for i := 0; i < 3; i++ {
+		if found, word := filter.FindIn("色情网站"); !found || word != "色情" {
+			t.Errorf("find in, got %v %s", found, word)
+		}
+	}
+	if stats := filter.CacheStats(); stats != (CacheStats{Size: 1, Hits: 2, Misses: 1}) {
+		t.Errorf("cache stats, got %+v", stats)
+	}
+
+	// 修改缓存返回的切片不影响缓存
+	words := filter.FindAll("色情")
+	words[0] = "x"
+	if words := filter.FindAll("色情"); !reflect.DeepEqual(words, []string{"色情"}) {
+		t.Errorf("find all cached, got %v", words)
+	}
+
+	if got := filter.Replace("色情", '*'); got != "**" {
+		t.Errorf("replace, got %s", got)
+	}
+	if got := filter.Replace("色情", '#'); got != "##" {
+		t.Errorf("replace with another rune, got %s", got)
+	}
+	if stats := filter.CacheStats(); stats.Size != 2 {
+		t.Errorf("cache should be bounded, got %+v", stats)
+	}
+
+	// 词典修改后缓存失效
+	filter.AddWord("网站")
+	if words := filter.FindAll("色情网站"); !reflect.DeepEqual(words, []string{"色情", "网站"}) {
+		t.Errorf("find all after add word, got %v", words)
+	}
+
+	// 例外规则、分类动作等配置修改后缓存同样失效
+	if err := filter.AddException("网站", "", "建设"); err != nil {
+		t.Fatal(err)
+	}
+	if words := filter.FindAll("色情网站建设"); !reflect.DeepEqual(words, []string{"色情"}) {
+		t.Errorf("find all after add exception, got %v", words)
+	}
+	filter.ClearExceptions("网站")
+	if words := filter.FindAll("色情网站建设"); !reflect.DeepEqual(words, []string{"色情", "网站"}) {
+		t.Errorf("find all after clear exceptions, got %v", words)
+	}
+	filter.AddWordWithCategory("ad", "网站")
+	filter.Replace("网站", '*')
+	filter.SetCategoryAction("ad", Action{Kind: ActionAllow})
+	if got := filter.Replace("网站", '*'); got != "网站" {
+		t.Errorf("replace after set category action, got %s", got)
+	}
+
+	filter.SetCache(0)
+	if stats := filter.CacheStats(); stats != (CacheStats{}) {
+		t.Errorf("cache stats after disabled, got %+v", stats)
+	}
+}
@@ -0,0 +1,263 @@
+package sensitive
+
+import (
+	"bufio"
+	"io"
+)
+
+// wordMeta 词语的附加信息
+type wordMeta struct {
+	category string
+	priority int
+	level    int
+}
+
+// ActionKind 命中某类词语后对文本的处理方式
+type ActionKind int
+
+const (
+	// ActionReplace 将词语的每个字符替换为Action.Rune
+	ActionReplace ActionKind = iota
+	// ActionReplaceString 将整个词语替换为Action.String
+	ActionReplaceString
+	// ActionBlock 从文本中删除该词语
+	ActionBlock
+	// ActionLog 不修改文本，只交给SetLogHandler设置的回调处理
+	ActionLog
+	// ActionAllow 放行，不修改文本也不作为命中报告，用于白名单词
+	ActionAllow
+	// ActionReject 整段文本应被拒绝，词语本身按方法原本的方式处理，
+	// 见FilterWithDecision
+	ActionReject
+)
+
+// Action 命中某类词语后的处理动作
+type Action struct {
+	Kind   ActionKind
+	Rune   rune
+	String string
+}
+
+// AddWordWithCategory 添加属于某个分类的敏感词
+func AddWordWithCategory(category string, words ...string) {
+	Default().AddWordWithCategory(category, words...)
+}
+
+// AddWordWithCategory 添加属于category分类的敏感词，已存在的词会被改到该分类
+func (filter *Filter) AddWordWithCategory(category string, words ...string) {
+	words, _ = filter.intercept(MutationAdd, words)
+	filter.addWithCategory(category, words)
+}
+
+// addWithCategory 添加属于category分类的词，不经过拦截器
+func (filter *Filter) addWithCategory(category string, words []string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	filter.addWords(words...)
+	for _, word := range words {
+		filter.setMeta(word, func(meta *wordMeta) { meta.category = category })
+	}
+	filter.commit()
+}
+
+// loadCategory 同Load，词典中没有分类的词归入category，category为空时同Load
+func (filter *Filter) loadCategory(category string, rd io.Reader) error {
+	if category == "" {
+		return filter.Load(rd)
+	}
+	return filter.loadFormat(bufio.NewReader(rd), EncodingAuto, category, nil)
+}
+
+// setMeta 修改词语的附加信息，调用方需持有写锁
+func (filter *Filter) setMeta(word string, fn func(meta *wordMeta)) {
+	if filter.meta == nil {
+		filter.meta = make(map[string]wordMeta)
+	}
+	meta := filter.meta[word]
+	fn(&meta)
+	filter.meta[word] = meta
+	filter.configChanged()
+}
+
+// Category 返回词语所属的分类
+func Category(word string) string {
+	return Default().Category(word)
+}
+
+// Category 返回词语所属的分类，未设置时返回空字符串
+func (filter *Filter) Category(word string) string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.meta[word].category
+}
+
+// SetCategoryAction 设置某个分类的处理动作
+func SetCategoryAction(category string, action Action) {
+	Default().SetCategoryAction(category, action)
+}
+
+// SetCategoryAction 设置category分类的词在Replace和FilterWord中的处理动作。
+// 设置了任一分类动作后，Replace和FilterWord改为按匹配策略从左到右取
+// 互不重叠的命中(MatchDefault按最长匹配处理)，在一次遍历中对每个命中
+// 按其分类执行动作，没有分类或分类未设置动作的词按方法原本的方式处理
+func (filter *Filter) SetCategoryAction(category string, action Action) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	if filter.actions == nil {
+		filter.actions = make(map[string]Action)
+	}
+	filter.actions[category] = action
+}
+
+// SetLogHandler 设置ActionLog动作的回调
+func SetLogHandler(fn func(category string, m Match)) {
+	Default().SetLogHandler(fn)
+}
+
+// SetLogHandler 设置ActionLog动作的回调，回调在查询时同步调用，不能再调用filter的方法
+func (filter *Filter) SetLogHandler(fn func(category string, m Match)) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.logHandler = fn
+}
+
+// applyActions 对text中的命中按分类动作改写，fallback为未设置动作时的处理，调用方需持有锁
+func (filter *Filter) applyActions(text string, fallback Action) string {
+	result, _ := filter.rewrite(text, fallback)
+	return result
+}
+
+// rewrite 在一次遍历中找出命中并按分类动作改写text，返回改写后的文本和命中，
+// 调用方需持有锁
+func (filter *Filter) rewrite(text string, fallback Action) (string, []Match) {
+	text, err := filter.input(text)
+	if err != nil {
+		return "", nil
+	}
+	if filter.mergeSpans {
+		spans := filter.spans(text, fallback)
+		return filter.rewriteSpans(text, spans, fallback), spanMatches(spans)
+	}
+	if filter.throughNoise {
+		return filter.rewriteMatches(text, filter.noiseMatches(text), fallback)
+	}
+	return filter.rewriteMatches(text, filter.matches(text), fallback)
+}
+
+// rewriteMatches 按分类动作改写text中给定的互不重叠的命中，调用方需持有锁
+func (filter *Filter) rewriteMatches(text string, matches []Match, fallback Action) (string, []Match) {
+	setOffsets(text, matches)
+	var (
+		runes  = []rune(text)
+		result = make([]rune, 0, len(runes))
+		left   = 0
+	)
+	for _, m := range matches {
+		category, action := filter.actionOf(m.Word, fallback)
+		if action.Kind == ActionReject {
+			action = fallback
+		}
+
+		result = append(result, runes[left:m.Start]...)
+		left = m.End
+		switch action.Kind {
+		case ActionReplace:
+			for i := m.Start; i < m.End; i++ {
+				result = append(result, action.Rune)
+			}
+		case ActionReplaceString:
+			result = append(result, []rune(action.String)...)
+		case ActionBlock:
+		case ActionLog:
+			result = append(result, runes[m.Start:m.End]...)
+			if filter.logHandler != nil {
+				filter.logHandler(category, m)
+			}
+		}
+	}
+	return string(append(result, runes[left:]...)), matches
+}
+
+// actionOf 返回word的分类及其动作，分类未设置动作时返回fallback，调用方需持有锁
+func (filter *Filter) actionOf(word string, fallback Action) (string, Action) {
+	category := filter.meta[word].category
+	if action, ok := filter.actions[category]; ok {
+		return category, action
+	}
+	return category, fallback
+}
+
+// FilterWithDetails 和谐敏感词并返回命中
+func FilterWithDetails(text string, repl rune) (string, []Match) {
+	return Default().FilterWithDetails(text, repl)
+}
+
+// FilterWithDetails 在一次遍历中和谐敏感词并返回被处理的命中，
+// 省去先FindAll再Replace的两次遍历。命中按匹配策略从左到右选取、互不重叠
+// (MatchDefault按最长匹配处理)，设置了分类动作的词按其动作处理
+func (filter *Filter) FilterWithDetails(text string, repl rune) (string, []Match) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	if filter.skip(text) {
+		return text, nil
+	}
+	return filter.rewrite(text, Action{Kind: ActionReplace, Rune: repl})
+}
+
+// FilterWithDecision 和谐敏感词并返回命中和是否应拒绝整段文本
+func FilterWithDecision(text string, repl rune) (string, []Match, bool) {
+	return Default().FilterWithDecision(text, repl)
+}
+
+// FilterWithDecision 同FilterWithDetails，另外返回是否应拒绝整段文本：
+// 命中中有分类动作为ActionReject的词时为true，这些词在文本中按repl和谐，
+// 其余词按各自的分类动作处理。一次遍历即可同时得到和谐后的文本和拒绝结论，
+// 例如用SetCategoryAction("illegal", Action{Kind: ActionReject})区分必须拒绝的词
+// 和只需和谐的词。输入被SetMaxTextLen或SetUTF8Policy拒绝时返回空字符串并判定为拒绝
+func (filter *Filter) FilterWithDecision(text string, repl rune) (string, []Match, bool) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.filterWithDecision(text, repl)
+}
+
+// filterWithDecision FilterWithDecision的实现，调用方需持有锁
+func (filter *Filter) filterWithDecision(text string, repl rune) (string, []Match, bool) {
+	if _, err := filter.checkInput(text); err != nil {
+		return "", nil, true
+	}
+	if filter.skip(text) {
+		return text, nil, false
+	}
+	result, matches := filter.rewrite(text, Action{Kind: ActionReplace, Rune: repl})
+	return result, matches, filter.rejects(text)
+}
+
+// rejects 判断text中是否有分类动作为ActionReject的词。检查全部命中而不只是
+// 被选中处理的命中，被更长的词覆盖的拒绝词同样生效，调用方需持有锁
+func (filter *Filter) rejects(text string) bool {
+	var enabled bool
+	for _, action := range filter.actions {
+		enabled = enabled || action.Kind == ActionReject
+	}
+	if !enabled {
+		return false
+	}
+
+	text, err := filter.input(text)
+	if err != nil {
+		return true
+	}
+	if filter.throughNoise {
+		runes, _ := normalize(text, filter.noiseNormalizers())
+		text = string(runes)
+	}
+	for _, m := range filter.allMatches(text) {
+		if _, action := filter.actionOf(m.Word, Action{}); action.Kind == ActionReject {
+			return true
+		}
+	}
+	return false
+}
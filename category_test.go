@@ -0,0 +1,124 @@
+package sensitive
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCategoryAction(t *testing.T) {
+	filter := New()
+	filter.AddWordWithCategory("ad", "加微信")
+	filter.AddWordWithCategory("abuse", "傻瓜")
+	filter.AddWordWithCategory("watch", "敏感")
+	filter.AddWord("垃圾")
+
+	var logged []Match
+	filter.SetLogHandler(func(category string, m Match) {
+		if category != "watch" {
+			t.Errorf("log handler category, got %s, expect watch", category)
+		}
+		logged = append(logged, m)
+	})
+	filter.SetCategoryAction("ad", Action{Kind: ActionReplaceString, String: "[广告]"})
+	filter.SetCategoryAction("abuse", Action{Kind: ActionReplace, Rune: '#'})
+	filter.SetCategoryAction("watch", Action{Kind: ActionLog})
+
+	text := "傻瓜快加微信，垃圾敏感"
+	if got, expect := filter.Replace(text, '*'), "##快[广告]，**敏感"; got != expect {
+		t.Errorf("replace, got %s, expect %s", got, expect)
+	}
+	if got, expect := filter.FilterWord(text), "##快[广告]，敏感"; got != expect {
+		t.Errorf("filter, got %s, expect %s", got, expect)
+	}
+	if len(logged) != 2 || logged[0] != (Match{Word: "敏感", Start: 9, End: 11, ByteStart: 27, ByteEnd: 33, UTF16Start: 9, UTF16End: 11}) {
+		t.Errorf("logged matches, got %v", logged)
+	}
+
+	filter.SetCategoryAction("abuse", Action{Kind: ActionBlock})
+	if got, expect := filter.Replace("傻瓜", '*'), ""; got != expect {
+		t.Errorf("replace blocked word, got %s, expect %s", got, expect)
+	}
+	if got := filter.Category("加微信"); got != "ad" {
+		t.Errorf("category, got %s, expect ad", got)
+	}
+}
+
+func TestFilterWithDetails(t *testing.T) {
+	filter := New()
+	filter.AddWord("一个", "个东", "东西")
+
+	text, matches := filter.FilterWithDetails("我有一个东东西", '*')
+	if text != "我有**东**" {
+		t.Errorf("filter with details text, got %s, expect 我有**东**", text)
+	}
+	expect := []Match{{Word: "一个", Start: 2, End: 4, ByteStart: 6, ByteEnd: 12, UTF16Start: 2, UTF16End: 4}, {Word: "东西", Start: 5, End: 7, ByteStart: 15, ByteEnd: 21, UTF16Start: 5, UTF16End: 7}}
+	if !reflect.DeepEqual(matches, expect) {
+		t.Errorf("filter with details matches, got %v, expect %v", matches, expect)
+	}
+
+	if text, matches := filter.FilterWithDetails("没有", '*'); text != "没有" || matches != nil {
+		t.Errorf("filter with details clean text, got %s %v", text, matches)
+	}
+}
+
+func TestFilterWithDecision(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	filter.AddWordWithCategory("illegal", "违禁品")
+	filter.AddWordWithCategory("ad", "加微信")
+	filter.SetCategoryAction("illegal", Action{Kind: ActionReject})
+	filter.SetCategoryAction("ad", Action{Kind: ActionReplaceString, String: "[广告]"})
+
+	text, matches, reject := filter.FilterWithDecision("垃圾，加微信", '*')
+	if text != "**，[广告]" || len(matches) != 2 || reject {
+		t.Errorf("censor only, got %s %v %v", text, matches, reject)
+	}
+
+	text, matches, reject = filter.FilterWithDecision("加微信买违禁品", '*')
+	if text != "[广告]买***" || len(matches) != 2 || !reject {
+		t.Errorf("reject, got %s %v %v", text, matches, reject)
+	}
+
+	if text, matches, reject := filter.FilterWithDecision("没有", '*'); text != "没有" || matches != nil || reject {
+		t.Errorf("clean text, got %s %v %v", text, matches, reject)
+	}
+	if got := filter.Replace("买违禁品", '#'); got != "买###" {
+		t.Errorf("replace reject word, got %s", got)
+	}
+
+	// 被更长的词覆盖的拒绝词同样生效
+	filter.AddWordWithCategory("illegal", "法轮")
+	filter.AddWordWithCategory("spam", "法轮功")
+	if text, _, reject := filter.FilterWithDecision("他说法轮功", '*'); text != "他说***" || !reject {
+		t.Errorf("overlapped reject word, got %s %v", text, reject)
+	}
+}
+
+func TestLoadCategory(t *testing.T) {
+	filter := New()
+	filter.AddWord("旧词")
+	content := "\uFEFF# 注释\r\n 加微信 \r\n\r\n代开发票|fraud|3\r\n!旧词\r\n\\#1\r\n"
+	if err := filter.loadCategory("ad", strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{"加微信": "ad", "代开发票": "fraud", "#1": "ad"}
+	for word, expect := range cases {
+		if got := filter.Category(word); got != expect {
+			t.Errorf("category of %q, got %q, expect %q", word, got, expect)
+		}
+	}
+	if words := filter.FindAll("# 注释旧词"); len(words) != 0 {
+		t.Errorf("comment or removed word loaded, got %v", words)
+	}
+	if level := filter.Level("代开发票"); level != 3 {
+		t.Errorf("level, got %d", level)
+	}
+
+	if err := filter.loadCategory("porn", strings.NewReader(`["色情", "裸聊"]`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := filter.Category("裸聊"); got != "porn" {
+		t.Errorf("category of json dictionary, got %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package sensitive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch 词典内容与给定的SHA-256校验和不一致
+var ErrChecksumMismatch = errors.New("sensitive: checksum mismatch")
+
+// LoadNetWordDictChecksum 加载网络敏感词字典并校验
+func LoadNetWordDictChecksum(url, sum string) error {
+	return Default().LoadNetWordDictChecksum(url, sum)
+}
+
+// LoadNetWordDictChecksum 下载完整的词典并校验其十六进制SHA-256校验和sum，
+// 一致时才加载，避免下载不完整或被篡改的词典污染词库
+func (filter *Filter) LoadNetWordDictChecksum(url, sum string) error {
+	content, err := filter.fetchVerified(url, 5*time.Second, sum)
+	if err != nil {
+		return err
+	}
+	return filter.load(url, bufio.NewReader(bytes.NewReader(content)), EncodingAuto, nil)
+}
+
+// LoadChecksum 校验后加载词典
+func LoadChecksum(rd io.Reader, sum string) error {
+	return Default().LoadChecksum(rd, sum)
+}
+
+// LoadChecksum 读取rd的全部内容并校验SHA-256，一致时才加载
+func (filter *Filter) LoadChecksum(rd io.Reader, sum string) error {
+	content, err := io.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+	if err := verifySHA256(content, sum); err != nil {
+		return filter.recordLoad("", err)
+	}
+	return filter.Load(bytes.NewReader(content))
+}
+
+// fetchVerified 下载url的全部内容并校验，sum为空时不校验
+func (filter *Filter) fetchVerified(url string, timeout time.Duration, sum string) ([]byte, error) {
+	body, err := filter.fetch(url, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySHA256(content, sum); err != nil {
+		return nil, filter.recordLoad(url, fmt.Errorf("%w: %s", err, url))
+	}
+	return content, nil
+}
+
+// verifySHA256 校验content的SHA-256，sum为空时不校验
+func verifySHA256(content []byte, sum string) error {
+	if sum == "" {
+		return nil
+	}
+	digest := sha256.Sum256(content)
+	if !strings.EqualFold(hex.EncodeToString(digest[:]), sum) {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
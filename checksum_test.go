@@ -0,0 +1,49 @@
+package sensitive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sha256Hex(s string) string {
+	digest := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestLoadNetWordDictChecksum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "垃圾\n东西\n")
+	}))
+	defer srv.Close()
+
+	filter := New()
+	if err := filter.LoadNetWordDictChecksum(srv.URL, sha256Hex("垃圾\n")); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("truncated content, got %v", err)
+	}
+	if filter.trie.Has("垃圾") {
+		t.Errorf("mismatched dictionary should not be loaded")
+	}
+
+	if err := filter.LoadNetWordDictChecksum(srv.URL, strings.ToUpper(sha256Hex("垃圾\n东西\n"))); err != nil {
+		t.Fatal(err)
+	}
+	if !filter.trie.Has("东西") {
+		t.Errorf("dictionary not loaded")
+	}
+}
+
+func TestLoadChecksum(t *testing.T) {
+	filter := New()
+	if err := filter.LoadChecksum(strings.NewReader("垃圾"), "bad"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("got %v", err)
+	}
+	if err := filter.LoadChecksum(strings.NewReader("垃圾"), sha256Hex("垃圾")); err != nil || !filter.trie.Has("垃圾") {
+		t.Errorf("got %v", err)
+	}
+}
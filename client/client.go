@@ -0,0 +1,213 @@
+// Package client 调用server包提供的远程过滤服务，并在服务不可用时
+// 退回到本地加载的sensitive.Filter。
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+	"github.com/peterchanxyz/sensitive/server"
+)
+
+// Consistency 远程服务与本地过滤器之间的取舍
+type Consistency int
+
+const (
+	// RemoteFirst 以远程服务的结果为准，服务不可用时使用本地过滤器
+	RemoteFirst Consistency = iota
+	// LocalFirst 总是使用本地过滤器，适合配合Sync使本地词典与服务端保持一致
+	LocalFirst
+	// RemoteOnly 只使用远程服务，服务不可用时返回错误
+	RemoteOnly
+)
+
+// Client 远程过滤服务的客户端
+type Client struct {
+	// HTTPClient 发起请求使用的客户端，默认为http.DefaultClient
+	HTTPClient *http.Client
+	// Consistency 远程与本地的取舍，默认为RemoteFirst
+	Consistency Consistency
+
+	baseURL string
+	local   *sensitive.Filter
+}
+
+// NewClient 返回访问baseURL上的过滤服务的客户端，local为本地的后备过滤器，
+// 为nil时相当于RemoteOnly
+func NewClient(baseURL string, local *sensitive.Filter) *Client {
+	return &Client{
+		HTTPClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		local:      local,
+	}
+}
+
+// Unavailable 远程服务不可用(网络错误或5xx)时返回的错误
+type Unavailable struct {
+	Err error
+}
+
+func (e *Unavailable) Error() string {
+	return "client: service unavailable: " + e.Err.Error()
+}
+
+func (e *Unavailable) Unwrap() error {
+	return e.Err
+}
+
+// Check 检测文本是否含有敏感词
+func (c *Client) Check(ctx context.Context, text string) (*server.CheckResponse, error) {
+	if c.useLocal() {
+		return c.localCheck(text), nil
+	}
+
+	rsp := new(server.CheckResponse)
+	err := c.call(ctx, "/v1/check", &server.CheckRequest{Text: text}, rsp)
+	if c.fallback(err) {
+		return c.localCheck(text), nil
+	}
+	return rsp, err
+}
+
+// Filter 替换或删除文本中的敏感词，replacement的含义同server.FilterRequest
+func (c *Client) Filter(ctx context.Context, text, replacement string) (*server.FilterResponse, error) {
+	if c.useLocal() {
+		return c.localFilter(text, replacement), nil
+	}
+
+	rsp := new(server.FilterResponse)
+	err := c.call(ctx, "/v1/filter", &server.FilterRequest{Text: text, Replacement: replacement}, rsp)
+	if c.fallback(err) {
+		return c.localFilter(text, replacement), nil
+	}
+	return rsp, err
+}
+
+// Sync 订阅服务端的词典更新并应用到本地过滤器，直到ctx结束或连接断开。
+// 连接建立后先收到完整词典，因此重新调用Sync即可重新同步
+func (c *Client) Sync(ctx context.Context) error {
+	if c.local == nil {
+		return fmt.Errorf("client: no local filter to sync")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/watch", nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return &Unavailable{Err: err}
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return statusError(res)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		// 空行是服务端的心跳
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var delta sensitive.Delta
+		if err := json.Unmarshal(scanner.Bytes(), &delta); err != nil {
+			return err
+		}
+		delta.Apply(c.local)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return scanner.Err()
+}
+
+// KeepInSync 持续调用Sync，连接断开或服务不可用时等待retry后重新订阅，
+// 直到ctx结束。适合在后台goroutine中运行，使本地词典在数秒内跟上服务端
+func (c *Client) KeepInSync(ctx context.Context, retry time.Duration) error {
+	for {
+		err := c.Sync(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, ok := err.(*Unavailable); err != nil && !ok {
+			return err
+		}
+
+		timer := time.NewTimer(retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) useLocal() bool {
+	return c.local != nil && c.Consistency == LocalFirst
+}
+
+func (c *Client) fallback(err error) bool {
+	if err == nil || c.local == nil || c.Consistency == RemoteOnly {
+		return false
+	}
+	_, ok := err.(*Unavailable)
+	return ok
+}
+
+func (c *Client) localCheck(text string) *server.CheckResponse {
+	words := c.local.FindAll(text)
+	return &server.CheckResponse{Valid: len(words) == 0, Words: words}
+}
+
+func (c *Client) localFilter(text, replacement string) *server.FilterResponse {
+	rsp := &server.FilterResponse{Words: c.local.FindAll(text)}
+	if replacement == "" {
+		rsp.Text = c.local.FilterWord(text)
+	} else {
+		rsp.Text = c.local.Replace(text, []rune(replacement)[0])
+	}
+	return rsp
+}
+
+func (c *Client) call(ctx context.Context, path string, req, rsp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	res, err := c.HTTPClient.Do(r)
+	if err != nil {
+		return &Unavailable{Err: err}
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return statusError(res)
+	}
+	return json.NewDecoder(res.Body).Decode(rsp)
+}
+
+func statusError(res *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(res.Body).Decode(&body)
+	err := fmt.Errorf("client: %s: %s", res.Status, body.Error)
+	if res.StatusCode >= 500 {
+		return &Unavailable{Err: err}
+	}
+	return err
+}
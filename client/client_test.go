@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+	"github.com/peterchanxyz/sensitive/server"
+)
+
+func TestClientFallback(t *testing.T) {
+	remote := sensitive.New()
+	remote.AddWord("远程")
+	srv := httptest.NewServer(server.NewHandler(server.NewService(remote)))
+
+	local := sensitive.New()
+	local.AddWord("本地")
+	c := NewClient(srv.URL, local)
+
+	rsp, err := c.Check(context.Background(), "远程本地")
+	if err != nil || rsp.Valid || rsp.Words[0] != "远程" {
+		t.Errorf("remote check, got %+v %v", rsp, err)
+	}
+
+	srv.Close()
+	rsp, err = c.Check(context.Background(), "远程本地")
+	if err != nil || rsp.Valid || rsp.Words[0] != "本地" {
+		t.Errorf("fallback check, got %+v %v", rsp, err)
+	}
+
+	c.Consistency = RemoteOnly
+	if _, err := c.Check(context.Background(), "远程本地"); err == nil {
+		t.Errorf("remote only check should fail when service is down")
+	}
+}
+
+func TestClientSync(t *testing.T) {
+	remote := sensitive.New()
+	remote.AddWord("远程")
+	srv := httptest.NewServer(server.NewHandler(server.NewService(remote)))
+	defer srv.Close()
+
+	local := sensitive.New()
+	local.AddWord("本地")
+	c := NewClient(srv.URL, local)
+	c.Consistency = LocalFirst
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Sync(ctx)
+
+	remote.AddWord("新词")
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if rsp, _ := c.Filter(context.Background(), "远程新词本地", "*"); rsp.Text == "****本地" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	rsp, _ := c.Filter(context.Background(), "远程新词本地", "*")
+	t.Errorf("filter after sync, got %s, expect ****本地", rsp.Text)
+}
+
+func TestClientKeepInSync(t *testing.T) {
+	remote := sensitive.New()
+	remote.AddWord("远程")
+	srv := httptest.NewUnstartedServer(server.NewHandler(server.NewService(remote)))
+
+	local := sensitive.New()
+	c := NewClient("http://"+srv.Listener.Addr().String(), local)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- c.KeepInSync(ctx, 10*time.Millisecond) }()
+
+	// 服务端晚于客户端启动，客户端应在重试后完成同步
+	time.Sleep(30 * time.Millisecond)
+	srv.Start()
+	defer srv.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for !local.HasWord("远程") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !local.HasWord("远程") {
+		t.Fatalf("not synced, got %v", local.Words())
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("got %v, expect context.Canceled", err)
+	}
+}
@@ -0,0 +1,141 @@
+//go:build js && wasm
+
+// Command sensitive-wasm 将过滤器编译为WebAssembly，供浏览器在提交前预检用户输入，
+// 词典和匹配规则与后端完全一致。编译：
+//
+//	GOOS=js GOARCH=wasm go build -o sensitive.wasm ./cmd/sensitive-wasm
+//
+// 在页面中先引入Go自带的wasm_exec.js，运行后即可使用全局对象sensitive：
+//
+//	const go = new Go();
+//	const { instance } = await WebAssembly.instantiateStreaming(fetch("sensitive.wasm"), go.importObject);
+//	go.run(instance);
+//	sensitive.load(await (await fetch("dict.txt")).text());
+//	sensitive.findAll("...");
+//
+// 返回的位置均为rune下标，而不是JavaScript字符串的UTF-16下标
+package main
+
+import (
+	"bytes"
+	"strings"
+	"syscall/js"
+	"unicode/utf8"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+var filter = sensitive.New()
+
+func main() {
+	js.Global().Set("sensitive", js.ValueOf(map[string]interface{}{
+		"load":             js.FuncOf(load),
+		"loadCompiled":     js.FuncOf(loadCompiled),
+		"addWord":          js.FuncOf(addWord),
+		"delWord":          js.FuncOf(delWord),
+		"findIn":           js.FuncOf(findIn),
+		"findAll":          js.FuncOf(findAll),
+		"findAllWithIndex": js.FuncOf(findAllWithIndex),
+		"validate":         js.FuncOf(validate),
+		"replace":          js.FuncOf(replace),
+		"filter":           js.FuncOf(filterWord),
+	}))
+	// 阻塞，保持导出的函数可用
+	select {}
+}
+
+// load(dict: string | Uint8Array): string | null，返回错误信息
+func load(this js.Value, args []js.Value) interface{} {
+	var err error
+	if args[0].Type() == js.TypeString {
+		err = filter.Load(strings.NewReader(args[0].String()))
+	} else {
+		err = filter.Load(bytes.NewReader(goBytes(args[0])))
+	}
+	return jsError(err)
+}
+
+// loadCompiled(data: Uint8Array): string | null，加载SaveCompiled写出的词典
+func loadCompiled(this js.Value, args []js.Value) interface{} {
+	return jsError(filter.LoadCompiled(bytes.NewReader(goBytes(args[0]))))
+}
+
+func addWord(this js.Value, args []js.Value) interface{} {
+	filter.AddWord(jsStrings(args)...)
+	return nil
+}
+
+func delWord(this js.Value, args []js.Value) interface{} {
+	filter.DelWord(jsStrings(args)...)
+	return nil
+}
+
+// findIn(text): {found: boolean, word: string}
+func findIn(this js.Value, args []js.Value) interface{} {
+	found, word := filter.FindIn(args[0].String())
+	return map[string]interface{}{"found": found, "word": word}
+}
+
+// findAll(text): string[]
+func findAll(this js.Value, args []js.Value) interface{} {
+	return stringsToJS(filter.FindAll(args[0].String()))
+}
+
+// findAllWithIndex(text): {word, start, end}[]
+func findAllWithIndex(this js.Value, args []js.Value) interface{} {
+	matches := filter.FindAllWithIndex(args[0].String())
+	result := make([]interface{}, len(matches))
+	for i, m := range matches {
+		result[i] = map[string]interface{}{"word": m.Word, "start": m.Start, "end": m.End}
+	}
+	return result
+}
+
+// validate(text): {valid: boolean, word: string}
+func validate(this js.Value, args []js.Value) interface{} {
+	valid, word := filter.Validate(args[0].String())
+	return map[string]interface{}{"valid": valid, "word": word}
+}
+
+// replace(text, repl = "*"): string
+func replace(this js.Value, args []js.Value) interface{} {
+	repl := '*'
+	if len(args) > 1 {
+		repl, _ = utf8.DecodeRuneInString(args[1].String())
+	}
+	return filter.Replace(args[0].String(), repl)
+}
+
+// filter(text): string
+func filterWord(this js.Value, args []js.Value) interface{} {
+	return filter.FilterWord(args[0].String())
+}
+
+func goBytes(v js.Value) []byte {
+	b := make([]byte, v.Get("length").Int())
+	js.CopyBytesToGo(b, v)
+	return b
+}
+
+func jsStrings(args []js.Value) []string {
+	words := make([]string, len(args))
+	for i, arg := range args {
+		words[i] = arg.String()
+	}
+	return words
+}
+
+func stringsToJS(words []string) []interface{} {
+	result := make([]interface{}, len(words))
+	for i, word := range words {
+		result[i] = word
+	}
+	return result
+}
+
+func jsError(err error) interface{} {
+	if err != nil {
+		return err.Error()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+func TestWrapper(t *testing.T) {
+	if err := load(js.Undefined(), []js.Value{js.ValueOf("色情\n赌博\n")}); err != nil {
+		t.Fatalf("load, got %v", err)
+	}
+	addWord(js.Undefined(), []js.Value{js.ValueOf("毒品")})
+
+	found := js.ValueOf(findIn(js.Undefined(), []js.Value{js.ValueOf("看色情")}))
+	if !found.Get("found").Bool() || found.Get("word").String() != "色情" {
+		t.Errorf("findIn, got %v", found)
+	}
+
+	all := js.ValueOf(findAll(js.Undefined(), []js.Value{js.ValueOf("赌博和毒品")}))
+	if all.Length() != 2 || all.Index(1).String() != "毒品" {
+		t.Errorf("findAll, got %v", all)
+	}
+
+	matches := js.ValueOf(findAllWithIndex(js.Undefined(), []js.Value{js.ValueOf("看色情")}))
+	if matches.Length() != 1 || matches.Index(0).Get("start").Int() != 1 {
+		t.Errorf("findAllWithIndex, got %v", matches)
+	}
+
+	if got := replace(js.Undefined(), []js.Value{js.ValueOf("看色情"), js.ValueOf("#")}); got != "看##" {
+		t.Errorf("replace, got %v", got)
+	}
+	if got := filterWord(js.Undefined(), []js.Value{js.ValueOf("看色情")}); got != "看" {
+		t.Errorf("filter, got %v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// runDiff 比较两个词典，以"+词"和"-词"输出新增和删除的词，有差异时返回1
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 2 {
+		fmt.Fprintln(stderr, "usage: sensitive diff old.txt new.txt")
+		return 2
+	}
+
+	var filters [2]*sensitive.Filter
+	for i, path := range args {
+		filters[i] = sensitive.New()
+		if err := filters[i].LoadWordDict(path); err != nil {
+			fmt.Fprintf(stderr, "sensitive: %v\n", err)
+			return 1
+		}
+	}
+
+	diff := sensitive.Diff(filters[0], filters[1])
+	var lines []string
+	for _, word := range diff.OnlyA {
+		lines = append(lines, "-"+word)
+	}
+	for _, word := range diff.OnlyB {
+		lines = append(lines, "+"+word)
+	}
+	// 按词排序，同一个词不会同时出现在两边
+	sort.Slice(lines, func(i, j int) bool { return lines[i][1:] < lines[j][1:] })
+	for _, line := range lines {
+		fmt.Fprintln(stdout, line)
+	}
+	if len(lines) > 0 {
+		return 1
+	}
+	return 0
+}
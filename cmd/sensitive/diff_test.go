@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := writeDict(t, "色情\n赌博\n")
+	updated := writeDict(t, "赌博\n毒品\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"diff", old, updated}, &stdout, &stderr); code != 1 {
+		t.Errorf("exit code %d, stderr %q", code, stderr.String())
+	}
+	if got := stdout.String(); got != "+毒品\n-色情\n" {
+		t.Errorf("got %q", got)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"diff", old, old}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("same dictionary, got %d %q", code, stdout.String())
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// stdin 过滤命令读取的输入，测试时替换
+var stdin io.Reader = os.Stdin
+
+// lineMatches 旁路输出中一行的命中，Line从1开始，位置为rune下标
+type lineMatches struct {
+	Line    int         `json:"line"`
+	Matches []jsonMatch `json:"matches"`
+}
+
+type jsonMatch struct {
+	Word  string `json:"word"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// runFilter 逐行读取标准输入，将和谐后的行写到标准输出，
+// -matches指定文件时将每一行的命中以换行分隔的JSON写入该文件
+func runFilter(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("filter", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	replace := flags.String("replace", "*", "replacement character, empty to delete words")
+	matches := flags.String("matches", "", "write matches of each line as JSON to `file`")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: sensitive filter [-replace c] [-matches file] dict.txt...")
+		return 2
+	}
+
+	filter := sensitive.New()
+	for _, path := range flags.Args() {
+		if err := filter.LoadWordDict(path); err != nil {
+			fmt.Fprintf(stderr, "sensitive: %v\n", err)
+			return 1
+		}
+	}
+	filter.Compile()
+
+	var sidecar *json.Encoder
+	if *matches != "" {
+		f, err := os.Create(*matches)
+		if err != nil {
+			fmt.Fprintf(stderr, "sensitive: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w := bufio.NewWriter(f)
+		defer w.Flush()
+		sidecar = json.NewEncoder(w)
+	}
+
+	repl, _ := utf8.DecodeRuneInString(*replace)
+	if err := filterLines(filter, stdin, stdout, sidecar, repl); err != nil {
+		fmt.Fprintf(stderr, "sensitive: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// filterLines 逐行过滤，repl为utf8.RuneError时删除敏感词。输入暂时没有
+// 更多数据时刷新输出，既能批量写入，又不会让管道下游等待
+func filterLines(filter *sensitive.Filter, in io.Reader, out io.Writer, sidecar *json.Encoder, repl rune) error {
+	var (
+		reader = bufio.NewReaderSize(in, 64<<10)
+		writer = bufio.NewWriterSize(out, 64<<10)
+	)
+	for n := 1; ; n++ {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			text := strings.TrimSuffix(line, "\n")
+			if repl == utf8.RuneError {
+				writer.WriteString(filter.FilterWord(text))
+			} else {
+				writer.WriteString(filter.Replace(text, repl))
+			}
+			if len(text) < len(line) {
+				writer.WriteByte('\n')
+			}
+
+			if sidecar != nil {
+				if hits := filter.FindAllWithIndex(text); len(hits) > 0 {
+					record := lineMatches{Line: n}
+					for _, hit := range hits {
+						record.Matches = append(record.Matches, jsonMatch{Word: hit.Word, Start: hit.Start, End: hit.End})
+					}
+					if err := sidecar.Encode(record); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if err == io.EOF {
+			return writer.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		if reader.Buffered() == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
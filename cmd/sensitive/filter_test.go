@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	dict := writeDict(t, "色情\n赌博\n")
+	matches := filepath.Join(t.TempDir(), "matches.ndjson")
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("你好\n色情网站\r\n赌博")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"filter", "-matches", matches, dict}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr %q", code, stderr.String())
+	}
+	if got := stdout.String(); got != "你好\n**网站\r\n**" {
+		t.Errorf("got %q", got)
+	}
+
+	sidecar, _ := os.ReadFile(matches)
+	want := `{"line":2,"matches":[{"word":"色情","start":0,"end":2}]}
+{"line":3,"matches":[{"word":"赌博","start":0,"end":2}]}
+`
+	if string(sidecar) != want {
+		t.Errorf("matches, got %q", sidecar)
+	}
+
+	stdin = strings.NewReader("色情网站\n")
+	stdout.Reset()
+	run([]string{"filter", "-replace", "", dict}, &stdout, &stderr)
+	if got := stdout.String(); got != "网站\n" {
+		t.Errorf("delete, got %q", got)
+	}
+}
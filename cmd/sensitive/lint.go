@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// location 词在词典文件中首次出现的位置
+type location struct {
+	path string
+	line int
+}
+
+func (l location) String() string {
+	return fmt.Sprintf("%s:%d", l.path, l.line)
+}
+
+// runLint 检查词典中的重复词、冗余词、不合法的UTF-8和过短的词，
+// 以"文件:行号: 问题"的格式逐条输出，发现问题时返回1。
+// -fix时只输出删除冗余词后的词典，便于直接重定向覆盖原文件
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fix := flags.Bool("fix", false, "print the dictionary without redundant words")
+	minLength := flags.Int("min-length", 2, "report words shorter than `n` runes")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: sensitive lint [-fix] [-min-length n] dict.txt...")
+		return 2
+	}
+
+	var (
+		filter   = sensitive.New()
+		seen     = make(map[string]location)
+		problems int
+	)
+	report := func(at location, format string, a ...interface{}) {
+		problems++
+		if !*fix {
+			fmt.Fprintf(stdout, "%s: %s\n", at, fmt.Sprintf(format, a...))
+		}
+	}
+	for _, path := range flags.Args() {
+		err := scanDict(path, func(at location, word string) {
+			switch {
+			case !utf8.ValidString(word):
+				report(at, "invalid UTF-8: %q", word)
+			case utf8.RuneCountInString(word) < *minLength:
+				report(at, "%s: suspiciously short word", word)
+			}
+			if first, ok := seen[word]; ok {
+				report(at, "%s: duplicate of %s", word, first)
+			} else {
+				seen[word] = at
+			}
+		})
+		if err == nil {
+			err = filter.LoadWordDict(path)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "sensitive: %v\n", err)
+			return 1
+		}
+	}
+
+	redundant := filter.Redundant()
+	if *fix {
+		drop := make(map[string]bool, len(redundant))
+		for _, r := range redundant {
+			drop[r.Word] = true
+		}
+		for _, word := range filter.Words() {
+			if !drop[word] {
+				fmt.Fprintln(stdout, word)
+			}
+		}
+		return 0
+	}
+
+	for _, r := range redundant {
+		report(seen[r.Word], "%s: redundant, covered by %s", r.Word, r.By)
+	}
+	if problems > 0 {
+		return 1
+	}
+	return 0
+}
+
+// scanDict 逐行读取纯文本词典，对每个词调用fn，跳过空行、注释和删除指令
+func scanDict(path string, fn func(at location, word string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, sensitive.DefaultMaxLineLength)
+	for n := 1; scanner.Scan(); n++ {
+		if word, ok := dictWord(scanner.Text()); ok {
+			fn(location{path: path, line: n}, word)
+		}
+	}
+	return scanner.Err()
+}
+
+// dictWord 取出词典中一行的词，语法同sensitive.Load
+func dictWord(line string) (string, bool) {
+	text := strings.TrimSpace(line)
+	if text == "" || text[0] == '#' || text[0] == '!' {
+		return "", false
+	}
+	if text[0] == '\\' {
+		text = text[1:]
+	}
+	if i := strings.IndexByte(text, '|'); i >= 0 {
+		text = text[:i]
+	}
+	text = strings.TrimSpace(text)
+	return text, text != ""
+}
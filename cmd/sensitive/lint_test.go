@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDict(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "dict.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLint(t *testing.T) {
+	path := writeDict(t, "# 注释\n色情\n色情网站|porn\n赌博\n色情\n枪\n\xff\xfe\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"lint", path}, &stdout, &stderr); code != 1 {
+		t.Errorf("exit code %d, stderr %q", code, stderr.String())
+	}
+	want := strings.Join([]string{
+		path + ":5: 色情: duplicate of " + path + ":2",
+		path + ":6: 枪: suspiciously short word",
+		path + `:7: invalid UTF-8: "\xff\xfe"`,
+		path + ":3: 色情网站: redundant, covered by 色情",
+	}, "\n") + "\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"lint", "-fix", writeDict(t, "色情\n色情网站\n赌博\n")}, &stdout, &stderr); code != 0 {
+		t.Errorf("fix, exit code %d", code)
+	}
+	if got := stdout.String(); got != "色情\n赌博\n" {
+		t.Errorf("fix, got %q", got)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"lint", writeDict(t, "色情\n赌博\n")}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("clean dictionary, got %d %q", code, stdout.String())
+	}
+
+	if code := run([]string{"nope"}, &stdout, &stderr); code != 2 {
+		t.Errorf("unknown command, got %d", code)
+	}
+}
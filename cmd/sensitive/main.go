@@ -0,0 +1,52 @@
+// Command sensitive 敏感词词典的命令行工具。
+//
+// 用法：
+//
+//	sensitive lint [-fix] [-min-length n] dict.txt...
+//	                                    检查词典中的重复词、冗余词、编码错误和过短的词
+//	sensitive diff old.txt new.txt      列出新增和删除的词
+//	sensitive filter [-replace c] [-matches file] dict.txt...
+//	                                    逐行和谐标准输入并写到标准输出
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// command 一个子命令，返回进程退出码
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"lint":   runLint,
+	"filter": runFilter,
+	"diff":   runDiff,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "sensitive: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return cmd(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: sensitive <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	fmt.Fprintln(w, "  lint    report problems in dictionaries")
+	fmt.Fprintln(w, "  diff    list words added and removed between dictionaries")
+	fmt.Fprintln(w, "  filter  censor lines read from stdin")
+}
@@ -0,0 +1,99 @@
+package sensitive
+
+import (
+	"sync"
+	"time"
+)
+
+// coalescer 合并一段时间内的AddWord、DelWord，到期后在一次加锁中统一应用
+type coalescer struct {
+	window time.Duration
+
+	mu    sync.Mutex
+	ops   []queuedOp
+	timer *time.Timer
+	// flushMu 保证各批修改按入队顺序应用
+	flushMu sync.Mutex
+}
+
+type queuedOp struct {
+	op    MutationOp
+	words []string
+}
+
+// SetCoalesce 设置默认过滤器合并修改的时间窗口
+func SetCoalesce(window time.Duration) {
+	Default().SetCoalesce(window)
+}
+
+// SetCoalesce 开启后AddWord、DelWord不立即修改词典，而是排队并在window后
+// 一次性应用：每个时间窗口最多加一次写锁、版本号只加一，避免聊天机器人等
+// 客户端频繁修改时持续占用写锁、阻塞查询。排队中的修改对查询不可见，
+// 可调用FlushMutations立即应用。拦截器在入队前调用。window不大于0时关闭，
+// 并立即应用排队中的修改。TryAddWord、AddWordWithCategory等其他修改方法不受影响
+func (filter *Filter) SetCoalesce(window time.Duration) {
+	var c *coalescer
+	if window > 0 {
+		c = &coalescer{window: window}
+	}
+	if old := filter.coalesce.Swap(c); old != nil {
+		old.flush(filter)
+	}
+}
+
+// FlushMutations 立即应用默认过滤器排队中的修改
+func FlushMutations() {
+	Default().FlushMutations()
+}
+
+// FlushMutations 立即应用SetCoalesce排队中的修改，返回时之前的AddWord、
+// DelWord均已生效
+func (filter *Filter) FlushMutations() {
+	if c := filter.coalesce.Load(); c != nil {
+		c.flush(filter)
+	}
+}
+
+// enqueue 开启了合并时将修改排队并返回true，words被复制，调用方之后可以复用
+func (filter *Filter) enqueue(op MutationOp, words []string) bool {
+	c := filter.coalesce.Load()
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ops = append(c.ops, queuedOp{op: op, words: append([]string(nil), words...)})
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.window, func() { c.flush(filter) })
+	}
+	return true
+}
+
+// flush 在一次加锁中按顺序应用排队的修改
+func (c *coalescer) flush(filter *Filter) {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	ops := c.ops
+	c.ops = nil
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mu.Unlock()
+	if len(ops) == 0 {
+		return
+	}
+
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	for _, q := range ops {
+		if q.op == MutationDel {
+			filter.delWords(q.words...)
+		} else {
+			filter.addWords(q.words...)
+		}
+	}
+	filter.commit()
+}
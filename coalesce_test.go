@@ -0,0 +1,58 @@
+package sensitive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoalesce(t *testing.T) {
+	filter := New()
+	filter.SetCoalesce(time.Hour)
+
+	version := filter.Version()
+	filter.AddWord("垃圾", "东西")
+	filter.DelWord("东西")
+	filter.AddWord("坏")
+	if filter.HasWord("垃圾") || filter.Version() != version {
+		t.Fatalf("queued mutations are visible")
+	}
+
+	// 排队后调用方复用切片不影响排队的修改
+	words := []string{"坏"}
+	filter.AddWord(words...)
+	words[0] = "好"
+
+	filter.FlushMutations()
+	if filter.HasWord("好") {
+		t.Errorf("queued words aliased the caller's slice")
+	}
+	if !filter.HasWord("垃圾") || !filter.HasWord("坏") || filter.HasWord("东西") {
+		t.Errorf("flushed words, got %v", filter.Words())
+	}
+	if got := filter.Version(); got != version+1 {
+		t.Errorf("version after flush, got %d, expect %d", got, version+1)
+	}
+
+	// 时间窗口到期后自动应用
+	filter.SetCoalesce(10 * time.Millisecond)
+	filter.AddWord("新词")
+	deadline := time.Now().Add(time.Second)
+	for !filter.HasWord("新词") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !filter.HasWord("新词") {
+		t.Errorf("queued word not applied after window")
+	}
+
+	// 关闭时应用排队中的修改
+	filter.SetCoalesce(time.Hour)
+	filter.DelWord("新词")
+	filter.SetCoalesce(0)
+	if filter.HasWord("新词") {
+		t.Errorf("pending mutation not applied when disabled")
+	}
+	filter.AddWord("立即")
+	if !filter.HasWord("立即") {
+		t.Errorf("add word after disabling coalesce")
+	}
+}
@@ -0,0 +1,151 @@
+package sensitive
+
+import (
+	"sort"
+	"sync"
+)
+
+// SourcedMatch 组合过滤器中的一次命中，Source为命中所在的过滤器名称
+type SourcedMatch struct {
+	Match
+	Source string
+}
+
+// CompositeFilter 组合多个过滤器，一次调用检查全部过滤器，如全局词典、
+// 租户词典和监管要求的词典。各过滤器按添加顺序检查，保留各自的配置
+type CompositeFilter struct {
+	mu      sync.RWMutex
+	names   []string
+	filters []*Filter
+}
+
+// NewCompositeFilter 返回一个空的组合过滤器
+func NewCompositeFilter() *CompositeFilter {
+	return &CompositeFilter{}
+}
+
+// Add 添加名为name的过滤器，同名的过滤器被替换，顺序不变
+func (cf *CompositeFilter) Add(name string, filter *Filter) {
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+	for i := range cf.names {
+		if cf.names[i] == name {
+			// 复制后再替换，不影响members已返回的列表
+			cf.filters = append([]*Filter(nil), cf.filters...)
+			cf.filters[i] = filter
+			return
+		}
+	}
+	cf.names = append(cf.names, name)
+	cf.filters = append(cf.filters, filter)
+}
+
+// Remove 移除名为name的过滤器，不存在时返回false
+func (cf *CompositeFilter) Remove(name string) bool {
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+	for i := range cf.names {
+		if cf.names[i] == name {
+			cf.names = append(cf.names[:i:i], cf.names[i+1:]...)
+			cf.filters = append(cf.filters[:i:i], cf.filters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Filter 返回名为name的过滤器，不存在时返回nil
+func (cf *CompositeFilter) Filter(name string) *Filter {
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+	for i := range cf.names {
+		if cf.names[i] == name {
+			return cf.filters[i]
+		}
+	}
+	return nil
+}
+
+// members 返回当前的过滤器列表，之后的Add、Remove不影响返回值
+func (cf *CompositeFilter) members() ([]string, []*Filter) {
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+	return cf.names, cf.filters
+}
+
+// FindIn 依次检测各过滤器，返回第一个命中的词及其所在的过滤器
+func (cf *CompositeFilter) FindIn(text string) (found bool, word, source string) {
+	names, filters := cf.members()
+	for i, filter := range filters {
+		if found, word := filter.FindIn(text); found {
+			return true, word, names[i]
+		}
+	}
+	return false, "", ""
+}
+
+// Validate 检测字符串是否合法，不合法时返回第一个命中的词及其所在的过滤器
+func (cf *CompositeFilter) Validate(text string) (valid bool, word, source string) {
+	names, filters := cf.members()
+	for i, filter := range filters {
+		if valid, word := filter.Validate(text); !valid {
+			return false, word, names[i]
+		}
+	}
+	return true, "", ""
+}
+
+// FindAll 找到所有过滤器中的匹配词，按过滤器顺序合并去重
+func (cf *CompositeFilter) FindAll(text string) []string {
+	var (
+		words []string
+		set   = make(map[string]struct{})
+	)
+	_, filters := cf.members()
+	for _, filter := range filters {
+		for _, word := range filter.FindAll(text) {
+			if _, ok := set[word]; !ok {
+				set[word] = struct{}{}
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// FindAllWithIndex 找到所有过滤器中的匹配词及其位置，按起点、终点升序排列，
+// 不同过滤器的命中可能重叠
+func (cf *CompositeFilter) FindAllWithIndex(text string) []SourcedMatch {
+	var matches []SourcedMatch
+	names, filters := cf.members()
+	for i, filter := range filters {
+		for _, m := range filter.FindAllWithIndex(text) {
+			matches = append(matches, SourcedMatch{Match: m, Source: names[i]})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End < matches[j].End
+	})
+	return matches
+}
+
+// Replace 依次用各过滤器和谐敏感词
+func (cf *CompositeFilter) Replace(text string, repl rune) string {
+	_, filters := cf.members()
+	for _, filter := range filters {
+		text = filter.Replace(text, repl)
+	}
+	return text
+}
+
+// FilterWord 依次用各过滤器过滤敏感词
+func (cf *CompositeFilter) FilterWord(text string) string {
+	_, filters := cf.members()
+	for _, filter := range filters {
+		text = filter.FilterWord(text)
+	}
+	return text
+}
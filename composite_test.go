@@ -0,0 +1,51 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompositeFilter(t *testing.T) {
+	global, tenant := New(), New()
+	global.AddWord("色情", "赌博")
+	tenant.AddWord("竞品", "赌博")
+
+	cf := NewCompositeFilter()
+	cf.Add("global", global)
+	cf.Add("tenant", tenant)
+
+	if found, word, source := cf.FindIn("推荐竞品"); !found || word != "竞品" || source != "tenant" {
+		t.Errorf("find in, got %v %s %s", found, word, source)
+	}
+	if valid, _, _ := cf.Validate("你好"); !valid {
+		t.Errorf("validate clean text")
+	}
+	if words := cf.FindAll("竞品赌博色情"); !reflect.DeepEqual(words, []string{"赌博", "色情", "竞品"}) {
+		t.Errorf("find all, got %v", words)
+	}
+
+	expect := []SourcedMatch{
+		{Match{Word: "竞品", Start: 0, End: 2, ByteStart: 0, ByteEnd: 6, UTF16Start: 0, UTF16End: 2}, "tenant"},
+		{Match{Word: "赌博", Start: 2, End: 4, ByteStart: 6, ByteEnd: 12, UTF16Start: 2, UTF16End: 4}, "global"},
+		{Match{Word: "赌博", Start: 2, End: 4, ByteStart: 6, ByteEnd: 12, UTF16Start: 2, UTF16End: 4}, "tenant"},
+	}
+	if got := cf.FindAllWithIndex("竞品赌博"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("find all with index, got %v, expect %v", got, expect)
+	}
+	if got := cf.Replace("竞品和色情", '*'); got != "**和**" {
+		t.Errorf("replace, got %s", got)
+	}
+	if got := cf.FilterWord("竞品和色情"); got != "和" {
+		t.Errorf("filter word, got %s", got)
+	}
+
+	if !cf.Remove("tenant") || cf.Remove("tenant") {
+		t.Errorf("remove tenant")
+	}
+	if cf.Filter("global") != global || cf.Filter("tenant") != nil {
+		t.Errorf("filter by name after remove")
+	}
+	if found, _, _ := cf.FindIn("竞品"); found {
+		t.Errorf("removed filter should not be checked")
+	}
+}
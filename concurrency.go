@@ -0,0 +1,94 @@
+package sensitive
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrencyMode 查询与修改之间的并发控制方式
+type ConcurrencyMode int
+
+const (
+	// ConcurrencyLock 查询加读锁，修改加写锁，为默认方式
+	ConcurrencyLock ConcurrencyMode = iota
+	// ConcurrencySnapshot 发布一份只读副本，FindIn、FindAll、FindAllWithIndex、
+	// Validate、Replace和FilterWord通过atomic.Pointer读取副本，完全不加锁。
+	// 修改词典或配置时撤下副本，由后台在snapshotDelay之后按最新的词典和配置
+	// 重建，期间的查询仍加读锁，一段时间内的多次修改只重建一次；只修改配置时
+	// 复用上次编译的词典。适合读远多于写的场景，其余查询仍加读锁。
+	// 使用NewWithStore的自定义存储时不发布副本，查询始终加读锁
+	ConcurrencySnapshot
+)
+
+// filterMutex 释放写锁前可调用publish的读写锁
+type filterMutex struct {
+	sync.RWMutex
+	// publish 非nil时在释放写锁前调用，只在持有写锁时修改
+	publish func()
+	// dirty 持有写锁期间词典或配置有修改，只在持有写锁时访问
+	dirty bool
+	// scheduled 已安排重建副本、尚未开始
+	scheduled atomic.Bool
+}
+
+// snapshotDelay ConcurrencySnapshot模式下修改后重建副本的延迟，这段时间内的
+// 修改合并为一次重建
+const snapshotDelay = 10 * time.Millisecond
+
+// Unlock 有修改时调用publish，然后释放写锁
+func (m *filterMutex) Unlock() {
+	if m.publish != nil && m.dirty {
+		m.publish()
+	}
+	m.dirty = false
+	m.RWMutex.Unlock()
+}
+
+// SetConcurrency 设置默认过滤器的并发控制方式
+func SetConcurrency(mode ConcurrencyMode) {
+	Default().SetConcurrency(mode)
+}
+
+// SetConcurrency 设置查询与修改之间的并发控制方式，两种方式的对比见
+// BenchmarkConcurrentReaders
+func (filter *Filter) SetConcurrency(mode ConcurrencyMode) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if mode == ConcurrencySnapshot {
+		filter.mu.publish = filter.publish
+		filter.mu.dirty = true
+		return
+	}
+	filter.mu.publish = nil
+	filter.published.Store(nil)
+}
+
+// configChanged 标记影响查询结果的配置已修改并清空结果缓存，调用方需持有写锁
+func (filter *Filter) configChanged() {
+	filter.mu.dirty = true
+	if filter.cache != nil {
+		filter.cache.clear()
+	}
+}
+
+// publish 撤下已发布的副本，并安排在snapshotDelay之后重建，调用方需持有写锁
+func (filter *Filter) publish() {
+	filter.published.Store(nil)
+	if filter.store != nil || filter.mu.scheduled.Swap(true) {
+		return
+	}
+	time.AfterFunc(snapshotDelay, filter.republish)
+}
+
+// republish 按当前的词典和配置重建并发布副本。持有读锁期间不会有修改，
+// 之后的修改会撤下这份副本并重新安排重建
+func (filter *Filter) republish() {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	filter.mu.scheduled.Store(false)
+	if filter.mu.publish == nil || filter.store != nil {
+		return
+	}
+	filter.published.Store(filter.frozen())
+}
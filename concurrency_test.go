@@ -0,0 +1,195 @@
+package sensitive
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrencySnapshot(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	filter.SetConcurrency(ConcurrencySnapshot)
+	if filter.published.Load() != nil {
+		t.Errorf("published while holding the write lock")
+	}
+	waitPublished(t, filter)
+
+	if got := filter.Replace("好垃圾", '*'); got != "好**" {
+		t.Errorf("snapshot replace, got %s", got)
+	}
+	filter.AddWord("东西")
+	if found, word := filter.FindIn("东西"); !found || word != "东西" {
+		t.Errorf("snapshot after add, got %v %s", found, word)
+	}
+	filter.SetMatchPolicy(MatchShortest)
+	filter.AddWord("东西南北")
+	if got := filter.FindAll("东西南北"); len(got) != 1 || got[0] != "东西" {
+		t.Errorf("snapshot after setting policy, got %v", got)
+	}
+	if stats := filter.Stats(); stats.Queries != 3 || stats.Hits != 3 {
+		t.Errorf("snapshot stats, got %+v", stats)
+	}
+
+	// 没有修改时释放写锁不重新发布
+	published := waitPublished(t, filter)
+	filter.mu.Lock()
+	filter.mu.Unlock()
+	if filter.published.Load() != published {
+		t.Errorf("republished without changes")
+	}
+	filter.SetSkipLinks(true)
+	if snap := waitPublished(t, filter); snap == published || !snap.skipLinks {
+		t.Errorf("config change not published")
+	}
+	if filter.published.Load().da != published.da {
+		t.Errorf("config change compiled the dictionary again")
+	}
+
+	filter.SetConcurrency(ConcurrencyLock)
+	if filter.published.Load() != nil {
+		t.Errorf("snapshot still published")
+	}
+	if valid, _ := filter.Validate("垃圾"); valid {
+		t.Errorf("validate after switching back")
+	}
+}
+
+func TestConcurrencySnapshotRace(t *testing.T) {
+	filter := New()
+	filter.SetConcurrency(ConcurrencySnapshot)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				filter.Replace("有东西", '*')
+				filter.FindAllWithIndex("有东西")
+			}
+		}()
+	}
+	for j := 0; j < 50; j++ {
+		filter.AddWord(fmt.Sprint("词", j))
+		filter.DelWord("东西")
+		filter.AddWord("东西")
+	}
+	wg.Wait()
+}
+
+func TestConcurrencySnapshotBatch(t *testing.T) {
+	filter := New()
+	filter.SetConcurrency(ConcurrencySnapshot)
+	waitPublished(t, filter)
+
+	for i := 0; i < 100; i++ {
+		filter.AddWord(fmt.Sprint("词", i))
+		// 重建完成前的查询加读锁，仍能看到刚加入的词
+		if found, _ := filter.FindIn(fmt.Sprint("有词", i)); !found {
+			t.Fatalf("word %d not found before republishing", i)
+		}
+	}
+	snap := waitPublished(t, filter)
+	if !snap.da.Has("词99") {
+		t.Errorf("republished snapshot misses the last word")
+	}
+}
+
+func TestConcurrencySnapshotStore(t *testing.T) {
+	filter := NewWithStore(mapStore{})
+	filter.AddWord("垃圾")
+	filter.SetConcurrency(ConcurrencySnapshot)
+	time.Sleep(2 * snapshotDelay)
+	if filter.published.Load() != nil {
+		t.Errorf("published a snapshot of a custom store")
+	}
+	if found, _ := filter.FindIn("垃圾"); !found {
+		t.Errorf("store filter in snapshot mode")
+	}
+}
+
+// TestConcurrencyModes 两种并发控制方式下各项功能的查询结果相同
+func TestConcurrencyModes(t *testing.T) {
+	for _, c := range featureCases {
+		locked, snapshot := c.filter(), c.filter()
+		snapshot.SetConcurrency(ConcurrencySnapshot)
+		if c.new == nil {
+			waitPublished(t, snapshot)
+		}
+		for _, text := range c.texts {
+			want, got := queryResults(locked, text), queryResults(snapshot, text)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s %q: snapshot %v, lock %v", c.name, text, got, want)
+			}
+		}
+	}
+}
+
+// waitPublished 等待后台重建并发布副本
+func waitPublished(t *testing.T, filter *Filter) *Filter {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if snap := filter.published.Load(); snap != nil {
+			return snap
+		}
+	}
+	t.Fatalf("snapshot not published")
+	return nil
+}
+
+// BenchmarkConcurrentReaders 比较两种并发控制方式下，8、32、128个goroutine
+// 并发查询、同时另有一个goroutine每毫秒修改一次词典时的查询开销
+func BenchmarkConcurrentReaders(b *testing.B) {
+	modes := []struct {
+		name string
+		mode ConcurrencyMode
+	}{
+		{"rwmutex", ConcurrencyLock},
+		{"snapshot", ConcurrencySnapshot},
+	}
+	for _, m := range modes {
+		for _, readers := range []int{8, 32, 128} {
+			b.Run(fmt.Sprintf("%s/readers=%d", m.name, readers), func(b *testing.B) {
+				filter := benchFilter()
+				filter.SetConcurrency(m.mode)
+
+				done := make(chan struct{})
+				go func() {
+					ticker := time.NewTicker(time.Millisecond)
+					defer ticker.Stop()
+					for i := 0; ; i++ {
+						select {
+						case <-done:
+							return
+						case <-ticker.C:
+							filter.AddWord(fmt.Sprint("bench", i))
+						}
+					}
+				}()
+
+				var wg sync.WaitGroup
+				b.ReportAllocs()
+				b.ResetTimer()
+				for r := 0; r < readers; r++ {
+					n := b.N / readers
+					if r < b.N%readers {
+						n++
+					}
+					wg.Add(1)
+					go func(n int) {
+						defer wg.Done()
+						for i := 0; i < n; i++ {
+							filter.Replace(benchText, '*')
+						}
+					}(n)
+				}
+				wg.Wait()
+				b.StopTimer()
+				close(done)
+			})
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package sensitive
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedConfig 配置文件格式不受支持
+var ErrUnsupportedConfig = errors.New("sensitive: unsupported config format")
+
+// Config 过滤器的配置，由NewFromConfig从JSON文件读取
+type Config struct {
+	// Sources 词典来源，按顺序加载
+	Sources []SourceConfig `json:"sources"`
+	// Noise FindIn、Validate使用的噪音正则，为空时使用默认值
+	Noise string `json:"noise"`
+	// NoisePresets 追加的内置噪音预设，如whitespace、punctuation、invisible
+	NoisePresets []string `json:"noise_presets"`
+	// Separators 匹配时忽略的分隔字符，见SetSeparators
+	Separators string `json:"separators"`
+	// Normalizers 归一化器名称，只用于NewPipelineFromConfig，
+	// 可选case、width、space
+	Normalizers []string `json:"normalizers"`
+	// Policy 匹配策略，可选default、longest、shortest
+	Policy        string `json:"policy"`
+	Overlap       bool   `json:"overlap"`
+	Prefilter     bool   `json:"prefilter"`
+	SkipLinks     bool   `json:"skip_links"`
+	MaxWordLength int    `json:"max_word_length"`
+	// Compile 加载完成后编译为双数组Trie
+	Compile bool `json:"compile"`
+	// Categories 各分类的处理动作
+	Categories map[string]CategoryConfig `json:"categories"`
+}
+
+// SourceConfig 一个词典来源，File、URL、Words三者取其一
+type SourceConfig struct {
+	// File 词典文件路径，相对路径相对于配置文件所在目录
+	File string `json:"file"`
+	URL  string `json:"url"`
+	// Timeout URL的超时时间，如"5s"，默认5秒
+	Timeout string `json:"timeout"`
+	// SHA256 File或URL内容的十六进制SHA-256校验和，为空时不校验
+	SHA256 string `json:"sha256"`
+	// Words 直接写在配置中的词
+	Words []string `json:"words"`
+	// Category 来源中的词所属的分类
+	Category string `json:"category"`
+}
+
+// CategoryConfig 分类的处理动作
+type CategoryConfig struct {
+	// Action 可选replace、replace_string、block、log、allow、reject
+	Action string `json:"action"`
+	// Replacement replace时为替换字符(默认'*')，replace_string时为替换文本
+	Replacement string `json:"replacement"`
+}
+
+// NewFromConfig 按配置文件新建过滤器
+func NewFromConfig(path string) (*Filter, error) {
+	config, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.Filter()
+}
+
+// NewPipelineFromConfig 按配置文件新建Pipeline，在过滤器之前应用配置中的归一化器
+func NewPipelineFromConfig(path string) (*Pipeline, error) {
+	config, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.Pipeline()
+}
+
+// ReadConfig 读取配置文件，目前只支持JSON
+func ReadConfig(path string) (*Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedConfig, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("sensitive: parse config %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i := range config.Sources {
+		if file := config.Sources[i].File; file != "" && !filepath.IsAbs(file) {
+			config.Sources[i].File = filepath.Join(dir, file)
+		}
+	}
+	return config, nil
+}
+
+// Filter 按配置新建过滤器并加载全部词典来源
+func (config *Config) Filter() (*Filter, error) {
+	filter := New()
+	if config.Noise != "" {
+		if err := filter.UpdateNoisePattern(config.Noise); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, preset := range config.NoisePresets {
+		if err := filter.AddNoisePreset(preset); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, preset)
+		}
+	}
+
+	policy, err := parsePolicy(config.Policy)
+	if err != nil {
+		return nil, err
+	}
+	filter.SetMatchPolicy(policy)
+	filter.SetOverlap(config.Overlap)
+	filter.SetSkipLinks(config.SkipLinks)
+	filter.SetSeparators(config.Separators)
+	if config.MaxWordLength != 0 {
+		filter.SetMaxWordLength(config.MaxWordLength)
+	}
+
+	for category, c := range config.Categories {
+		action, err := c.action()
+		if err != nil {
+			return nil, fmt.Errorf("sensitive: category %s: %w", category, err)
+		}
+		filter.SetCategoryAction(category, action)
+	}
+
+	for _, source := range config.Sources {
+		if err := source.load(filter); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Prefilter {
+		filter.EnablePrefilter(true)
+	}
+	if config.Compile {
+		filter.Compile()
+	}
+	return filter, nil
+}
+
+// Pipeline 按配置新建过滤器，并在其前面加上配置中的归一化器
+func (config *Config) Pipeline() (*Pipeline, error) {
+	filter, err := config.Filter()
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewPipelineBuilder()
+	for _, name := range config.Normalizers {
+		switch name {
+		case "case":
+			b.Normalize(CaseFolder)
+		case "width":
+			b.Normalize(WidthFolder)
+		case "space":
+			b.Normalize(SpaceRemover)
+		default:
+			return nil, fmt.Errorf("sensitive: unknown normalizer %q", name)
+		}
+	}
+	for category, c := range config.Categories {
+		action, _ := c.action()
+		b.Action(category, action)
+	}
+	return b.Match(filter).Build()
+}
+
+func parsePolicy(name string) (MatchPolicy, error) {
+	switch name {
+	case "", "default":
+		return MatchDefault, nil
+	case "longest":
+		return MatchLongest, nil
+	case "shortest":
+		return MatchShortest, nil
+	}
+	return MatchDefault, fmt.Errorf("sensitive: unknown match policy %q", name)
+}
+
+func (c CategoryConfig) action() (Action, error) {
+	switch c.Action {
+	case "", "replace":
+		r := '*'
+		if c.Replacement != "" {
+			r, _ = utf8.DecodeRuneInString(c.Replacement)
+		}
+		return Action{Kind: ActionReplace, Rune: r}, nil
+	case "replace_string":
+		return Action{Kind: ActionReplaceString, String: c.Replacement}, nil
+	case "block":
+		return Action{Kind: ActionBlock}, nil
+	case "log":
+		return Action{Kind: ActionLog}, nil
+	case "allow":
+		return Action{Kind: ActionAllow}, nil
+	case "reject":
+		return Action{Kind: ActionReject}, nil
+	}
+	return Action{}, fmt.Errorf("unknown action %q", c.Action)
+}
+
+// read 读取词典来源的全部内容并校验
+func (source SourceConfig) read(filter *Filter) ([]byte, error) {
+	switch {
+	case source.File != "":
+		content, err := os.ReadFile(source.File)
+		if err != nil {
+			return nil, filter.recordLoad(source.File, err)
+		}
+		if err := verifySHA256(content, source.SHA256); err != nil {
+			return nil, filter.recordLoad(source.File, fmt.Errorf("%w: %s", err, source.File))
+		}
+		return content, nil
+	case source.URL != "":
+		timeout := 5 * time.Second
+		if source.Timeout != "" {
+			d, err := time.ParseDuration(source.Timeout)
+			if err != nil {
+				return nil, err
+			}
+			timeout = d
+		}
+		return filter.fetchVerified(source.URL, timeout, source.SHA256)
+	}
+	return []byte(strings.Join(source.Words, "\n")), nil
+}
+
+// load 将来源中的词加入过滤器
+func (source SourceConfig) load(filter *Filter) error {
+	content, err := source.read(filter)
+	if err != nil {
+		return err
+	}
+	if err := filter.loadCategory(source.Category, bytes.NewReader(content)); err != nil {
+		return err
+	}
+	// 不带分类的来源已由Load记录
+	if source.Category != "" {
+		filter.recordLoad(source.name(), nil)
+	}
+	return nil
+}
+
+// name 来源的文件路径或URL，内联的词语返回空
+func (source SourceConfig) name() string {
+	if source.File != "" {
+		return source.File
+	}
+	return source.URL
+}
@@ -0,0 +1,74 @@
+package sensitive
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "words.txt"), []byte("垃圾\n东西\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewFromConfig(t *testing.T) {
+	path := writeConfig(t, "sensitive.json", `{
+		"sources": [
+			{"file": "words.txt"},
+			{"words": ["# 广告", " 加微信 "], "category": "ad"}
+		],
+		"policy": "longest",
+		"compile": true,
+		"categories": {"ad": {"action": "replace_string", "replacement": "[广告]"}}
+	}`)
+
+	filter, err := NewFromConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filter.Replace("垃圾东西，请加微信", '*'); got != "****，请[广告]" {
+		t.Errorf("replace, got %s", got)
+	}
+	if filter.Category("加微信") != "ad" {
+		t.Errorf("category not applied")
+	}
+	if filter.Category("# 广告") != "" {
+		t.Errorf("comment loaded as a word")
+	}
+}
+
+func TestNewPipelineFromConfig(t *testing.T) {
+	path := writeConfig(t, "sensitive.json", `{
+		"sources": [{"words": ["bad"]}],
+		"normalizers": ["width", "case"]
+	}`)
+
+	p, err := NewPipelineFromConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Run("ＢＡＤ guy").Text; got != "*** guy" {
+		t.Errorf("run, got %s", got)
+	}
+}
+
+func TestNewFromConfigErrors(t *testing.T) {
+	if _, err := NewFromConfig(writeConfig(t, "sensitive.yaml", "sources: []")); !errors.Is(err, ErrUnsupportedConfig) {
+		t.Errorf("yaml config, got %v", err)
+	}
+	if _, err := NewFromConfig(writeConfig(t, "sensitive.json", `{"policy": "random"}`)); err == nil {
+		t.Errorf("unknown policy should fail")
+	}
+	if _, err := NewFromConfig(writeConfig(t, "sensitive.json", `{"sources": [{"file": "missing.txt"}]}`)); err == nil {
+		t.Errorf("missing file should fail")
+	}
+}
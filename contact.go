@@ -0,0 +1,135 @@
+package sensitive
+
+import "strings"
+
+// ContactRule 号码规则，号码长度在[MinLen, MaxLen]之间，且以Prefixes之一开头
+// (Prefixes为空表示不限)时命中
+type ContactRule struct {
+	Name     string
+	MinLen   int
+	MaxLen   int
+	Prefixes []string
+}
+
+// DefaultContactRules 默认的号码规则：手机号和QQ号，按顺序匹配
+var DefaultContactRules = []ContactRule{
+	{Name: "phone", MinLen: 11, MaxLen: 11, Prefixes: []string{"13", "14", "15", "16", "17", "18", "19"}},
+	{Name: "qq", MinLen: 5, MaxLen: 11, Prefixes: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+}
+
+// ContactMatch 一次号码命中，Number为归一化后的数字串，
+// Start和End为原文中的rune区间，左闭右开
+type ContactMatch struct {
+	Rule   string
+	Number string
+	Start  int
+	End    int
+}
+
+// maxDigitGap 号码中允许连续出现的分隔字符个数
+const maxDigitGap = 2
+
+var digitRunes = map[rune]rune{
+	'〇': '0', '零': '0',
+	'一': '1', '壹': '1', '幺': '1',
+	'二': '2', '贰': '2', '两': '2',
+	'三': '3', '叁': '3',
+	'四': '4', '肆': '4',
+	'五': '5', '伍': '5',
+	'六': '6', '陆': '6',
+	'七': '7', '柒': '7',
+	'八': '8', '捌': '8',
+	'九': '9', '玖': '9',
+}
+
+// digitBlocks 连续排列1到9的各种带圈、带括号等数字
+var digitBlocks = []rune{0x2460, 0x2474, 0x2488, 0x2776, 0x2780, 0x278A}
+
+// NormalizeDigit 将各种写法的数字归一化为ASCII数字，包括全角数字、
+// 中文数字(含大写)、带圈和带括号的数字等，不是数字时返回false
+func NormalizeDigit(r rune) (rune, bool) {
+	switch {
+	case r >= '0' && r <= '9':
+		return r, true
+	case r >= '０' && r <= '９':
+		return '0' + r - '０', true
+	case r == '⓪' || r == '⓿':
+		return '0', true
+	}
+	for _, first := range digitBlocks {
+		if r >= first && r < first+9 {
+			return '1' + r - first, true
+		}
+	}
+	d, ok := digitRunes[r]
+	return d, ok
+}
+
+// isDigitSeparator 判断号码中间可以跳过的分隔字符
+func isDigitSeparator(r rune) bool {
+	return strings.ContainsRune(" \t-_.,/~·、，。—－＿．／～|*+", r)
+}
+
+// FindContacts 找出文本中的联系方式号码
+func FindContacts(text string, rules ...ContactRule) []ContactMatch {
+	if len(rules) == 0 {
+		rules = DefaultContactRules
+	}
+
+	var (
+		results []ContactMatch
+		runes   = []rune(text)
+	)
+	for i := 0; i < len(runes); {
+		d, ok := NormalizeDigit(runes[i])
+		if !ok {
+			i++
+			continue
+		}
+
+		var (
+			start  = i
+			end    = i + 1
+			digits = []rune{d}
+			gap    = 0
+		)
+		for j := i + 1; j < len(runes); j++ {
+			if d, ok := NormalizeDigit(runes[j]); ok {
+				digits = append(digits, d)
+				end = j + 1
+				gap = 0
+				continue
+			}
+			if gap < maxDigitGap && isDigitSeparator(runes[j]) {
+				gap++
+				continue
+			}
+			break
+		}
+		i = end
+
+		number := string(digits)
+		for _, rule := range rules {
+			if rule.matchNumber(number) {
+				results = append(results, ContactMatch{Rule: rule.Name, Number: number, Start: start, End: end})
+				break
+			}
+		}
+	}
+	return results
+}
+
+func (rule ContactRule) matchNumber(number string) bool {
+	if len(number) < rule.MinLen || (rule.MaxLen > 0 && len(number) > rule.MaxLen) {
+		return false
+	}
+	if len(rule.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range rule.Prefixes {
+		if strings.HasPrefix(number, prefix) {
+			return true
+		}
+	}
+	return false
+}
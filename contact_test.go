@@ -0,0 +1,29 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindContacts(t *testing.T) {
+	testcases := []struct {
+		Text   string
+		Expect []ContactMatch
+	}{
+		{"加我一叁八⑧⑧⑧８-８８８八", []ContactMatch{{"phone", "13888888888", 2, 14}}},
+		{"扣扣：⒈⒉3 4 5 6 7", []ContactMatch{{"qq", "1234567", 3, 14}}},
+		{"一个两个三个", nil},
+		{"价格100元", nil},
+	}
+
+	for _, tc := range testcases {
+		if got := FindContacts(tc.Text); !reflect.DeepEqual(got, tc.Expect) {
+			t.Errorf("find contacts %s, got %v, expect %v", tc.Text, got, tc.Expect)
+		}
+	}
+
+	rule := ContactRule{Name: "short", MinLen: 3, MaxLen: 3}
+	if got := FindContacts("价格100元", rule); len(got) != 1 || got[0].Number != "100" {
+		t.Errorf("find contacts with custom rule, got %v", got)
+	}
+}
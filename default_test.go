@@ -0,0 +1,27 @@
+package sensitive
+
+import "testing"
+
+func TestSetDefault(t *testing.T) {
+	old := Default()
+	defer SetDefault(old)
+
+	filter := New()
+	filter.AddWord("色情")
+	SetDefault(filter)
+	if Default() != filter {
+		t.Errorf("default filter not replaced")
+	}
+	if found, word := FindIn("色情网站"); !found || word != "色情" {
+		t.Errorf("package find in after set default, got %v %s", found, word)
+	}
+
+	SetDefault(nil)
+	if Default() != filter {
+		t.Errorf("set default nil should be ignored")
+	}
+	AddWord("赌博")
+	if !filter.HasWord("赌博") || old.HasWord("赌博") {
+		t.Errorf("package add word should modify the new default filter")
+	}
+}
@@ -0,0 +1,145 @@
+package sensitive
+
+import "sort"
+
+// Delta 一次词典增量更新，可直接用JSON等格式在服务间传输。
+// Reset为true时表示整个词典被替换，Added为替换后的全部词语
+type Delta struct {
+	Version uint64   `json:"version,omitempty"`
+	Reset   bool     `json:"reset,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Apply 将增量应用到filter上
+func (delta Delta) Apply(filter *Filter) {
+	if delta.Reset {
+		filter.ResetWords(delta.Added...)
+		return
+	}
+	filter.ApplyDelta(delta.Added, delta.Removed)
+}
+
+// ApplyDelta 原子地应用一次增量更新
+func ApplyDelta(added, removed []string) {
+	Default().ApplyDelta(added, removed)
+}
+
+// ApplyDelta 在同一次加锁中删除removed并添加added，查询方不会看到
+// 只应用了一半的词典。同时出现在两边的词最终保留。版本号只加一
+func (filter *Filter) ApplyDelta(added, removed []string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	filter.delWords(removed...)
+	filter.addWords(added...)
+	filter.commit()
+}
+
+// ResetWords 用words替换整个词典
+func ResetWords(words ...string) {
+	Default().ResetWords(words...)
+}
+
+// ResetWords 在同一次加锁中清空词典并添加words
+func (filter *Filter) ResetWords(words ...string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	filter.setCompiled(nil)
+	if filter.store != nil {
+		filter.resetStore(nil)
+	} else {
+		filter.trie = NewTrie()
+	}
+	filter.deadlines = nil
+	filter.schedules = nil
+	filter.meta = nil
+	filter.rebuildPrefilter()
+	filter.mutable().Add(words...)
+	if filter.prefilter != nil {
+		for _, word := range words {
+			filter.prefilter.addWord(word)
+		}
+	}
+	filter.pending = Delta{Reset: true}
+	filter.commit()
+}
+
+// commit 完成一次修改：版本号加一，没有定时启用或删除的词时停止定时器，
+// 并将累积的修改通知给Watch的回调，调用方需持有写锁
+func (filter *Filter) commit() {
+	filter.version++
+	filter.mu.dirty = true
+	filter.stopIdleTimer()
+	delta := filter.pending
+	filter.pending = Delta{}
+	if len(filter.watchers) == 0 {
+		return
+	}
+
+	delta.Version = filter.version
+	if delta.Reset {
+		delta.Added, delta.Removed = nil, nil
+		filter.matcher().Walk(func(word string) bool {
+			delta.Added = append(delta.Added, word)
+			return true
+		})
+	}
+	for _, id := range sortedWatcherIDs(filter.watchers) {
+		filter.watchers[id](delta)
+	}
+}
+
+func sortedWatcherIDs(watchers map[int]func(Delta)) []int {
+	ids := make([]int, 0, len(watchers))
+	for id := range watchers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Watch 订阅词典的修改
+func Watch(fn func(Delta)) (cancel func()) {
+	return Default().Watch(fn)
+}
+
+// Watch 订阅词典的修改，每次修改完成后以Delta的形式同步调用fn，
+// Delta.Version为修改后的版本号。fn在持有写锁时调用，应尽快返回且
+// 不能再调用filter的方法。返回的cancel用于取消订阅
+func (filter *Filter) Watch(fn func(Delta)) (cancel func()) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	if filter.watchers == nil {
+		filter.watchers = make(map[int]func(Delta))
+	}
+	id := filter.nextWatcher
+	filter.nextWatcher++
+	filter.watchers[id] = fn
+
+	return func() {
+		filter.mu.Lock()
+		defer filter.mu.Unlock()
+		delete(filter.watchers, id)
+	}
+}
+
+// Words 返回词典中的全部词语
+func Words() []string {
+	return Default().Words()
+}
+
+// Words 按字典序返回词典中的全部词语
+func (filter *Filter) Words() []string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	var words []string
+	filter.matcher().Walk(func(word string) bool {
+		words = append(words, word)
+		return true
+	})
+	return words
+}
@@ -0,0 +1,65 @@
+package sensitive
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyDelta(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾", "东西")
+	version := filter.Version()
+
+	var delta Delta
+	if err := json.Unmarshal([]byte(`{"added":["物体","东西"],"removed":["垃圾","东西"]}`), &delta); err != nil {
+		t.Fatal(err)
+	}
+	delta.Apply(filter)
+
+	if got := filter.Replace("垃圾东西物体", '*'); got != "垃圾****" {
+		t.Errorf("replace after delta, got %s, expect 垃圾****", got)
+	}
+	if got := filter.Version(); got != version+1 {
+		t.Errorf("version after delta, got %d, expect %d", got, version+1)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	filter := New()
+	var deltas []Delta
+	cancel := filter.Watch(func(delta Delta) {
+		deltas = append(deltas, delta)
+	})
+
+	filter.AddWord("垃圾")
+	filter.ApplyDelta([]string{"东西"}, []string{"垃圾"})
+	filter.ResetWords("物体")
+	cancel()
+	filter.AddWord("不通知")
+
+	expect := []Delta{
+		{Version: 1, Added: []string{"垃圾"}},
+		{Version: 2, Added: []string{"东西"}, Removed: []string{"垃圾"}},
+		{Version: 3, Reset: true, Added: []string{"物体"}},
+	}
+	if !reflect.DeepEqual(deltas, expect) {
+		t.Errorf("watch deltas, got %+v, expect %+v", deltas, expect)
+	}
+	if got := filter.Words(); !reflect.DeepEqual(got, []string{"不通知", "物体"}) {
+		t.Errorf("words, got %v", got)
+	}
+}
+
+func TestWatchLoad(t *testing.T) {
+	filter := New()
+	var deltas []Delta
+	filter.Watch(func(delta Delta) {
+		deltas = append(deltas, delta)
+	})
+
+	filter.LoadBytes([]byte("垃圾\n东西\n"))
+	if len(deltas) != 1 || !reflect.DeepEqual(deltas[0].Added, []string{"垃圾", "东西"}) {
+		t.Errorf("load should notify loaded words, got %+v", deltas)
+	}
+}
@@ -0,0 +1,43 @@
+package sensitive
+
+// DetailedMatch 一个词在文本中的全部命中及其分类和等级
+type DetailedMatch struct {
+	Word     string
+	Category string
+	// Level 词的等级，表示严重程度，见Level
+	Level int
+	// Positions 该词在原文中的各次命中，按出现顺序排列
+	Positions []Match
+}
+
+// FindAllDetailed 找到所有匹配词及其分类和等级
+func FindAllDetailed(text string) []DetailedMatch {
+	return Default().FindAllDetailed(text)
+}
+
+// FindAllDetailed 同FindAllWithIndex，将命中按词分组，按首次出现的顺序返回，
+// 并附上词的分类和等级，下游的策略引擎无需再逐个查询Category和Level
+func (filter *Filter) FindAllDetailed(text string) []DetailedMatch {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	matches := filter.findAllWithIndex(text)
+	filter.count(text, len(matches) > 0)
+	setOffsets(text, matches)
+
+	var (
+		details []DetailedMatch
+		index   = make(map[string]int)
+	)
+	for _, m := range matches {
+		i, ok := index[m.Word]
+		if !ok {
+			meta := filter.meta[m.Word]
+			i = len(details)
+			index[m.Word] = i
+			details = append(details, DetailedMatch{Word: m.Word, Category: meta.category, Level: meta.level})
+		}
+		details[i].Positions = append(details[i].Positions, m)
+	}
+	return details
+}
@@ -0,0 +1,29 @@
+package sensitive
+
+import "testing"
+
+func TestFindAllDetailed(t *testing.T) {
+	filter := New()
+	if err := filter.LoadBytes([]byte("垃圾\n加微信|ad|3\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := filter.FindAllDetailed("加微信，垃圾，再加微信")
+	if len(got) != 2 {
+		t.Fatalf("detailed matches, got %+v", got)
+	}
+	ad := got[0]
+	if ad.Word != "加微信" || ad.Category != "ad" || ad.Level != 3 || len(ad.Positions) != 2 {
+		t.Errorf("first detail, got %+v", ad)
+	}
+	if p := ad.Positions[1]; p.Start != 8 || p.End != 11 || p.ByteStart != 24 {
+		t.Errorf("second position, got %+v", p)
+	}
+	if plain := got[1]; plain.Word != "垃圾" || plain.Category != "" || plain.Level != 0 || len(plain.Positions) != 1 {
+		t.Errorf("second detail, got %+v", plain)
+	}
+
+	if got := filter.FindAllDetailed("干净"); got != nil {
+		t.Errorf("clean text, got %+v", got)
+	}
+}
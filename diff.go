@@ -0,0 +1,60 @@
+package sensitive
+
+import "sort"
+
+// DictDiff 两个过滤器词典的差异，各列表均按字典序排列
+type DictDiff struct {
+	// OnlyA 只在a中的词
+	OnlyA []string
+	// OnlyB 只在b中的词
+	OnlyB []string
+	// Common 两边都有的词
+	Common []string
+}
+
+// Equal 两个词典是否相同
+func (d DictDiff) Equal() bool {
+	return len(d.OnlyA) == 0 && len(d.OnlyB) == 0
+}
+
+// Delta 返回把a的词典变为b的增量
+func (d DictDiff) Delta() Delta {
+	return Delta{Added: d.OnlyB, Removed: d.OnlyA}
+}
+
+// Diff 比较a和b的词典，如同一词典的两个版本。两个过滤器分别加读锁取出
+// 全部词语，a与b可以是同一个过滤器
+func Diff(a, b *Filter) DictDiff {
+	var (
+		wa   = dictWords(a)
+		wb   = dictWords(b)
+		diff DictDiff
+		i, j int
+	)
+	for i < len(wa) && j < len(wb) {
+		switch {
+		case wa[i] < wb[j]:
+			diff.OnlyA = append(diff.OnlyA, wa[i])
+			i++
+		case wa[i] > wb[j]:
+			diff.OnlyB = append(diff.OnlyB, wb[j])
+			j++
+		default:
+			diff.Common = append(diff.Common, wa[i])
+			i++
+			j++
+		}
+	}
+	diff.OnlyA = append(diff.OnlyA, wa[i:]...)
+	diff.OnlyB = append(diff.OnlyB, wb[j:]...)
+	return diff
+}
+
+// dictWords 返回按字典序排列的全部词语，自定义存储的遍历顺序不确定
+func dictWords(filter *Filter) []string {
+	words := filter.Words()
+	if !sort.StringsAreSorted(words) {
+		sort.Strings(words)
+	}
+	return words
+}
@@ -0,0 +1,33 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a, b := New(), New()
+	a.AddWord("垃圾", "东西", "旧词")
+	b.AddWord("垃圾", "东西", "新词", "另一个")
+
+	diff := Diff(a, b)
+	expect := DictDiff{
+		OnlyA:  []string{"旧词"},
+		OnlyB:  []string{"另一个", "新词"},
+		Common: []string{"东西", "垃圾"},
+	}
+	if !reflect.DeepEqual(diff, expect) {
+		t.Errorf("diff, got %+v, expect %+v", diff, expect)
+	}
+	if diff.Equal() {
+		t.Errorf("diff of different dicts is equal")
+	}
+
+	diff.Delta().Apply(a)
+	if diff := Diff(a, b); !diff.Equal() || len(diff.Common) != 4 {
+		t.Errorf("diff after applying delta, got %+v", diff)
+	}
+	if diff := Diff(a, a); !diff.Equal() {
+		t.Errorf("diff with itself, got %+v", diff)
+	}
+}
@@ -0,0 +1,94 @@
+package sensitive
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoDictFiles 目录中没有匹配的词典文件
+var ErrNoDictFiles = errors.New("sensitive: no dictionary files matched")
+
+// LoadWordDictDir 加载目录中的全部词典文件
+func LoadWordDictDir(dir, pattern string) error {
+	return Default().LoadWordDictDir(dir, pattern)
+}
+
+// LoadWordDictDir 按文件名顺序加载dir中所有匹配pattern(如"*.txt"，语法同
+// filepath.Match)的词典文件，遇到第一个错误时停止，之前的文件已经加载
+func (filter *Filter) LoadWordDictDir(dir, pattern string) error {
+	return filter.loadDir(dir, pattern, false)
+}
+
+// LoadCategoryDir 按分类加载目录中的全部词典文件
+func LoadCategoryDir(dir, pattern string) error {
+	return Default().LoadCategoryDir(dir, pattern)
+}
+
+// LoadCategoryDir 同LoadWordDictDir，另外以去掉扩展名的文件名作为其中的词
+// 的分类，如ad.txt中的词归入"ad"分类，每个分类维护一个文件即可。
+// 行内已用"词|分类"指定了分类的词保留其分类，"!词"只作用于同一个文件
+func (filter *Filter) LoadCategoryDir(dir, pattern string) error {
+	return filter.loadDir(dir, pattern, true)
+}
+
+func (filter *Filter) loadDir(dir, pattern string, byName bool) error {
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return err
+	}
+
+	loaded := 0
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if byName {
+			err = filter.loadCategoryFile(path)
+		} else {
+			err = filter.LoadWordDict(path)
+		}
+		if err != nil {
+			return err
+		}
+		loaded++
+	}
+	if loaded == 0 {
+		return fmt.Errorf("%w: %s", ErrNoDictFiles, filepath.Join(dir, pattern))
+	}
+	return nil
+}
+
+// loadCategoryFile 加载path，文件中没有分类的词归入以文件名命名的分类
+func (filter *Filter) loadCategoryFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return filter.recordLoad(path, err)
+	}
+	defer f.Close()
+
+	filter.mu.RLock()
+	tmp := New()
+	tmp.maxLineLength = filter.maxLineLength
+	filter.mu.RUnlock()
+
+	filter.beginLoad(path)
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if err := tmp.loadFormat(bufio.NewReader(f), EncodingAuto, name, nil); err != nil {
+		return filter.recordLoad(path, err)
+	}
+
+	byCategory := make(map[string][]string)
+	tmp.trie.Walk(func(word string) bool {
+		category := tmp.meta[word].category
+		byCategory[category] = append(byCategory[category], word)
+		return true
+	})
+	for category, words := range byCategory {
+		filter.addWithCategory(category, words)
+	}
+	return filter.recordLoad(path, nil)
+}
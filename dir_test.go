@@ -0,0 +1,60 @@
+package sensitive
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeDictDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadWordDictDir(t *testing.T) {
+	dir := writeDictDir(t, map[string]string{
+		"ad.txt":    "加微信\n代开发票|fraud\n",
+		"porn.txt":  "# 注释\n色情\n",
+		"readme.md": "不是词典\n",
+		"empty.txt": "",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "sub.txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := New()
+	if err := filter.LoadWordDictDir(dir, "*.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if words := filter.FindAll("加微信看色情，不是词典"); !reflect.DeepEqual(words, []string{"加微信", "色情"}) {
+		t.Errorf("find all after loading dir, got %v", words)
+	}
+	if state := filter.LoadState(); state.Status != LoadReady {
+		t.Errorf("load state after loading dir, got %v", state.Status)
+	}
+
+	filter = New()
+	if err := filter.LoadCategoryDir(dir, "*.txt"); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{"加微信": "ad", "代开发票": "fraud", "色情": "porn", "注释": ""}
+	for word, expect := range cases {
+		if got := filter.Category(word); got != expect {
+			t.Errorf("category of %s, got %q, expect %q", word, got, expect)
+		}
+	}
+
+	if err := filter.LoadWordDictDir(dir, "*.csv"); !errors.Is(err, ErrNoDictFiles) {
+		t.Errorf("load dir without matches, got %v", err)
+	}
+	if err := filter.LoadWordDictDir(dir, "["); err == nil {
+		t.Errorf("load dir with bad pattern should fail")
+	}
+}
@@ -0,0 +1,82 @@
+package sensitive
+
+import (
+	"strconv"
+	"strings"
+)
+
+// addPlainLine 解析并加入纯文本词典中的一行，没有分类的词归入category，
+// 调用方需持有写锁：
+//   - 空行和以#开头的注释行被跳过
+//   - "!词"从词典中删除该词
+//   - "词|分类|等级"加入词并设置分类和等级，分类和等级均可省略
+//   - 行首的\用于转义，如"\#1"表示词"#1"
+func (filter *Filter) addPlainLine(line, category string, report *LoadReport) {
+	text := strings.TrimSpace(line)
+	switch {
+	case text == "":
+		if report != nil {
+			report.Lines++
+			report.Empty++
+		}
+		return
+	case text[0] == '#':
+		if report != nil {
+			report.Lines++
+			report.Comments++
+		}
+		return
+	case text[0] == '!':
+		if word := strings.TrimSpace(text[1:]); word != "" {
+			filter.delWords(word)
+		}
+		if report != nil {
+			report.Lines++
+			report.Removed++
+		}
+		return
+	case text[0] == '\\':
+		line = text[1:]
+	}
+
+	if !strings.Contains(line, "|") {
+		filter.addLine(line, category, report)
+		return
+	}
+
+	fields := strings.Split(line, "|")
+	var (
+		word  = strings.TrimSpace(fields[0])
+		level int
+	)
+	if len(fields) > 1 && strings.TrimSpace(fields[1]) != "" {
+		category = strings.TrimSpace(fields[1])
+	}
+	if len(fields) > 2 && strings.TrimSpace(fields[2]) != "" {
+		var err error
+		if level, err = strconv.Atoi(strings.TrimSpace(fields[2])); err != nil || len(fields) > 3 {
+			if report != nil {
+				report.Lines++
+				report.Malformed++
+			}
+			return
+		}
+	}
+
+	filter.addLine(word, category, report)
+	if level != 0 && filter.matcher().Has(word) {
+		filter.setMeta(word, func(meta *wordMeta) { meta.level = level })
+	}
+}
+
+// Level 返回词语的等级
+func Level(word string) int {
+	return Default().Level(word)
+}
+
+// Level 返回词典文件中"词|分类|等级"为词语设置的等级，未设置时为0
+func (filter *Filter) Level(word string) int {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.meta[word].level
+}
@@ -0,0 +1,51 @@
+package sensitive
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDirectives(t *testing.T) {
+	filter := New()
+	filter.AddWord("旧词")
+
+	content := `# 广告类
+加微信|ad|3
+刷单 | ad
+垃圾
+
+!旧词
+\#1
+\!重要
+坏|ad|x
+`
+	report, err := filter.LoadWithReport(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.trie.Has("旧词") {
+		t.Errorf("!旧词 should remove the word")
+	}
+	for _, word := range []string{"加微信", "刷单", "垃圾", "#1", "!重要"} {
+		if !filter.trie.Has(word) {
+			t.Errorf("missing %s", word)
+		}
+	}
+	if filter.trie.Has("坏") {
+		t.Errorf("malformed line should be skipped")
+	}
+	if filter.Category("加微信") != "ad" || filter.Level("加微信") != 3 || filter.Category("刷单") != "ad" {
+		t.Errorf("directive not applied")
+	}
+	expect := LoadReport{Lines: 9, Added: 5, Empty: 1, Comments: 1, Removed: 1, Malformed: 1}
+	if *report != expect {
+		t.Errorf("got %+v, expect %+v", *report, expect)
+	}
+
+	// Load同样支持注释和指令
+	filter = New()
+	filter.LoadBytes([]byte("# comment\n垃圾|spam\n"))
+	if filter.trie.Has("# comment") || filter.Category("垃圾") != "spam" {
+		t.Errorf("load should parse directives")
+	}
+}
@@ -0,0 +1,75 @@
+package sensitive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// dumpDOT 以Graphviz格式输出词典结构，maxDepth<=0时不限深度。
+// 词的结束节点画成双圈
+func dumpDOT[S any](a automaton[S], children func(S) []rune, w io.Writer, maxDepth int) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph trie {")
+	fmt.Fprintln(bw, "\tnode [shape=circle];")
+	fmt.Fprintln(bw, "\tn0 [label=\"root\", shape=box];")
+
+	type item struct {
+		id    int
+		state S
+		depth int
+	}
+	var (
+		queue = []item{{0, a.start(), 0}}
+		ids   = 1
+	)
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+		if maxDepth > 0 && it.depth >= maxDepth {
+			continue
+		}
+
+		for _, r := range children(it.state) {
+			child, _ := a.next(it.state, r)
+			label := strconv.Quote(string(r))
+			if a.end(child) {
+				fmt.Fprintf(bw, "\tn%d [label=%s, shape=doublecircle];\n", ids, label)
+			} else {
+				fmt.Fprintf(bw, "\tn%d [label=%s];\n", ids, label)
+			}
+			fmt.Fprintf(bw, "\tn%d -> n%d;\n", it.id, ids)
+			queue = append(queue, item{ids, child, it.depth + 1})
+			ids++
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// DumpDOT 以Graphviz格式输出Trie的前maxDepth层，maxDepth<=0时输出整棵树
+func (tree *Trie) DumpDOT(w io.Writer, maxDepth int) error {
+	return dumpDOT[*Node](tree, func(node *Node) []rune {
+		return sortedKeys(node.Children)
+	}, w, maxDepth)
+}
+
+// DumpDOT 以Graphviz格式输出双数组的前maxDepth层，maxDepth<=0时全部输出
+func (da *DoubleArray) DumpDOT(w io.Writer, maxDepth int) error {
+	return dumpDOT[int32](da, da.children, w, maxDepth)
+}
+
+func (da *DoubleArray) children(state int32) []rune {
+	var runes []rune
+	if da.base[state] == 0 {
+		return nil
+	}
+	for _, r := range da.alphabet {
+		if _, ok := da.next(state, r); ok {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
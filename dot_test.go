@@ -0,0 +1,41 @@
+package sensitive
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDumpDOT(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情", "色")
+
+	expect := `digraph trie {
+	node [shape=circle];
+	n0 [label="root", shape=box];
+	n1 [label="色", shape=doublecircle];
+	n0 -> n1;
+	n2 [label="情", shape=doublecircle];
+	n1 -> n2;
+}
+`
+	var buf bytes.Buffer
+	if err := filter.DumpDOT(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expect {
+		t.Errorf("dump dot, got\n%s\nexpect\n%s", buf.String(), expect)
+	}
+
+	filter.Compile()
+	buf.Reset()
+	filter.DumpDOT(&buf, 0)
+	if buf.String() != expect {
+		t.Errorf("dump compiled dot, got\n%s\nexpect\n%s", buf.String(), expect)
+	}
+
+	buf.Reset()
+	filter.DumpDOT(&buf, 1)
+	if bytes.Contains(buf.Bytes(), []byte("情")) {
+		t.Errorf("dump dot with depth 1 should not contain second level, got\n%s", buf.String())
+	}
+}
@@ -0,0 +1,383 @@
+package sensitive
+
+import "sort"
+
+// DoubleArray 双数组Trie，由Trie编译而来，只读。
+// 每个状态只占用base和check两个int32以及一个结束标记，
+// 内存占用只有基于指针和map的Trie的几分之一，查询结果与Trie完全一致
+type DoubleArray struct {
+	// alphabet 词典中出现过的全部字符，升序排列，字符的编码为其下标+1
+	alphabet []rune
+	base     []int32
+	// check 保存父状态+1，0表示该位置空闲
+	check []int32
+	ends  []uint64
+	// mapping 由OpenDoubleArray内存映射时对应的文件内容
+	mapping []byte
+	// codes BMP字符到编码的直接映射表，由withCodeTable生成，非nil时代替二分查找
+	codes []uint16
+}
+
+// NewDoubleArray 由Trie构建双数组Trie
+func NewDoubleArray(tree *Trie) *DoubleArray {
+	da := &DoubleArray{}
+
+	set := make(map[rune]struct{})
+	collectRunes(tree.Root, set)
+	da.alphabet = make([]rune, 0, len(set))
+	for r := range set {
+		da.alphabet = append(da.alphabet, r)
+	}
+	sort.Slice(da.alphabet, func(i, j int) bool { return da.alphabet[i] < da.alphabet[j] })
+
+	b := &builder{da: da, next: []int32{0}, prev: []int32{0}}
+	da.base = []int32{0}
+	da.check = []int32{-1}
+	da.ends = []uint64{0}
+	b.grow(len(da.alphabet) + 1)
+
+	type item struct {
+		state int32
+		node  *Node
+	}
+	queue := []item{{0, tree.Root}}
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+
+		if it.node.IsPathEnd() {
+			da.setEnd(it.state)
+		}
+		if len(it.node.Children) == 0 {
+			continue
+		}
+
+		keys := sortedKeys(it.node.Children)
+		codes := make([]int32, len(keys))
+		for i, r := range keys {
+			codes[i] = da.code(r)
+		}
+
+		base := b.findBase(codes)
+		da.base[it.state] = base
+		for i, c := range codes {
+			child := base + c
+			da.check[child] = it.state + 1
+			b.occupy(child)
+			queue = append(queue, item{child, it.node.Children[keys[i]]})
+		}
+	}
+
+	da.shrink()
+	return da
+}
+
+func collectRunes(node *Node, set map[rune]struct{}) {
+	for r, child := range node.Children {
+		set[r] = struct{}{}
+		collectRunes(child, set)
+	}
+}
+
+// builder 构建期间用双向链表维护空闲位置，查找base时只需遍历空闲位置
+type builder struct {
+	da   *DoubleArray
+	next []int32
+	prev []int32
+	// cursor 多子节点查找的起点，之前的空闲位置大多已无法容纳多个子节点
+	cursor int32
+}
+
+// head 空闲链表的哨兵为下标0(根节点，永不空闲)
+func (b *builder) grow(size int) {
+	da := b.da
+	old := len(da.check)
+	if size <= old {
+		return
+	}
+	if size < 2*old {
+		size = 2 * old
+	}
+	da.base = append(da.base, make([]int32, size-old)...)
+	da.check = append(da.check, make([]int32, size-old)...)
+	da.ends = append(da.ends, make([]uint64, (size+63)/64-len(da.ends))...)
+	b.next = append(b.next, make([]int32, size-old)...)
+	b.prev = append(b.prev, make([]int32, size-old)...)
+
+	tail := b.prev[0]
+	for i := int32(old); i < int32(size); i++ {
+		if i == 0 {
+			continue
+		}
+		b.next[tail] = i
+		b.prev[i] = tail
+		tail = i
+	}
+	b.next[tail] = 0
+	b.prev[0] = tail
+}
+
+func (b *builder) occupy(pos int32) {
+	b.next[b.prev[pos]] = b.next[pos]
+	b.prev[b.next[pos]] = b.prev[pos]
+}
+
+// findBase 找到一个base，使base+codes中的所有位置都空闲
+func (b *builder) findBase(codes []int32) int32 {
+	pos := b.next[0]
+	tries := 0
+	if len(codes) > 1 && b.cursor > pos {
+		for int(b.cursor) < len(b.da.check) && b.da.check[b.cursor] != 0 {
+			b.cursor++
+		}
+		if int(b.cursor) < len(b.da.check) {
+			pos = b.cursor
+		}
+	}
+	for ; ; tries++ {
+		if pos == 0 {
+			// 没有空闲位置了，扩容后从新增部分开始找
+			pos = int32(len(b.da.check))
+			b.grow(len(b.da.check) + 1)
+		}
+		// base可以为负，0保留用于表示没有子节点
+		base := pos - codes[0]
+		if base != 0 {
+			b.grow(int(base + codes[len(codes)-1] + 1))
+			free := true
+			for _, c := range codes[1:] {
+				if b.da.check[base+c] != 0 {
+					free = false
+					break
+				}
+			}
+			if free {
+				if len(codes) > 1 && tries > 32 {
+					b.cursor = pos
+				}
+				return base
+			}
+		}
+		pos = b.next[pos]
+	}
+}
+
+func (da *DoubleArray) shrink() {
+	size := len(da.check)
+	for size > 1 && da.check[size-1] == 0 {
+		size--
+	}
+	da.base = append([]int32(nil), da.base[:size]...)
+	da.check = append([]int32(nil), da.check[:size]...)
+	da.ends = append([]uint64(nil), da.ends[:(size+63)/64]...)
+}
+
+func (da *DoubleArray) setEnd(state int32) {
+	da.ends[state/64] |= 1 << (uint(state) % 64)
+}
+
+// code 返回字符的编码，不在字母表中时返回0
+func (da *DoubleArray) code(r rune) int32 {
+	if da.codes != nil && r >= 0 && r < 1<<16 {
+		return int32(da.codes[r])
+	}
+	i := sort.Search(len(da.alphabet), func(i int) bool { return da.alphabet[i] >= r })
+	if i < len(da.alphabet) && da.alphabet[i] == r {
+		return int32(i + 1)
+	}
+	return 0
+}
+
+func (da *DoubleArray) matchLimit(text string, policy MatchPolicy, limit int) []Match {
+	return matchLimit[int32](da, text, policy, limit)
+}
+
+func (da *DoubleArray) scanBytes(text []byte, policy MatchPolicy) [][2]int {
+	return scanBytes[int32](da, text, policy)
+}
+
+func (da *DoubleArray) start() int32 {
+	return 0
+}
+
+func (da *DoubleArray) next(state int32, r rune) (int32, bool) {
+	c := da.code(r)
+	if c == 0 {
+		return 0, false
+	}
+	child := da.base[state] + c
+	if da.base[state] == 0 || child < 1 || int(child) >= len(da.check) || da.check[child] != state+1 {
+		return 0, false
+	}
+	return child, true
+}
+
+func (da *DoubleArray) end(state int32) bool {
+	return da.ends[state/64]&(1<<(uint(state)%64)) != 0
+}
+
+// Walk 按字典序遍历所有词，fn返回false时停止
+func (da *DoubleArray) Walk(fn func(word string) bool) {
+	da.walk(da.childStates(), 0, nil, fn)
+}
+
+// WalkPrefix 按字典序遍历所有以prefix开头的词，fn返回false时停止
+func (da *DoubleArray) WalkPrefix(prefix string, fn func(word string) bool) {
+	if state, ok := descend[int32](da, prefix); ok {
+		da.walk(da.childStates(), state, []rune(prefix), fn)
+	}
+}
+
+func (da *DoubleArray) walk(kids *stateChildren, state int32, prefix []rune, fn func(word string) bool) bool {
+	if da.end(state) && !fn(string(prefix)) {
+		return false
+	}
+	for _, child := range kids.of(state) {
+		if !da.walk(kids, child, append(prefix, da.label(state, child)), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// stateChildren 每个状态的子状态，以压缩行的形式保存：
+// states[offsets[s]:offsets[s+1]]为状态s的子状态，按字符升序排列
+type stateChildren struct {
+	offsets []int32
+	states  []int32
+}
+
+func (c *stateChildren) of(state int32) []int32 {
+	return c.states[c.offsets[state]:c.offsets[state+1]]
+}
+
+// childStates 由check一次遍历得到全部状态的子状态，耗时O(状态数)，
+// 不必在每个状态上遍历整个字母表。子状态的位置为父状态的base加字符编码，
+// 按位置升序加入即按字符升序排列
+func (da *DoubleArray) childStates() *stateChildren {
+	n := len(da.check)
+	c := &stateChildren{offsets: make([]int32, n+1)}
+	for i := 1; i < n; i++ {
+		if parent, ok := da.parent(int32(i)); ok {
+			c.offsets[parent+1]++
+		}
+	}
+	for i := 0; i < n; i++ {
+		c.offsets[i+1] += c.offsets[i]
+	}
+	c.states = make([]int32, c.offsets[n])
+	next := append([]int32(nil), c.offsets[:n]...)
+	for i := 1; i < n; i++ {
+		if parent, ok := da.parent(int32(i)); ok {
+			c.states[next[parent]] = int32(i)
+			next[parent]++
+		}
+	}
+	return c
+}
+
+// parent 返回state的父状态，state空闲或与父状态的base不一致时返回false
+func (da *DoubleArray) parent(state int32) (int32, bool) {
+	parent := da.check[state] - 1
+	if parent < 0 || int(parent) >= len(da.check) || da.base[parent] == 0 {
+		return 0, false
+	}
+	code := state - da.base[parent]
+	return parent, code >= 1 && int(code) <= len(da.alphabet)
+}
+
+// label 返回从parent转移到child的字符
+func (da *DoubleArray) label(parent, child int32) rune {
+	return da.alphabet[child-da.base[parent]-1]
+}
+
+// Trie 将双数组还原为可修改的Trie，按check直接重建各节点，耗时O(状态数)
+func (da *DoubleArray) Trie() *Trie {
+	tree := NewTrie()
+	kids := da.childStates()
+	nodes := []*Node{tree.Root}
+	states := []int32{0}
+	for len(states) > 0 {
+		node, state := nodes[len(nodes)-1], states[len(states)-1]
+		nodes, states = nodes[:len(nodes)-1], states[:len(states)-1]
+		for _, child := range kids.of(state) {
+			r := da.label(state, child)
+			next := NewNode(r)
+			next.isPathEnd = da.end(child)
+			node.Children[r] = next
+			nodes, states = append(nodes, next), append(states, child)
+		}
+	}
+	return tree
+}
+
+// Has 判断word是否为词典中的一个完整的词
+func (da *DoubleArray) Has(word string) bool {
+	return hasWord[int32](da, word)
+}
+
+// Replace 词语替换
+func (da *DoubleArray) Replace(text string, character rune) string {
+	runes := []rune(text)
+	replaceRunes[int32](da, runes, character)
+	return string(runes)
+}
+
+// Filter 直接过滤掉字符串中的敏感词
+func (da *DoubleArray) Filter(text string) string {
+	return string(filterRunes[int32](da, nil, []rune(text)))
+}
+
+// Validate 验证字符串是否合法，如不合法则返回false和检测到
+// 的第一个敏感词
+func (da *DoubleArray) Validate(text string) (bool, string) {
+	return validateRunes[int32](da, []rune(text))
+}
+
+// ValidateWithWildcard 验证字符串是否合法，词库中的wildcard字符可匹配任意一个字符
+func (da *DoubleArray) ValidateWithWildcard(text string, wildcard rune) (bool, string) {
+	return validateWithWildcard[int32](da, []rune(text), wildcard)
+}
+
+// ValidateWithWildcardN 同ValidateWithWildcard，词库中的wildcard字符可匹配0到max个任意字符
+func (da *DoubleArray) ValidateWithWildcardN(text string, wildcard rune, max int) (bool, string) {
+	return validateWithWildcardN[int32](da, []rune(text), wildcard, max)
+}
+
+// FindIn 判断text中是否含有词库中的词
+func (da *DoubleArray) FindIn(text string) (bool, string) {
+	validated, first := da.Validate(text)
+	return !validated, first
+}
+
+// FindAll 找有所有包含在词库中的词
+func (da *DoubleArray) FindAll(text string) []string {
+	return findAllRunes[int32](da, []rune(text))
+}
+
+// ReplaceWithPolicy 按匹配策略替换词语
+func (da *DoubleArray) ReplaceWithPolicy(text string, character rune, policy MatchPolicy) string {
+	return replaceWithPolicy[int32](da, text, character, policy)
+}
+
+// FilterWithPolicy 按匹配策略过滤词语
+func (da *DoubleArray) FilterWithPolicy(text string, policy MatchPolicy) string {
+	return filterWithPolicy[int32](da, text, policy)
+}
+
+// FindAllWithPolicy 按匹配策略找出所有词语，结果去重
+func (da *DoubleArray) FindAllWithPolicy(text string, policy MatchPolicy) []string {
+	return findAllWithPolicy[int32](da, text, policy)
+}
+
+// FindAllWithIndex 找出所有命中词及其位置，语义同Trie.FindAllWithIndex
+func (da *DoubleArray) FindAllWithIndex(text string, policy MatchPolicy, overlap bool) []Match {
+	matches := da.matchIndex(text, policy, overlap)
+	setOffsets(text, matches)
+	return matches
+}
+
+// matchIndex FindAllWithIndex的实现，只计算rune下标
+func (da *DoubleArray) matchIndex(text string, policy MatchPolicy, overlap bool) []Match {
+	return findAllWithIndex[int32](da, text, policy, overlap)
+}
@@ -0,0 +1,151 @@
+package sensitive
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode"
+)
+
+// 编译后词典的二进制格式，所有整数均为小端序：
+//
+//	magic    [4]byte  "SDAT"
+//	version  uint32
+//	alphabet uint32   字母表长度A
+//	states   uint32   状态数N
+//	int32 * A         字母表
+//	int32 * N         base
+//	int32 * N         check
+//	[4]byte           可选填充，使ends按8字节对齐
+//	uint64 * ceil(N/64) ends
+const (
+	compiledMagic   = "SDAT"
+	compiledVersion = 1
+	headerSize      = 16
+)
+
+// ErrInvalidCompiled 输入不是合法的编译后词典
+var ErrInvalidCompiled = errors.New("sensitive: invalid compiled dictionary")
+
+// compiledLayout 根据字母表长度和状态数计算各段的偏移
+func compiledLayout(alphabet, states int) (endsOffset, size int) {
+	endsOffset = headerSize + 4*alphabet + 8*states
+	if endsOffset%8 != 0 {
+		endsOffset += 4
+	}
+	return endsOffset, endsOffset + 8*((states+63)/64)
+}
+
+// WriteTo 将双数组以二进制格式写入w
+func (da *DoubleArray) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	header := make([]byte, headerSize)
+	copy(header, compiledMagic)
+	binary.LittleEndian.PutUint32(header[4:], compiledVersion)
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(da.alphabet)))
+	binary.LittleEndian.PutUint32(header[12:], uint32(len(da.check)))
+	bw.Write(header)
+
+	for _, data := range []interface{}{da.alphabet, da.base, da.check} {
+		if err := binary.Write(bw, binary.LittleEndian, data); err != nil {
+			return 0, err
+		}
+	}
+	endsOffset, size := compiledLayout(len(da.alphabet), len(da.check))
+	if endsOffset != headerSize+4*len(da.alphabet)+8*len(da.check) {
+		bw.Write(make([]byte, 4))
+	}
+	if err := binary.Write(bw, binary.LittleEndian, da.ends); err != nil {
+		return 0, err
+	}
+	return int64(size), bw.Flush()
+}
+
+// ReadDoubleArray 从r读取WriteTo写出的双数组
+func ReadDoubleArray(r io.Reader) (*DoubleArray, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	alphabet, states, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	da := &DoubleArray{}
+	if da.alphabet, err = readSlice[rune](r, alphabet); err != nil {
+		return nil, err
+	}
+	if da.base, err = readSlice[int32](r, states); err != nil {
+		return nil, err
+	}
+	if da.check, err = readSlice[int32](r, states); err != nil {
+		return nil, err
+	}
+	if endsOffset, _ := compiledLayout(alphabet, states); endsOffset != headerSize+4*alphabet+8*states {
+		if _, err := io.ReadFull(r, make([]byte, 4)); err != nil {
+			return nil, err
+		}
+	}
+	if da.ends, err = readSlice[uint64](r, (states+63)/64); err != nil {
+		return nil, err
+	}
+	if err := da.validate(); err != nil {
+		return nil, err
+	}
+	return da, nil
+}
+
+// readSlice 分块读取n个小端序整数。内存随实际读到的数据增长，
+// 头部中伪造的长度不会导致一次性分配大量内存
+func readSlice[T int32 | uint64](r io.Reader, n int) ([]T, error) {
+	const chunk = 1 << 16
+	var s []T
+	for len(s) < n {
+		m := n - len(s)
+		if m > chunk {
+			m = chunk
+		}
+		part := make([]T, m)
+		if err := binary.Read(r, binary.LittleEndian, part); err != nil {
+			return nil, err
+		}
+		s = append(s, part...)
+	}
+	return s, nil
+}
+
+// validate 检查读入的双数组的结构：根状态的check须为-1即没有父状态，
+// 字母表须是严格升序的合法字符以供二分查找和编码表使用。
+// 每个状态由check确定唯一的父状态，根状态又不会成为子状态，
+// 从根出发的遍历因此总是一棵树，伪造的base和check不会使Walk重复访问同一状态
+func (da *DoubleArray) validate() error {
+	if len(da.check) == 0 || da.check[0] != -1 {
+		return ErrInvalidCompiled
+	}
+	for i, r := range da.alphabet {
+		if r < 0 || r > unicode.MaxRune || i > 0 && r <= da.alphabet[i-1] {
+			return ErrInvalidCompiled
+		}
+	}
+	return nil
+}
+
+func parseHeader(header []byte) (alphabet, states int, err error) {
+	if string(header[:4]) != compiledMagic {
+		return 0, 0, ErrInvalidCompiled
+	}
+	if version := binary.LittleEndian.Uint32(header[4:]); version != compiledVersion {
+		return 0, 0, fmt.Errorf("sensitive: unsupported compiled dictionary version %d", version)
+	}
+	alphabet = int(binary.LittleEndian.Uint32(header[8:]))
+	states = int(binary.LittleEndian.Uint32(header[12:]))
+	// 字母表不会超过全部Unicode字符，状态下标为int32
+	if states < 1 || states > math.MaxInt32 || alphabet > unicode.MaxRune+1 {
+		return 0, 0, ErrInvalidCompiled
+	}
+	return alphabet, states, nil
+}
@@ -0,0 +1,92 @@
+package sensitive
+
+import (
+	"os"
+	"unsafe"
+)
+
+// OpenDoubleArray 打开SaveCompiled写出的词典文件。在支持的平台上以只读
+// 内存映射的方式使用文件内容，同一主机上的多个进程共享相同的物理页，
+// 打开时不需要为词典分配堆内存；其它平台退化为读入内存。
+// 使用完毕后需调用Close释放映射
+func OpenDoubleArray(path string) (*DoubleArray, error) {
+	if !littleEndian() {
+		return readDoubleArrayFile(path)
+	}
+
+	data, err := mmapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return readDoubleArrayFile(path)
+	}
+
+	da, err := doubleArrayFromBytes(data)
+	if err != nil {
+		munmap(data)
+		return nil, err
+	}
+	da.mapping = data
+	return da, nil
+}
+
+// Close 释放内存映射，之后不能再使用该双数组。非映射得到的双数组调用Close无副作用
+func (da *DoubleArray) Close() error {
+	if da.mapping == nil {
+		return nil
+	}
+	data := da.mapping
+	da.mapping = nil
+	da.alphabet, da.base, da.check, da.ends = nil, nil, nil, nil
+	return munmap(data)
+}
+
+func readDoubleArrayFile(path string) (*DoubleArray, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadDoubleArray(f)
+}
+
+// doubleArrayFromBytes 直接引用data中的各段，不做拷贝。要求主机为小端序
+func doubleArrayFromBytes(data []byte) (*DoubleArray, error) {
+	if len(data) < headerSize {
+		return nil, ErrInvalidCompiled
+	}
+	alphabet, states, err := parseHeader(data[:headerSize])
+	if err != nil {
+		return nil, err
+	}
+	endsOffset, size := compiledLayout(alphabet, states)
+	if len(data) < size {
+		return nil, ErrInvalidCompiled
+	}
+
+	baseOffset := headerSize + 4*alphabet
+	checkOffset := baseOffset + 4*states
+	da := &DoubleArray{
+		alphabet: int32s(data, headerSize, alphabet),
+		base:     int32s(data, baseOffset, states),
+		check:    int32s(data, checkOffset, states),
+		ends:     unsafe.Slice((*uint64)(unsafe.Pointer(&data[endsOffset])), (states+63)/64),
+	}
+	if err := da.validate(); err != nil {
+		return nil, err
+	}
+	return da, nil
+}
+
+func int32s(data []byte, offset, n int) []int32 {
+	if n == 0 {
+		return nil
+	}
+	return unsafe.Slice((*int32)(unsafe.Pointer(&data[offset])), n)
+}
+
+func littleEndian() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}
@@ -0,0 +1,175 @@
+package sensitive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDoubleArray(t *testing.T) {
+	tree := NewTrie()
+	tree.Add("有一个东西", "一个东西", "一个", "东西", "个东", "abc", "ab", "色情")
+	da := NewDoubleArray(tree)
+
+	texts := []string{"我有一个东东西", "我有一个东西", "一个物体", "xabcd", "没有", ""}
+	for _, text := range texts {
+		if got, expect := da.Replace(text, '*'), tree.Replace(text, '*'); got != expect {
+			t.Errorf("replace %s, got %s, expect %s", text, got, expect)
+		}
+		if got, expect := da.Filter(text), tree.Filter(text); got != expect {
+			t.Errorf("filter %s, got %s, expect %s", text, got, expect)
+		}
+		if got, expect := da.FindAll(text), tree.FindAll(text); !reflect.DeepEqual(got, expect) {
+			t.Errorf("findall %s, got %v, expect %v", text, got, expect)
+		}
+		gotPass, gotFirst := da.Validate(text)
+		expectPass, expectFirst := tree.Validate(text)
+		if gotPass != expectPass || gotFirst != expectFirst {
+			t.Errorf("validate %s, got %v %s, expect %v %s", text, gotPass, gotFirst, expectPass, expectFirst)
+		}
+	}
+
+	var words []string
+	da.Walk(func(word string) bool {
+		words = append(words, word)
+		return true
+	})
+	var expect []string
+	tree.Walk(func(word string) bool {
+		expect = append(expect, word)
+		return true
+	})
+	if !reflect.DeepEqual(words, expect) {
+		t.Errorf("walk, got %v, expect %v", words, expect)
+	}
+
+	var prefixed []string
+	da.WalkPrefix("一个", func(word string) bool {
+		prefixed = append(prefixed, word)
+		return true
+	})
+	if !reflect.DeepEqual(prefixed, []string{"一个", "一个东西"}) {
+		t.Errorf("walk prefix, got %v", prefixed)
+	}
+
+	// 还原的Trie与原Trie包含相同的词
+	words = nil
+	da.Trie().Walk(func(word string) bool {
+		words = append(words, word)
+		return true
+	})
+	if !reflect.DeepEqual(words, expect) {
+		t.Errorf("trie, got %v, expect %v", words, expect)
+	}
+}
+
+func TestFilterCompile(t *testing.T) {
+	filter := New()
+	filter.AddWord("一个", "东西")
+	filter.Compile()
+
+	if got := filter.Replace("一个东西", '*'); got != "****" {
+		t.Errorf("compiled replace, got %s, expect ****", got)
+	}
+
+	filter.AddWord("物体")
+	filter.DelWord("东西")
+	if got := filter.Replace("一个东西物体", '*'); got != "**东西**" {
+		t.Errorf("replace after mutation, got %s, expect **东西**", got)
+	}
+}
+
+func TestSaveLoadCompiled(t *testing.T) {
+	filter := New()
+	filter.AddWord("一个", "东西", "abc")
+
+	var buf bytes.Buffer
+	if err := filter.SaveCompiled(&buf); err != nil {
+		t.Fatalf("save compiled: %v", err)
+	}
+
+	loaded := New()
+	if err := loaded.LoadCompiled(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("load compiled: %v", err)
+	}
+	if got := loaded.Replace("一个东西abc", '*'); got != "*******" {
+		t.Errorf("replace after load compiled, got %s, expect *******", got)
+	}
+
+	if err := loaded.LoadCompiled(strings.NewReader("not a dictionary")); err != ErrInvalidCompiled {
+		t.Errorf("load invalid, got %v, expect %v", err, ErrInvalidCompiled)
+	}
+}
+
+func TestReadDoubleArrayInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	NewDoubleArray(NewTrie()).WriteTo(&buf)
+	header := buf.Bytes()[:headerSize]
+
+	// 头部声明了巨大的长度而内容很短，不能按头部分配内存
+	for _, counts := range [][2]uint32{{1 << 31, 1}, {1, 1 << 31}, {0, 1<<31 - 1}} {
+		data := append([]byte(nil), header...)
+		binary.LittleEndian.PutUint32(data[8:], counts[0])
+		binary.LittleEndian.PutUint32(data[12:], counts[1])
+		if _, err := ReadDoubleArray(bytes.NewReader(append(data, make([]byte, 64)...))); err == nil {
+			t.Errorf("read with counts %v should fail", counts)
+		}
+	}
+
+	tree := NewTrie()
+	tree.Add("一个", "东西")
+	da := NewDoubleArray(tree)
+	for name, corrupt := range map[string]func(da *DoubleArray){
+		"root parent": func(da *DoubleArray) { da.check[0], da.base[0] = 1, 0-int32(len(da.alphabet)) },
+		"unsorted":    func(da *DoubleArray) { da.alphabet[0], da.alphabet[1] = da.alphabet[1], da.alphabet[0] },
+		"negative":    func(da *DoubleArray) { da.alphabet[0] = -1 },
+	} {
+		bad := &DoubleArray{
+			alphabet: append([]rune(nil), da.alphabet...),
+			base:     append([]int32(nil), da.base...),
+			check:    append([]int32(nil), da.check...),
+			ends:     da.ends,
+		}
+		corrupt(bad)
+		buf.Reset()
+		bad.WriteTo(&buf)
+		if _, err := ReadDoubleArray(bytes.NewReader(buf.Bytes())); err != ErrInvalidCompiled {
+			t.Errorf("read %s, got %v, expect %v", name, err, ErrInvalidCompiled)
+		}
+		if _, err := doubleArrayFromBytes(buf.Bytes()); err != ErrInvalidCompiled {
+			t.Errorf("map %s, got %v, expect %v", name, err, ErrInvalidCompiled)
+		}
+	}
+}
+
+func TestLoadCompiledFile(t *testing.T) {
+	filter := New()
+	filter.AddWord("一个", "东西", "abc")
+
+	path := filepath.Join(t.TempDir(), "dict.sdat")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.SaveCompiled(f); err != nil {
+		t.Fatalf("save compiled: %v", err)
+	}
+	f.Close()
+
+	loaded := New()
+	if err := loaded.LoadCompiledFile(path); err != nil {
+		t.Fatalf("load compiled file: %v", err)
+	}
+	if got := loaded.Replace("一个东西abc", '*'); got != "*******" {
+		t.Errorf("replace after load compiled file, got %s, expect *******", got)
+	}
+
+	loaded.AddWord("物体")
+	if got := loaded.Replace("一个物体", '*'); got != "****" {
+		t.Errorf("replace after mutation, got %s, expect ****", got)
+	}
+}
@@ -0,0 +1,155 @@
+package sensitive
+
+import "unicode/utf8"
+
+const zeroWidthJoiner = '\u200d'
+
+// EmojiMapper 将配置的emoji序列翻译为对应词语的归一化器，如"🐎"→"马"，
+// 用于发现以emoji代替汉字的变体。同一位置取最长的序列，匹配时忽略变体选择符
+// (U+FE0E、U+FE0F)。命中还原到原文时覆盖整个emoji序列
+type EmojiMapper struct {
+	mapping map[string][]rune
+	// maxLen 最长序列的字符数，不含变体选择符
+	maxLen int
+}
+
+// NewEmojiMapper 按mapping返回emoji归一化器，键为emoji序列，值为翻译后的词语，
+// 值为空时删除该序列
+func NewEmojiMapper(mapping map[string]string) *EmojiMapper {
+	em := &EmojiMapper{mapping: make(map[string][]rune, len(mapping))}
+	for seq, word := range mapping {
+		key := stripVariation(seq)
+		if key == "" {
+			continue
+		}
+		em.mapping[key] = []rune(word)
+		if n := utf8.RuneCountInString(key); n > em.maxLen {
+			em.maxLen = n
+		}
+	}
+	return em
+}
+
+// Normalize 将emoji序列替换为对应的词语。翻译后的首字对应序列的第一个字符，
+// 多字时末字对应序列的最后一个字符
+func (em *EmojiMapper) Normalize(runes []rune, index []int) ([]rune, []int) {
+	var (
+		out    = make([]rune, 0, len(runes))
+		outIdx = make([]int, 0, len(index))
+	)
+	em.translate(runes, func(word []rune, from, to int) {
+		for k, r := range word {
+			out = append(out, r)
+			if k > 0 && k == len(word)-1 {
+				outIdx = append(outIdx, index[to-1])
+			} else {
+				outIdx = append(outIdx, index[from])
+			}
+		}
+	})
+	return out, outIdx
+}
+
+// normalizeSpan 翻译后的每个字都对应整个emoji序列
+func (em *EmojiMapper) normalizeSpan(runes []rune, start, end []int) ([]rune, []int, []int) {
+	var (
+		out      = make([]rune, 0, len(runes))
+		outStart = make([]int, 0, len(start))
+		outEnd   = make([]int, 0, len(end))
+	)
+	em.translate(runes, func(word []rune, from, to int) {
+		for _, r := range word {
+			out = append(out, r)
+			outStart = append(outStart, start[from])
+			outEnd = append(outEnd, end[to-1])
+		}
+	})
+	return out, outStart, outEnd
+}
+
+// translate 从左到右切分runes，对每个emoji序列runes[from:to]以翻译后的词
+// 调用emit，其余字符以其本身调用emit
+func (em *EmojiMapper) translate(runes []rune, emit func(word []rune, from, to int)) {
+	key := make([]rune, 0, em.maxLen)
+	for i := 0; i < len(runes); {
+		var (
+			word  []rune
+			found bool
+			end   = i
+		)
+		key = key[:0]
+		for j := i; j < len(runes) && len(key) < em.maxLen; j++ {
+			if isVariation(runes[j]) {
+				continue
+			}
+			key = append(key, runes[j])
+			if w, ok := em.mapping[string(key)]; ok {
+				word, found, end = w, true, j+1
+			}
+		}
+		if !found {
+			emit(runes[i:i+1], i, i+1)
+			i++
+			continue
+		}
+
+		// 序列之后的变体选择符属于该序列
+		for end < len(runes) && isVariation(runes[end]) {
+			end++
+		}
+		emit(word, i, end)
+		i = end
+	}
+}
+
+func isVariation(r rune) bool {
+	return r == '\ufe0e' || r == '\ufe0f'
+}
+
+func stripVariation(s string) string {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		if !isVariation(r) {
+			runes = append(runes, r)
+		}
+	}
+	return string(runes)
+}
+
+// isEmojiModifier 判断r是否只能跟在emoji之后修饰它：变体选择符、肤色、
+// 键帽和标签字符
+func isEmojiModifier(r rune) bool {
+	switch {
+	case isVariation(r), r == '\u20e3':
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		return true
+	case r >= 0xe0020 && r <= 0xe007f:
+		return true
+	}
+	return false
+}
+
+// isEmoji 粗略判断r是否为emoji，覆盖常用的符号和表情区段
+func isEmoji(r rune) bool {
+	return r >= 0x1f000 && r <= 0x1faff || r >= 0x2600 && r <= 0x27bf
+}
+
+// emojiEnd 当original[end-1]为emoji时，返回其所在序列的结尾，
+// 包括之后的修饰符及由零宽连接符连接的后续emoji，否则原样返回end
+func emojiEnd(original []rune, end int) int {
+	if end == 0 || !isEmoji(original[end-1]) {
+		return end
+	}
+	for end < len(original) {
+		switch {
+		case isEmojiModifier(original[end]):
+			end++
+		case original[end] == zeroWidthJoiner && end+1 < len(original):
+			end += 2
+		default:
+			return end
+		}
+	}
+	return end
+}
@@ -0,0 +1,55 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEmojiMapper(t *testing.T) {
+	em := NewEmojiMapper(map[string]string{
+		"🐎":               "马",
+		"🈲\ufe0f":         "禁",
+		"👨\u200d👩\u200d👦": "家",
+		"🐴🐴":              "马马",
+	})
+
+	runes, index := em.Normalize([]rune("草泥🐎🈲\ufe0f🐴🐴"), []int{0, 1, 2, 3, 4, 5, 6})
+	if string(runes) != "草泥马禁马马" {
+		t.Errorf("normalize, got %s", string(runes))
+	}
+	if expect := []int{0, 1, 2, 3, 5, 6}; !reflect.DeepEqual(index, expect) {
+		t.Errorf("normalize index, got %v, expect %v", index, expect)
+	}
+
+	// 没有变体选择符时同样匹配
+	if runes, _ := em.Normalize([]rune("🈲"), []int{0}); string(runes) != "禁" {
+		t.Errorf("normalize without variation selector, got %s", string(runes))
+	}
+}
+
+func TestPipelineMapEmoji(t *testing.T) {
+	filter := New()
+	filter.AddWord("草泥马", "禁书", "全家")
+
+	p, err := NewPipelineBuilder().
+		MapEmoji(map[string]string{"🐎": "马", "🈲": "禁", "👨\u200d👩\u200d👦": "家"}).
+		Match(filter).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		text, expect string
+	}{
+		{"草泥🐎!", "***!"},
+		{"这是🈲\ufe0f书", "这是***"},
+		{"全👨\u200d👩\u200d👦福", "******福"},
+		{"🐎上", "🐎上"},
+	}
+	for _, c := range cases {
+		if res := p.Run(c.text); res.Text != c.expect {
+			t.Errorf("run %q, got %q, expect %q", c.text, res.Text, c.expect)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package sensitive
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding 词典的字符编码
+type Encoding int
+
+const (
+	// EncodingAuto 按BOM和内容自动识别：有BOM时按BOM，否则内容不是合法的UTF-8
+	// 但是合法的GB18030时按GB18030，其余按UTF-8
+	EncodingAuto Encoding = iota
+	// EncodingUTF8 UTF-8，开头的BOM会被去掉
+	EncodingUTF8
+	// EncodingGB18030 GB18030，兼容GBK和GB2312
+	EncodingGB18030
+	// EncodingUTF16LE 小端序UTF-16
+	EncodingUTF16LE
+	// EncodingUTF16BE 大端序UTF-16
+	EncodingUTF16BE
+)
+
+// EncodingGBK GBK是GB18030的子集，按GB18030解码
+const EncodingGBK = EncodingGB18030
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+	bomGB18030 = []byte{0x84, 0x31, 0x95, 0x33}
+)
+
+// LoadWithEncoding 按指定编码加载词典
+func LoadWithEncoding(rd io.Reader, enc Encoding) error {
+	return Default().LoadWithEncoding(rd, enc)
+}
+
+// LoadWithEncoding 同Load，但按enc解码词典内容。Load相当于使用EncodingAuto
+func (filter *Filter) LoadWithEncoding(rd io.Reader, enc Encoding) error {
+	return filter.load("", bufio.NewReader(rd), enc, nil)
+}
+
+// detectEncoding 按BOM和预读的内容识别编码
+func detectEncoding(head []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return EncodingUTF8
+	case bytes.HasPrefix(head, bomGB18030):
+		return EncodingGB18030
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	// 预读可能截断最后一个字符
+	valid := head
+	for i := 0; i < utf8.UTFMax && i < len(head); i++ {
+		if utf8.RuneStart(head[len(head)-1-i]) {
+			valid = head[:len(head)-1-i]
+			break
+		}
+	}
+	if utf8.Valid(valid) || !validGB18030(head) {
+		return EncodingUTF8
+	}
+	return EncodingGB18030
+}
+
+// validGB18030 判断预读的内容是否为合法的GB18030，忽略被截断的最后一个字符
+func validGB18030(head []byte) bool {
+	gb18030Once.Do(initGB18030)
+	for i := 0; i < len(head); {
+		r, size := decodeGB18030Rune(head[i:])
+		if r == utf8.RuneError {
+			return len(head)-i < 4
+		}
+		i += size
+	}
+	return true
+}
+
+// decodeAll 读取全部内容并由enc转为UTF-8
+func decodeAll(rd io.Reader, enc Encoding) ([]byte, error) {
+	content, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	switch enc {
+	case EncodingGB18030:
+		return decodeGB18030(bytes.TrimPrefix(content, bomGB18030)), nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		return decodeUTF16(content, enc), nil
+	}
+	return bytes.TrimPrefix(content, bomUTF8), nil
+}
+
+func decodeUTF16(content []byte, enc Encoding) []byte {
+	units := make([]uint16, 0, len(content)/2)
+	for i := 0; i+1 < len(content); i += 2 {
+		if enc == EncodingUTF16LE {
+			units = append(units, uint16(content[i])|uint16(content[i+1])<<8)
+		} else {
+			units = append(units, uint16(content[i])<<8|uint16(content[i+1]))
+		}
+	}
+	if len(units) > 0 && units[0] == 0xFEFF {
+		units = units[1:]
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+var (
+	gb18030Once  sync.Once
+	gb18030Table []rune
+)
+
+func initGB18030() {
+	gb18030Table = []rune(gb18030Double)
+}
+
+// decodeGB18030 将GB18030编码的内容转为UTF-8，不合法的字节转为U+FFFD
+func decodeGB18030(content []byte) []byte {
+	gb18030Once.Do(initGB18030)
+
+	out := make([]byte, 0, len(content)*3/2)
+	for i := 0; i < len(content); {
+		r, size := decodeGB18030Rune(content[i:])
+		out = utf8.AppendRune(out, r)
+		i += size
+	}
+	return out
+}
+
+func decodeGB18030Rune(p []byte) (rune, int) {
+	b1 := p[0]
+	switch {
+	case b1 < 0x80:
+		return rune(b1), 1
+	case b1 == 0x80 || b1 == 0xFF || len(p) < 2:
+		return utf8.RuneError, 1
+	}
+
+	b2 := p[1]
+	if b2 >= 0x40 && b2 <= 0xFE && b2 != 0x7F {
+		return gb18030Table[int(b1-0x81)*191+int(b2-0x40)], 2
+	}
+	if b2 < 0x30 || b2 > 0x39 || len(p) < 4 || p[2] < 0x81 || p[2] > 0xFE || p[3] < 0x30 || p[3] > 0x39 {
+		return utf8.RuneError, 1
+	}
+
+	index := ((int(b1-0x81)*10+int(b2-0x30))*126+int(p[2]-0x81))*10 + int(p[3]-0x30)
+	if b1 >= 0x90 {
+		r := rune(index - 189000 + 0x10000)
+		if r > utf8.MaxRune {
+			return utf8.RuneError, 4
+		}
+		return r, 4
+	}
+
+	i := sort.Search(len(gb18030Ranges), func(i int) bool { return int(gb18030Ranges[i].end) >= index })
+	if i == len(gb18030Ranges) || int(gb18030Ranges[i].index) > index {
+		return utf8.RuneError, 4
+	}
+	return rune(gb18030Ranges[i].rune) + rune(index-int(gb18030Ranges[i].index)), 4
+}
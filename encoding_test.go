@@ -0,0 +1,52 @@
+package sensitive
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeGB18030(t *testing.T) {
+	// "垃圾\n東西\n¥\U00020000\n"，包括双字节、BMP四字节和增补平面四字节编码
+	gb := []byte("\xc0\xac\xbb\xf8\n\x96|\xce\xf7\n\x810\x846\x952\x826\n")
+	if got := string(decodeGB18030(gb)); got != "垃圾\n東西\n¥\U00020000\n" {
+		t.Errorf("got %q", got)
+	}
+	if got := string(decodeGB18030([]byte{0x80, 0xff, 0x81})); got != "���" {
+		t.Errorf("invalid bytes, got %q", got)
+	}
+}
+
+func TestLoadEncodings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"gbk", []byte("\xc0\xac\xbb\xf8\n\x96|\xce\xf7\n")},
+		{"gb18030 bom", append([]byte{0x84, 0x31, 0x95, 0x33}, "\xc0\xac\xbb\xf8\n\x96|\xce\xf7\n"...)},
+		{"utf8 bom", []byte("\xef\xbb\xbf垃圾\n東西\n")},
+		{"utf16le", []byte("\xff\xfe\x83W>W\n\x00qg\x7f\x89\n\x00")},
+		{"utf16be", []byte("\xfe\xffW\x83W>\x00\ngq\x89\x7f\x00\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := New()
+			if err := filter.LoadBytes(tt.content); err != nil {
+				t.Fatal(err)
+			}
+			if !filter.trie.Has("垃圾") || !filter.trie.Has("東西") {
+				t.Errorf("words not decoded")
+			}
+		})
+	}
+}
+
+func TestLoadWithEncoding(t *testing.T) {
+	filter := New()
+	// 这两个字节恰好也是合法的UTF-8("Ȁ")，自动识别会按UTF-8处理
+	if err := filter.LoadWithEncoding(bytes.NewReader([]byte("\xc8\x80")), EncodingGBK); err != nil {
+		t.Fatal(err)
+	}
+	if !filter.trie.Has("葊") {
+		t.Errorf("explicit encoding not applied")
+	}
+}
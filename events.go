@@ -0,0 +1,13 @@
+package sensitive
+
+import "time"
+
+// eventLogger 接收过滤器的运行事件，由SetLogger设置
+type eventLogger interface {
+	// loadFinished 一次加载结束，elapsed为加载耗时，未知时为0
+	loadFinished(state LoadState, version uint64, elapsed time.Duration)
+	// matchEnabled 是否需要记录命中，为false时不计算命中的词
+	matchEnabled() bool
+	// matchFound 一次命中了敏感词的查询，textLength为文本的字节数
+	matchFound(words []string, textLength int)
+}
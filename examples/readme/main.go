@@ -11,7 +11,7 @@ func main() {
 	filter.LoadWordDict("../dict/dict.txt")
 	filter.AddWord("长者")
 
-	fmt.Println(filter.Filter("我为长者续一秒"))       // 我为续一秒
+	fmt.Println(filter.FilterWord("我为长者续一秒"))   // 我为续一秒
 	fmt.Println(filter.Replace("我为长者续一秒", '*')) // 我为**续一秒
 	fmt.Println(filter.FindIn("我为长者续一秒"))       // true, 长者
 	fmt.Println(filter.Validate("我为长者续一秒"))     // False, 长者
@@ -47,14 +47,14 @@ func main() {
 	fmt.Println(filter.Validate("有一个"))
 	fmt.Println(filter.Validate("有一"))
 
-	fmt.Println("一", filter.Filter("一"))
-	fmt.Println("一个", filter.Filter("一个"))
-	fmt.Println("一个东", filter.Filter("一个东"))
-	fmt.Println("一个东西", filter.Filter("一个东西"))
-	fmt.Println("一个东西啊", filter.Filter("一个东西啊"))
-	fmt.Println("有一个东西啊", filter.Filter("有一个东西啊"))
-	fmt.Println("有一个东啊", filter.Filter("有一个东啊"))
-	fmt.Println("有一个啊", filter.Filter("有一个啊"))
-	fmt.Println("有一个", filter.Filter("有一个"))
-	fmt.Println("有一", filter.Filter("有一"))
+	fmt.Println("一", filter.FilterWord("一"))
+	fmt.Println("一个", filter.FilterWord("一个"))
+	fmt.Println("一个东", filter.FilterWord("一个东"))
+	fmt.Println("一个东西", filter.FilterWord("一个东西"))
+	fmt.Println("一个东西啊", filter.FilterWord("一个东西啊"))
+	fmt.Println("有一个东西啊", filter.FilterWord("有一个东西啊"))
+	fmt.Println("有一个东啊", filter.FilterWord("有一个东啊"))
+	fmt.Println("有一个啊", filter.FilterWord("有一个啊"))
+	fmt.Println("有一个", filter.FilterWord("有一个"))
+	fmt.Println("有一", filter.FilterWord("有一"))
 }
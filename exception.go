@@ -0,0 +1,97 @@
+package sensitive
+
+// exceptionContext 例外规则检查命中前后各多少个字符
+const exceptionContext = 64
+
+// exception 上下文例外规则，before和after任一为nil表示不限制该侧
+type exception struct {
+	before regex
+	after  regex
+}
+
+// AddException 添加上下文例外规则
+func AddException(word, before, after string) error {
+	return Default().AddException(word, before, after)
+}
+
+// AddException 添加上下文例外规则：命中word时，若紧挨其前的文本匹配正则before
+// 且紧挨其后的文本匹配正则after，则不算命中。before或after为空表示不限制该侧，
+// 两者都为空时word在任何情况下都不算命中。如AddException("河蟹", "", "(汤|粥)")
+// 使"河蟹汤"不再命中。例外规则在Trie匹配之后执行，同一个词可以有多条规则。
+// before和after只与命中前后各exceptionContext(64)个字符比较，以免长文本中
+// 每个命中都扫描整段文本
+func (filter *Filter) AddException(word, before, after string) error {
+	var (
+		ex  exception
+		err error
+	)
+	if before != "" {
+		if ex.before, err = compileRegex(`(?:` + before + `)$`); err != nil {
+			return err
+		}
+	}
+	if after != "" {
+		if ex.after, err = compileRegex(`^(?:` + after + `)`); err != nil {
+			return err
+		}
+	}
+
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	if filter.exceptions == nil {
+		filter.exceptions = make(map[string][]exception)
+	}
+	filter.exceptions[word] = append(filter.exceptions[word], ex)
+	return nil
+}
+
+// ClearExceptions 清除词语的全部例外规则
+func ClearExceptions(word string) {
+	Default().ClearExceptions(word)
+}
+
+// ClearExceptions 清除词语的全部例外规则
+func (filter *Filter) ClearExceptions(word string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	delete(filter.exceptions, word)
+}
+
+// excepted 判断命中是否被例外规则排除，offsets[i]为text中第i个字符的字节偏移，
+// 末尾另有len(text)，调用方需持有锁
+func (filter *Filter) excepted(text string, offsets []int, m Match) bool {
+	exceptions := filter.exceptions[m.Word]
+	if len(exceptions) == 0 {
+		return false
+	}
+
+	from, to := m.Start-exceptionContext, m.End+exceptionContext
+	if from < 0 {
+		from = 0
+	}
+	if to > len(offsets)-1 {
+		to = len(offsets) - 1
+	}
+	before, after := text[offsets[from]:offsets[m.Start]], text[offsets[m.End]:offsets[to]]
+	for _, ex := range exceptions {
+		if ex.before != nil && !ex.before.MatchString(before) {
+			continue
+		}
+		if ex.after != nil && !ex.after.MatchString(after) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// runeOffsets 返回text中每个字符的字节偏移，末尾另加len(text)
+func runeOffsets(text string) []int {
+	offsets := make([]int, 0, len(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(text))
+}
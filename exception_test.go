@@ -0,0 +1,86 @@
+package sensitive
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestException(t *testing.T) {
+	filter := New()
+	filter.AddWord("河蟹", "和谐")
+	if err := filter.AddException("河蟹", "", "(汤|粥)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.AddException("河蟹", "清蒸", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.AddException("河蟹", "(", ""); err == nil {
+		t.Errorf("add exception with invalid pattern should fail")
+	}
+
+	testcases := []struct {
+		Text          string
+		ExpectReplace string
+		ExpectFindAll []string
+	}{
+		{"今天喝河蟹汤", "今天喝河蟹汤", nil},
+		{"清蒸河蟹很好吃", "清蒸河蟹很好吃", nil},
+		{"被河蟹了，要和谐", "被**了，要**", []string{"河蟹", "和谐"}},
+		{"河蟹和谐河蟹粥", "****河蟹粥", []string{"河蟹", "和谐"}},
+	}
+
+	for _, tc := range testcases {
+		if got := filter.Replace(tc.Text, '*'); got != tc.ExpectReplace {
+			t.Errorf("replace %s, got %s, expect %s", tc.Text, got, tc.ExpectReplace)
+		}
+		if got := filter.FindAll(tc.Text); !reflect.DeepEqual(got, tc.ExpectFindAll) {
+			t.Errorf("findall %s, got %v, expect %v", tc.Text, got, tc.ExpectFindAll)
+		}
+	}
+
+	if found, _ := filter.FindIn("河蟹汤"); found {
+		t.Errorf("findin excepted word should not be found")
+	}
+	filter.ClearExceptions("河蟹")
+	if found, word := filter.FindIn("河蟹汤"); !found || word != "河蟹" {
+		t.Errorf("findin after clear exceptions, got %v %s", found, word)
+	}
+}
+
+func TestExceptionContext(t *testing.T) {
+	filter := New()
+	filter.AddWord("河蟹")
+	if err := filter.AddException("河蟹", "清蒸.*", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	near := "清蒸" + strings.Repeat("的", exceptionContext-2) + "河蟹"
+	if found, _ := filter.FindIn(near); found {
+		t.Errorf("exception within context should apply")
+	}
+	far := "清蒸" + strings.Repeat("的", exceptionContext) + "河蟹"
+	if found, _ := filter.FindIn(far); !found {
+		t.Errorf("exception beyond context should not apply")
+	}
+
+	// 每个命中只检查附近的文本，长文本中的大量命中不会使匹配变为平方复杂度
+	long := strings.Repeat("清蒸河蟹，", 20000)
+	if got := filter.FindAllWithIndex(long); len(got) != 0 {
+		t.Errorf("long text, got %d matches", len(got))
+	}
+}
+
+func BenchmarkException(b *testing.B) {
+	filter := New()
+	filter.AddWord("河蟹")
+	if err := filter.AddException("河蟹", "清蒸", "(汤|粥)"); err != nil {
+		b.Fatal(err)
+	}
+	text := strings.Repeat("今天吃河蟹，", 2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		filter.FindAllWithIndex(text)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import "expvar"
+
+// Publish 将默认过滤器的运行状态以name发布到expvar
+func Publish(name string) {
+	Default().Publish(name)
+}
+
+// Publish 将过滤器的运行状态以name发布到expvar，引入net/http后可在
+// /debug/vars中查看。与expvar.Publish一样，name重复时会panic
+func (filter *Filter) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return filter.Stats()
+	}))
+}
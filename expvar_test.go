@@ -0,0 +1,23 @@
+//go:build !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情")
+	filter.Publish("sensitive_test")
+
+	var stats Stats
+	if err := json.Unmarshal([]byte(expvar.Get("sensitive_test").String()), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Words != 1 {
+		t.Errorf("got %+v", stats)
+	}
+}
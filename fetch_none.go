@@ -0,0 +1,27 @@
+//go:build tinygo || sensitive_noregexp
+
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// get 不引入net/http的构建中总是返回ErrUnsupported
+func get(ctx context.Context, url string, timeout time.Duration) (io.ReadCloser, string, error) {
+	return nil, "", &FetchError{URL: url, Err: ErrUnsupported}
+}
+
+func isTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+func isNetError(err error) bool {
+	return false
+}
+
+func statusText(code int) string {
+	return ""
+}
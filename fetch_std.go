@@ -0,0 +1,55 @@
+//go:build !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// get 请求url，返回响应内容及其版本(ETag或Last-Modified)，错误与fetch相同，
+// 返回的响应内容已包装为fetchBody
+func get(ctx context.Context, url string, timeout time.Duration) (io.ReadCloser, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", &FetchError{URL: url, Err: err}
+	}
+	c := http.Client{
+		Timeout: timeout,
+	}
+	rsp, err := c.Do(req)
+	if err != nil {
+		return nil, "", &FetchError{URL: url, Err: err}
+	}
+
+	if rsp.StatusCode >= 400 {
+		rsp.Body.Close()
+		return nil, "", &HTTPError{URL: url, StatusCode: rsp.StatusCode}
+	}
+	version := rsp.Header.Get("ETag")
+	if version == "" {
+		version = rsp.Header.Get("Last-Modified")
+	}
+	return fetchBody{ReadCloser: rsp.Body, url: url}, version, nil
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+func isNetError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func statusText(code int) string {
+	return http.StatusText(code)
+}
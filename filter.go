@@ -3,52 +3,285 @@ package sensitive
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
-	"regexp"
-	"sync"
+	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
+// defaultFilter 包级函数使用的过滤器
+var defaultFilter atomic.Pointer[Filter]
+
+func init() {
+	defaultFilter.Store(New())
+}
+
+// Default 返回包级函数使用的默认过滤器
+func Default() *Filter {
+	return defaultFilter.Load()
+}
+
+// SetDefault 将filter设为包级函数使用的默认过滤器，便于先完整配置好过滤器
+// 再通过包级函数使用。替换前已开始的包级调用仍使用原来的过滤器；
+// filter为nil时不做任何修改
+func SetDefault(filter *Filter) {
+	if filter != nil {
+		defaultFilter.Store(filter)
+	}
+}
+
+// DefaultMaxWordLength TryAddWord默认允许的最大词长(rune数)
+const DefaultMaxWordLength = 64
+
 var (
-	pkgFilter = New()
+	// ErrEmptyWord 词语为空
+	ErrEmptyWord = errors.New("sensitive: empty word")
+	// ErrBlankWord 词语只含空白字符
+	ErrBlankWord = errors.New("sensitive: blank word")
+	// ErrWordTooLong 词语超过最大长度
+	ErrWordTooLong = errors.New("sensitive: word too long")
+	// ErrUnsupported 当前构建不支持该功能。使用TinyGo或sensitive_noregexp标签
+	// 编译时不引入regexp和net/http，接受正则的方法和加载网络词典返回该错误
+	ErrUnsupported = errors.New("sensitive: not supported in this build")
 )
 
 // Filter 敏感词过滤器
 type Filter struct {
-	mu    sync.RWMutex
-	trie  *Trie
-	noise *regexp.Regexp
+	mu      filterMutex
+	trie    *Trie
+	noise   regex
+	policy  MatchPolicy
+	overlap bool
+	// noiseSet 默认的字符集合去噪模式，与noise最多只有一个非nil
+	noiseSet *runeSet
+	// noisePatterns 在noise之后依次应用的命名噪音模式
+	noisePatterns []noisePattern
+	noiseFunc     func(r rune) bool
+	// throughNoise Replace、FilterWord是否跨过噪音字符匹配
+	throughNoise bool
+	// da 编译后的双数组Trie，非nil时trie为nil
+	da *DoubleArray
+	// store 自定义的词典存储，非nil时trie和da均不使用，见NewWithStore
+	store WordStore
+	// prefilter 词首字符位图，开启预过滤时非nil
+	prefilter *prefilter
+	// maxWordLength TryAddWord允许的最大词长(rune数)
+	maxWordLength int
+	// maxLineLength 加载纯文本词典时允许的最大行长(字节数)
+	maxLineLength int
+	// version 词典版本号，每次修改加一
+	version       uint64
+	snapshots     map[uint64]*Snapshot
+	snapshotOrder []uint64
+	maxSnapshots  int
+	// deadlines 带有效期的词及其过期时间
+	deadlines map[string]time.Time
+	// schedules 设置了生效时间的词
+	schedules map[string][]Schedule
+	timer     *time.Timer
+	// meta 词语的分类、优先级等附加信息
+	meta       map[string]wordMeta
+	actions    map[string]Action
+	logHandler func(category string, m Match)
+	sampler    func(Sample)
+	sampleRate float64
+	// events 运行事件的接收者，见SetLogger
+	events     eventLogger
+	tracer     Tracer
+	exceptions map[string][]exception
+	// usePriority 是否有词设置过非0的优先级
+	usePriority bool
+	// rules 共现规则，ruleTrie保存全部规则用到的词
+	rules    map[string]Rule
+	ruleTrie *Trie
+	// skipLinks 是否跳过URL和邮箱中的命中
+	skipLinks bool
+	tokenizer Tokenizer
+	// reverse 是否同时检查倒写的词
+	reverse bool
+	// separators 匹配时忽略的分隔字符，见SetSeparators
+	separators *runeSet
+	// mergeSpans 是否合并相邻或重叠的命中，见SetMergeSpans
+	mergeSpans bool
+	// lineJoin 跨行匹配时忽略的分隔，见SetLineJoin
+	lineJoin regex
+	// interceptor AddWord、DelWord等的拦截器
+	interceptor Interceptor
+	// compiled Freeze和ConcurrencySnapshot复用的编译结果，见compiledTable
+	compiled atomic.Pointer[compiledDict]
+	// published ConcurrencySnapshot模式下发布的只读副本，见SetConcurrency
+	published atomic.Pointer[Filter]
+	// coalesce 合并AddWord、DelWord的配置，见SetCoalesce
+	coalesce atomic.Pointer[coalescer]
+	// usernameSeps ValidateUsername忽略的分隔字符
+	usernameSeps *runeSet
+	// profiles 按名称保存的配置方案，见SetProfile
+	profiles map[string]Profile
+	// maxTextLen 查询文本的最大字节数，truncateText为超过时是否截断，见SetMaxTextLen
+	maxTextLen   int
+	truncateText bool
+	// utf8Policy 查询文本不是合法的UTF-8时的处理方式
+	utf8Policy UTF8Policy
+	// parallel 长文本并行匹配的配置，见SetParallel
+	parallel *parallelScan
+	// cache 查询结果的LRU缓存，见SetCache
+	cache *resultCache
+	// phonetic 读音索引，见SetPhonetic
+	phonetic *phoneticIndex
+	// retry 加载网络词典的重试策略
+	retry RetryPolicy
+	// loadState 最近一次加载词典的状态，loadErrors为失败次数
+	loadState  LoadState
+	loadErrors uint64
+	ready      chan struct{}
+	// queries、hits 查询和命中的次数
+	queries atomic.Uint64
+	hits    atomic.Uint64
+	// pending 自上次commit以来的修改
+	pending     Delta
+	watchers    map[int]func(Delta)
+	nextWatcher int
+}
+
+// matcher Trie和DoubleArray共有的查询方法
+type matcher interface {
+	Has(word string) bool
+	Walk(fn func(word string) bool)
+	WalkPrefix(prefix string, fn func(word string) bool)
+	FindIn(text string) (bool, string)
+	Validate(text string) (bool, string)
+	ValidateWithWildcard(text string, wildcard rune) (bool, string)
+	ValidateWithWildcardN(text string, wildcard rune, max int) (bool, string)
+	ReplaceWithPolicy(text string, character rune, policy MatchPolicy) string
+	FilterWithPolicy(text string, policy MatchPolicy) string
+	FindAllWithPolicy(text string, policy MatchPolicy) []string
+	FindAllWithIndex(text string, policy MatchPolicy, overlap bool) []Match
+	DumpDOT(w io.Writer, maxDepth int) error
+	firstRunes() []rune
+	matchIndex(text string, policy MatchPolicy, overlap bool) []Match
+	matchLimit(text string, policy MatchPolicy, limit int) []Match
+	scanBytes(text []byte, policy MatchPolicy) [][2]int
+}
+
+// matcher 返回当前用于查询的词典结构，调用方需持有锁
+func (filter *Filter) matcher() matcher {
+	if filter.store != nil {
+		return storeMatcher{filter.store}
+	}
+	if filter.da != nil {
+		return filter.da
+	}
+	return filter.trie
+}
+
+// mutable 返回可修改的词典存储，已编译时先还原为Trie，调用方需持有写锁
+func (filter *Filter) mutable() WordStore {
+	if filter.store != nil {
+		return filter.store
+	}
+	if filter.da != nil {
+		filter.trie = filter.da.Trie()
+		filter.setCompiled(nil)
+	}
+	return filter.trie
+}
+
+// addWords 添加词语并同步预过滤位图，调用方需持有写锁
+func (filter *Filter) addWords(words ...string) {
+	filter.mutable().Add(words...)
+	filter.record(words, nil)
+	for _, word := range words {
+		if filter.prefilter != nil {
+			filter.prefilter.addWord(word)
+		}
+		delete(filter.deadlines, word)
+		delete(filter.schedules, word)
+	}
+}
+
+// delWords 删除词语，调用方需持有写锁
+func (filter *Filter) delWords(words ...string) {
+	filter.mutable().Del(words...)
+	filter.record(nil, words)
+	for _, word := range words {
+		delete(filter.deadlines, word)
+		delete(filter.schedules, word)
+		delete(filter.meta, word)
+	}
+}
+
+// record 有订阅者时记录本次修改，在commit时通知，调用方需持有写锁
+func (filter *Filter) record(added, removed []string) {
+	if len(filter.watchers) == 0 {
+		return
+	}
+	filter.pending.Added = append(filter.pending.Added, added...)
+	filter.pending.Removed = append(filter.pending.Removed, removed...)
+}
+
+// skip 开启预过滤且text中不可能含有敏感词时返回true，调用方需持有锁
+func (filter *Filter) skip(text string) bool {
+	return filter.prefilter != nil && !filter.prefilter.mayMatch(text)
+}
+
+// rebuildPrefilter 按当前词典重建预过滤位图，调用方需持有写锁
+func (filter *Filter) rebuildPrefilter() {
+	if filter.prefilter == nil {
+		return
+	}
+	filter.prefilter = &prefilter{}
+	for _, r := range filter.matcher().firstRunes() {
+		filter.prefilter.add(r)
+	}
+}
+
+// setCompiled 替换编译后的词典，旧词典若为内存映射则释放，调用方需持有写锁
+func (filter *Filter) setCompiled(da *DoubleArray) {
+	if filter.da != nil && filter.da != da {
+		filter.da.Close()
+	}
+	filter.da = da
+	if da != nil {
+		filter.trie = nil
+	}
 }
 
 // New 返回一个敏感词过滤器
 func New() *Filter {
 	return &Filter{
-		trie:  NewTrie(),
-		noise: regexp.MustCompile(`[\|\s&%$@*]+`),
+		trie:          NewTrie(),
+		noiseSet:      newRuneSet(DefaultNoiseRunes),
+		usernameSeps:  newRuneSet(DefaultUsernameSeparators),
+		maxWordLength: DefaultMaxWordLength,
+		maxLineLength: DefaultMaxLineLength,
+		maxSnapshots:  DefaultMaxSnapshots,
+		ready:         make(chan struct{}),
 	}
 }
 
 func LoadWordDict(path string) error {
-	return pkgFilter.LoadWordDict(path)
+	return Default().LoadWordDict(path)
 }
 
 // LoadWordDict 加载敏感词字典
 func (filter *Filter) LoadWordDict(path string) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return err
+		return filter.recordLoad(path, err)
 	}
 	defer f.Close()
 
-	return filter.Load(f)
+	return filter.load(path, bufio.NewReader(f), EncodingAuto, nil)
 }
 
 // LoadBytes common method to add words
 func LoadBytes(ba []byte) error {
-	return pkgFilter.LoadBytes(ba)
+	return Default().LoadBytes(ba)
 }
 
 // LoadBytes common method to add words
@@ -58,7 +291,7 @@ func (filter *Filter) LoadBytes(ba []byte) error {
 
 // LoadNetWordDict 加载网络敏感词字典
 func LoadNetWordDict(url string) error {
-	return pkgFilter.LoadNetWordDict(url)
+	return Default().LoadNetWordDict(url)
 }
 
 // LoadNetWordDict 加载网络敏感词字典
@@ -68,176 +301,678 @@ func (filter *Filter) LoadNetWordDict(url string) error {
 
 // LoadNetWordDictTimeout 加载网络敏感词字典，带超时设置
 func LoadNetWordDictTimeout(url string, timeout time.Duration) error {
-	return pkgFilter.LoadNetWordDictTimeout(url, timeout)
+	return Default().LoadNetWordDictTimeout(url, timeout)
 }
 
-// LoadNetWordDictTimeout 加载网络敏感词字典，带超时设置
+// LoadNetWordDictTimeout 加载网络敏感词字典，带超时设置。服务端返回错误状态码时
+// 返回*HTTPError，请求或读取失败时返回*FetchError，超时可用errors.Is(err, ErrTimeout)判断
 func (filter *Filter) LoadNetWordDictTimeout(url string, timeout time.Duration) error {
-	c := http.Client{
-		Timeout: timeout,
-	}
-	rsp, err := c.Get(url)
+	body, err := filter.fetch(url, timeout)
 	if err != nil {
 		return err
 	}
-	defer rsp.Body.Close()
+	defer body.Close()
 
-	if rsp.StatusCode >= 400 {
-		text := http.StatusText(rsp.StatusCode)
-		return fmt.Errorf(text)
-	}
-	return filter.Load(rsp.Body)
+	return filter.load(url, bufio.NewReader(body), EncodingAuto, nil)
+}
+
+// fetch 请求url并返回响应内容，状态码>=400时返回*HTTPError，
+// 请求和读取内容失败时返回*FetchError
+func fetch(url string, timeout time.Duration) (io.ReadCloser, error) {
+	body, _, err := get(context.Background(), url, timeout)
+	return body, err
 }
 
 // Load common method to add words
 func Load(rd io.Reader) error {
-	return pkgFilter.Load(rd)
+	return Default().Load(rd)
 }
 
-// Load common method to add words
+// Load common method to add words，自动识别内容格式，见loadFormat
 func (filter *Filter) Load(rd io.Reader) error {
+	return filter.load("", bufio.NewReader(rd), EncodingAuto, nil)
+}
+
+// load 从source加载词典并记录加载状态，source仅用于LoadState
+func (filter *Filter) load(source string, buf *bufio.Reader, enc Encoding, report *LoadReport) error {
+	filter.beginLoad(source)
+	return filter.recordLoad(source, filter.loadFormat(buf, enc, "", report))
+}
+
+// loadPlain 按行加载纯文本词典，没有分类的词归入category，支持的注释和指令
+// 见addPlainLine。每行去除首尾
+// 空白和\r，超过SetMaxLineLength的行被跳过；report为nil时以ErrLineTooLong报告
+// 被跳过的行，否则整理每一行并统计
+func (filter *Filter) loadPlain(buf *bufio.Reader, category string, report *LoadReport) error {
 	filter.mu.Lock()
 	defer filter.mu.Unlock()
+	defer filter.commit()
+
+	var (
+		splitter = &lineSplitter{max: filter.maxLineLength}
+		scanner  = bufio.NewScanner(buf)
+	)
+	scanner.Buffer(make([]byte, 0, 4096), splitter.max+2)
+	scanner.Split(splitter.split)
+	for scanner.Scan() {
+		filter.addPlainLine(scanner.Text(), category, report)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
 
-	buf := bufio.NewReader(rd)
-	for {
-		line, _, err := buf.ReadLine()
-		if err != nil {
-			if err != io.EOF {
-				return err
-			}
-			break
+	if splitter.oversized > 0 {
+		if report != nil {
+			report.Lines += splitter.oversized
+			report.Oversized += splitter.oversized
+		} else {
+			return fmt.Errorf("%w: %d lines skipped", ErrLineTooLong, splitter.oversized)
 		}
-		filter.trie.Add(string(line))
 	}
-
 	return nil
 }
 
 // AddWord 添加敏感词
 func AddWord(words ...string) {
-	pkgFilter.AddWord(words...)
+	Default().AddWord(words...)
 }
 
 // AddWord 添加敏感词
 func (filter *Filter) AddWord(words ...string) {
+	words, _ = filter.intercept(MutationAdd, words)
+	if filter.enqueue(MutationAdd, words) {
+		return
+	}
 	filter.mu.Lock()
 	defer filter.mu.Unlock()
-	filter.trie.Add(words...)
+	filter.addWords(words...)
+	filter.commit()
 }
 
 // DelWord 删除敏感词
 func DelWord(words ...string) {
-	pkgFilter.DelWord(words...)
+	Default().DelWord(words...)
 }
 
 // DelWord 删除敏感词
 func (filter *Filter) DelWord(words ...string) {
+	words, _ = filter.intercept(MutationDel, words)
+	if filter.enqueue(MutationDel, words) {
+		return
+	}
 	filter.mu.Lock()
 	defer filter.mu.Unlock()
-	filter.trie.Del(words...)
+	filter.delWords(words...)
+	filter.commit()
+}
+
+// TryAddWord 校验并添加敏感词
+func TryAddWord(word string) (existed bool, err error) {
+	return Default().TryAddWord(word)
+}
+
+// TryAddWord 校验并添加敏感词，词语为空、只含空白或超过最大长度时
+// 返回错误且不做修改；existed表示该词在添加前是否已存在
+func (filter *Filter) TryAddWord(word string) (existed bool, err error) {
+	if _, err := filter.intercept(MutationAdd, []string{word}); err != nil {
+		return false, err
+	}
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	if err := filter.checkWord(word); err != nil {
+		return false, err
+	}
+	existed = filter.matcher().Has(word)
+	if !existed {
+		filter.addWords(word)
+		filter.commit()
+	}
+	return existed, nil
+}
+
+// TryDelWord 校验并删除敏感词
+func TryDelWord(word string) (existed bool, err error) {
+	return Default().TryDelWord(word)
+}
+
+// TryDelWord 校验并删除敏感词，existed表示该词在删除前是否存在
+func (filter *Filter) TryDelWord(word string) (existed bool, err error) {
+	if _, err := filter.intercept(MutationDel, []string{word}); err != nil {
+		return false, err
+	}
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	if err := filter.checkWord(word); err != nil {
+		return false, err
+	}
+	existed = filter.matcher().Has(word)
+	if existed {
+		filter.delWords(word)
+		filter.commit()
+	}
+	return existed, nil
+}
+
+// checkWord 校验词语是否合法
+func (filter *Filter) checkWord(word string) error {
+	if word == "" {
+		return ErrEmptyWord
+	}
+	if strings.TrimSpace(word) == "" {
+		return ErrBlankWord
+	}
+	if filter.maxWordLength > 0 && utf8.RuneCountInString(word) > filter.maxWordLength {
+		return ErrWordTooLong
+	}
+	return nil
+}
+
+// SetMaxWordLength 设置TryAddWord允许的最大词长
+func SetMaxWordLength(n int) {
+	Default().SetMaxWordLength(n)
+}
+
+// SetMaxWordLength 设置TryAddWord允许的最大词长(rune数)，n<=0表示不限制
+func (filter *Filter) SetMaxWordLength(n int) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.maxWordLength = n
 }
 
 // FilterWord 过滤敏感词
 func FilterWord(text string) string {
-	return pkgFilter.FilterWord(text)
+	return Default().FilterWord(text)
 }
 
 // FilterWord 过滤敏感词
 func (filter *Filter) FilterWord(text string) string {
+	if snap := filter.published.Load(); snap != nil {
+		result := snap.filterWord(text)
+		filter.countSnapshot(snap, text, result != text)
+		return result
+	}
 	filter.mu.RLock()
 	defer filter.mu.RUnlock()
-	return filter.trie.Filter(text)
+	result := filter.filterWord(text)
+	filter.count(text, result != text)
+	return result
+}
+
+// filterWord FilterWord的实现，调用方需持有锁
+func (filter *Filter) filterWord(text string) string {
+	text, err := filter.input(text)
+	if err != nil {
+		return ""
+	}
+	if filter.skip(text) {
+		return text
+	}
+	if filter.spanMode() || filter.throughNoise {
+		return filter.applyActions(text, Action{Kind: ActionBlock})
+	}
+	return filter.matcher().FilterWithPolicy(text, filter.policy)
 }
 
 // Replace 和谐敏感词
 func Replace(text string, repl rune) string {
-	return pkgFilter.Replace(text, repl)
+	return Default().Replace(text, repl)
 }
 
 // Replace 和谐敏感词
 func (filter *Filter) Replace(text string, repl rune) string {
+	if snap := filter.published.Load(); snap != nil {
+		result := snap.replaceCached(text, repl)
+		filter.countSnapshot(snap, text, result != text)
+		return result
+	}
 	filter.mu.RLock()
 	defer filter.mu.RUnlock()
-	return filter.trie.Replace(text, repl)
+	result := filter.replaceCached(text, repl)
+	filter.count(text, result != text)
+	return result
+}
+
+// replace Replace的实现，调用方需持有锁
+func (filter *Filter) replace(text string, repl rune) string {
+	text, err := filter.input(text)
+	if err != nil {
+		return ""
+	}
+	if filter.skip(text) {
+		return text
+	}
+	if filter.spanMode() || filter.throughNoise {
+		return filter.applyActions(text, Action{Kind: ActionReplace, Rune: repl})
+	}
+	return filter.matcher().ReplaceWithPolicy(text, repl, filter.policy)
 }
 
 // FindIn 检测敏感词
 func FindIn(text string) (bool, string) {
-	return pkgFilter.FindIn(text)
+	return Default().FindIn(text)
 }
 
 // FindIn 检测敏感词
 func (filter *Filter) FindIn(text string) (bool, string) {
+	if snap := filter.published.Load(); snap != nil {
+		found, word := snap.findInCached(text)
+		filter.countSnapshot(snap, text, found)
+		return found, word
+	}
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	found, word := filter.findInCached(text)
+	filter.count(text, found)
+	return found, word
+}
+
+// findIn FindIn的实现，调用方需持有锁
+func (filter *Filter) findIn(text string) (bool, string) {
+	text, err := filter.input(text)
+	if err != nil {
+		return false, ""
+	}
+	text = filter.removeNoise(text)
+	if filter.skip(text) {
+		return false, ""
+	}
+	if filter.spanMode() {
+		if matches := filter.allMatches(text); len(matches) > 0 {
+			return true, matches[0].Word
+		}
+		return false, ""
+	}
+	return filter.matcher().FindIn(text)
+}
+
+// FindInIndex 检测敏感词并返回其在原文中的位置
+func FindInIndex(text string) (found bool, word string, runeOffset, byteOffset int) {
+	return Default().FindInIndex(text)
+}
+
+// FindInIndex 同FindIn，另外返回第一个敏感词在原文(去噪之前)中的rune下标
+// 和字节下标，便于向用户标出问题所在；未命中时两个下标均为-1
+func (filter *Filter) FindInIndex(text string) (found bool, word string, runeOffset, byteOffset int) {
 	filter.mu.RLock()
 	defer filter.mu.RUnlock()
-	text = filter.noise.ReplaceAllString(text, "")
-	return filter.trie.FindIn(text)
+
+	text, err := filter.input(text)
+	if err != nil {
+		return false, "", -1, -1
+	}
+	runes, index := normalize(text, filter.noiseNormalizers())
+	cleaned := string(runes)
+	if filter.skip(cleaned) {
+		return false, "", -1, -1
+	}
+
+	var matches []Match
+	if filter.spanMode() {
+		matches = filter.allMatches(cleaned)
+	} else {
+		matches = filter.matcher().matchIndex(cleaned, MatchShortest, false)
+	}
+	if len(matches) == 0 {
+		return false, "", -1, -1
+	}
+
+	runeOffset = index[matches[0].Start]
+	return true, matches[0].Word, runeOffset, byteIndex(text, runeOffset)
+}
+
+// byteIndex 返回第n个rune在text中的字节下标
+func byteIndex(text string, n int) int {
+	i := 0
+	for offset := range text {
+		if i == n {
+			return offset
+		}
+		i++
+	}
+	return len(text)
 }
 
 // FindAll 找到所有匹配词
 func FindAll(text string) []string {
-	return pkgFilter.FindAll(text)
+	return Default().FindAll(text)
 }
 
 // FindAll 找到所有匹配词
 func (filter *Filter) FindAll(text string) []string {
+	if snap := filter.published.Load(); snap != nil {
+		words := snap.findAllCached(text)
+		filter.countSnapshot(snap, text, len(words) > 0)
+		return words
+	}
 	filter.mu.RLock()
 	defer filter.mu.RUnlock()
-	return filter.trie.FindAll(text)
+	words := filter.findAllCached(text)
+	filter.count(text, len(words) > 0)
+	return words
+}
+
+// findAll FindAll的实现，调用方需持有锁
+func (filter *Filter) findAll(text string) []string {
+	text, err := filter.input(text)
+	if err != nil {
+		return nil
+	}
+	if filter.skip(text) {
+		return nil
+	}
+	if filter.spanMode() {
+		if filter.policy == MatchDefault {
+			return uniqueWords(filter.allMatches(text))
+		}
+		return uniqueWords(filter.matches(text))
+	}
+	if all, ok := filter.parallelMatches(text); ok {
+		if filter.policy == MatchDefault {
+			return uniqueWords(all)
+		}
+		return uniqueWords(selectMatches(all, filter.policy))
+	}
+	return filter.matcher().FindAllWithPolicy(text, filter.policy)
+}
+
+// FindAllWithIndex 找到所有匹配词及其位置
+func FindAllWithIndex(text string) []Match {
+	return Default().FindAllWithIndex(text)
+}
+
+// FindAllWithIndex 找到所有匹配词及其位置，不去重
+func (filter *Filter) FindAllWithIndex(text string) []Match {
+	if snap := filter.published.Load(); snap != nil {
+		matches := snap.findAllWithIndex(text)
+		filter.countSnapshot(snap, text, len(matches) > 0)
+		setOffsets(text, matches)
+		return matches
+	}
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	matches := filter.findAllWithIndex(text)
+	filter.count(text, len(matches) > 0)
+	setOffsets(text, matches)
+	return matches
+}
+
+// findAllWithIndex FindAllWithIndex的实现，调用方需持有锁
+func (filter *Filter) findAllWithIndex(text string) []Match {
+	text, err := filter.input(text)
+	if err != nil {
+		return nil
+	}
+	if filter.skip(text) {
+		return nil
+	}
+	if filter.spanMode() {
+		if filter.overlap {
+			return filter.allMatches(text)
+		}
+		return filter.matches(text)
+	}
+	if all, ok := filter.parallelMatches(text); ok {
+		if filter.overlap {
+			return all
+		}
+		return selectMatches(all, filter.policy)
+	}
+	return filter.matcher().matchIndex(text, filter.policy, filter.overlap)
 }
 
 // Validate 检测字符串是否合法
 func Validate(text string) (bool, string) {
-	return pkgFilter.Validate(text)
+	return Default().Validate(text)
 }
 
 // Validate 检测字符串是否合法
 func (filter *Filter) Validate(text string) (bool, string) {
+	if snap := filter.published.Load(); snap != nil {
+		valid, word := snap.validate(text)
+		filter.countSnapshot(snap, text, !valid)
+		return valid, word
+	}
 	filter.mu.RLock()
 	defer filter.mu.RUnlock()
-	text = filter.noise.ReplaceAllString(text, "")
-	return filter.trie.Validate(text)
+	valid, word := filter.validate(text)
+	filter.count(text, !valid)
+	return valid, word
+}
+
+// validate Validate的实现，调用方需持有锁
+func (filter *Filter) validate(text string) (bool, string) {
+	text, err := filter.input(text)
+	if err != nil {
+		return false, ""
+	}
+	text = filter.removeNoise(text)
+	if filter.skip(text) {
+		return true, ""
+	}
+	if filter.spanMode() {
+		if matches := filter.allMatches(text); len(matches) > 0 {
+			return false, matches[0].Word
+		}
+		return true, ""
+	}
+	return filter.matcher().Validate(text)
 }
 
 // Validate 检测字符串是否合法
 func ValidateWithWildcard(text string, wildcard rune) (bool, string) {
-	return pkgFilter.ValidateWithWildcard(text, wildcard)
+	return Default().ValidateWithWildcard(text, wildcard)
 }
 
 func (filter *Filter) ValidateWithWildcard(text string, wildcard rune) (bool, string) {
 	filter.mu.RLock()
 	defer filter.mu.RUnlock()
-	text = filter.noise.ReplaceAllString(text, "")
-	return filter.trie.ValidateWithWildcard(text, wildcard)
+	text, err := filter.input(text)
+	if err != nil {
+		return false, ""
+	}
+	text = filter.removeNoise(text)
+	return filter.matcher().ValidateWithWildcard(text, wildcard)
+}
+
+// ValidateWithWildcardN 检测字符串是否合法，词中的wildcard可匹配0到max个字符
+func ValidateWithWildcardN(text string, wildcard rune, max int) (bool, string) {
+	return Default().ValidateWithWildcardN(text, wildcard, max)
+}
+
+// ValidateWithWildcardN 同ValidateWithWildcard，词库中的wildcard字符可匹配
+// 0到max个任意字符，如词典中的"傻*逼"在max为3时可发现"傻逼"、"傻**逼"和
+// "傻xyz逼"。回溯的开销随max增长，max不宜过大
+func (filter *Filter) ValidateWithWildcardN(text string, wildcard rune, max int) (bool, string) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	text, err := filter.input(text)
+	if err != nil {
+		return false, ""
+	}
+	text = filter.removeNoise(text)
+	return filter.matcher().ValidateWithWildcardN(text, wildcard, max)
 }
 
 // UpdateNoisePattern 更新去噪模式
 func UpdateNoisePattern(pattern string) error {
-	return pkgFilter.UpdateNoisePattern(pattern)
+	return Default().UpdateNoisePattern(pattern)
 }
 
-// UpdateNoisePattern 更新去噪模式
+// UpdateNoisePattern 用正则更新默认的去噪模式，AddNoisePattern添加的命名模式不受影响
 func (filter *Filter) UpdateNoisePattern(pattern string) error {
 	filter.mu.Lock()
 	defer filter.mu.Unlock()
+	filter.configChanged()
 
-	noise, err := regexp.Compile(pattern)
+	noise, err := compileRegex(pattern)
 	if err != nil {
 		return err
 	}
 	filter.noise = noise
+	filter.noiseSet = nil
 	return nil
 }
 
+// Compile 将词典编译为双数组Trie
+func Compile() {
+	Default().Compile()
+}
+
+// Compile 将词典编译为双数组Trie并释放原有的Trie，大幅降低内存占用，
+// 查询结果不变。之后的AddWord、DelWord、Load会先将其还原为Trie，
+// 词典更新完成后可再次调用Compile
+func (filter *Filter) Compile() {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if filter.da != nil || filter.store != nil {
+		return
+	}
+	filter.setCompiled(NewDoubleArray(filter.trie))
+}
+
+// DumpDOT 以Graphviz格式输出词典结构
+func DumpDOT(w io.Writer, maxDepth int) error {
+	return Default().DumpDOT(w, maxDepth)
+}
+
+// DumpDOT 以Graphviz格式输出词典的前maxDepth层，maxDepth<=0时全部输出，
+// 用于调试某个词为何命中或未命中
+func (filter *Filter) DumpDOT(w io.Writer, maxDepth int) error {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.matcher().DumpDOT(w, maxDepth)
+}
+
+// SaveCompiled 将编译后的词典写入w
+func SaveCompiled(w io.Writer) error {
+	return Default().SaveCompiled(w)
+}
+
+// SaveCompiled 将编译后的词典以带版本号的二进制格式写入w，
+// 尚未调用Compile时临时编译一份写出，不改变过滤器本身
+func (filter *Filter) SaveCompiled(w io.Writer) error {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	da := filter.da
+	if da == nil {
+		da = NewDoubleArray(filter.plainTrie())
+	}
+	_, err := da.WriteTo(w)
+	return err
+}
+
+// LoadCompiled 加载SaveCompiled写出的词典
+func LoadCompiled(r io.Reader) error {
+	return Default().LoadCompiled(r)
+}
+
+// LoadCompiled 加载SaveCompiled写出的词典，替换当前的全部词语
+func (filter *Filter) LoadCompiled(r io.Reader) error {
+	da, err := ReadDoubleArray(r)
+	if err != nil {
+		return filter.recordLoad("", err)
+	}
+
+	filter.replaceCompiled(da)
+	return filter.recordLoad("", nil)
+}
+
+// LoadCompiledFile 以内存映射方式加载SaveCompiled写出的词典文件
+func LoadCompiledFile(path string) error {
+	return Default().LoadCompiledFile(path)
+}
+
+// LoadCompiledFile 以内存映射方式加载SaveCompiled写出的词典文件，
+// 替换当前的全部词语。多个进程加载同一文件时共享物理内存，
+// 映射在词典被替换或修改时释放
+func (filter *Filter) LoadCompiledFile(path string) error {
+	da, err := OpenDoubleArray(path)
+	if err != nil {
+		return filter.recordLoad(path, err)
+	}
+
+	filter.replaceCompiled(da)
+	return filter.recordLoad(path, nil)
+}
+
+// setDict 用da替换当前的全部词语，设置了自定义存储时将词写入存储，
+// 调用方需持有写锁
+func (filter *Filter) setDict(da *DoubleArray) {
+	if filter.store != nil {
+		filter.resetStore(da.Walk)
+		return
+	}
+	filter.setCompiled(da)
+}
+
+// replaceCompiled 用da替换当前的全部词语
+func (filter *Filter) replaceCompiled(da *DoubleArray) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.setDict(da)
+	filter.rebuildPrefilter()
+	filter.pending = Delta{Reset: true}
+	filter.commit()
+}
+
+// EnablePrefilter 开启或关闭预过滤
+func EnablePrefilter(enable bool) {
+	Default().EnablePrefilter(enable)
+}
+
+// EnablePrefilter 开启或关闭预过滤。开启后维护一份词首字符位图，
+// 查询时先用它快速排除不含任何词首字符的文本，适合绝大多数文本
+// 都不含敏感词的场景。删除词语不会清除位图，只会降低排除率，不影响结果
+func (filter *Filter) EnablePrefilter(enable bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if !enable {
+		filter.prefilter = nil
+		return
+	}
+	filter.prefilter = &prefilter{}
+	filter.rebuildPrefilter()
+}
+
+// SetMatchPolicy 设置同一位置命中多个词时的匹配策略
+func SetMatchPolicy(policy MatchPolicy) {
+	Default().SetMatchPolicy(policy)
+}
+
+// SetMatchPolicy 设置同一位置命中多个词时的匹配策略，
+// 对FindAll、Replace和FilterWord同时生效
+func (filter *Filter) SetMatchPolicy(policy MatchPolicy) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.policy = policy
+}
+
+// SetOverlap 设置FindAllWithIndex是否报告相互重叠的命中
+func SetOverlap(overlap bool) {
+	Default().SetOverlap(overlap)
+}
+
+// SetOverlap 设置FindAllWithIndex是否报告相互重叠的命中，
+// 如"ABCD"中同时报告"ABC"和"BCD"
+func (filter *Filter) SetOverlap(overlap bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.overlap = overlap
+}
+
 // RemoveNoise 去除空格等噪音
 func RemoveNoise(text string) string {
-	return pkgFilter.RemoveNoise(text)
+	return Default().RemoveNoise(text)
 }
 
 // RemoveNoise 去除空格等噪音
 func (filter *Filter) RemoveNoise(text string) string {
 	filter.mu.RLock()
 	defer filter.mu.RUnlock()
-	return filter.noise.ReplaceAllString(text, "")
+	return filter.removeNoise(text)
 }
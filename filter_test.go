@@ -3,7 +3,6 @@ package sensitive
 import (
 	"io"
 	"reflect"
-	"regexp"
 	"strings"
 	"testing"
 )
@@ -62,13 +61,13 @@ func TestSensitiveFilter(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
-		if got := filter.Filter(tc.Text); got != tc.Expect {
+		if got := filter.FilterWord(tc.Text); got != tc.Expect {
 			t.Errorf("filter %s, got %s, expect %s", tc.Text, got, tc.Expect)
 		}
 
 		filter.DelWord(tc.DelWords...)
 
-		if got := filter.Filter(tc.Text); got != tc.ThenExpect {
+		if got := filter.FilterWord(tc.Text); got != tc.ThenExpect {
 			t.Errorf("after del, filter %s, got %s, expect %s", tc.Text, got, tc.ThenExpect)
 		}
 
@@ -298,7 +297,7 @@ func TestDeleteWord2(t *testing.T) {
 func TestFilter_LoadWordDict(t *testing.T) {
 	type fields struct {
 		trie  *Trie
-		noise *regexp.Regexp
+		noise regex
 	}
 	type args struct {
 		path string
@@ -327,7 +326,7 @@ func TestFilter_LoadWordDict(t *testing.T) {
 func TestFilter_LoadNetWordDict(t *testing.T) {
 	type fields struct {
 		trie  *Trie
-		noise *regexp.Regexp
+		noise regex
 	}
 	type args struct {
 		url string
@@ -356,7 +355,7 @@ func TestFilter_LoadNetWordDict(t *testing.T) {
 func TestFilter_Load(t *testing.T) {
 	type fields struct {
 		trie  *Trie
-		noise *regexp.Regexp
+		noise regex
 	}
 	type args struct {
 		rd io.Reader
@@ -0,0 +1,28 @@
+package sensitive
+
+import "testing"
+
+func TestFindInIndex(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾", "东西")
+
+	testcases := []struct {
+		Text       string
+		Found      bool
+		Word       string
+		RuneOffset int
+		ByteOffset int
+	}{
+		{"这是垃圾", true, "垃圾", 2, 6},
+		{"ab 这是 垃 圾东西", true, "垃圾", 6, 10},
+		{"没有", false, "", -1, -1},
+	}
+
+	for _, tc := range testcases {
+		found, word, runeOffset, byteOffset := filter.FindInIndex(tc.Text)
+		if found != tc.Found || word != tc.Word || runeOffset != tc.RuneOffset || byteOffset != tc.ByteOffset {
+			t.Errorf("findinindex %s, got %v %s %d %d, expect %v %s %d %d", tc.Text,
+				found, word, runeOffset, byteOffset, tc.Found, tc.Word, tc.RuneOffset, tc.ByteOffset)
+		}
+	}
+}
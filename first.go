@@ -0,0 +1,76 @@
+package sensitive
+
+// ReplaceFirst 只和谐第一个敏感词
+func ReplaceFirst(text string, repl rune) string {
+	return Default().ReplaceFirst(text, repl)
+}
+
+// ReplaceFirst 同Replace，只和谐第一个敏感词，找到后即停止扫描，
+// 适合只需要处理开头的预览摘要等场景
+func (filter *Filter) ReplaceFirst(text string, repl rune) string {
+	return filter.ReplaceN(text, repl, 1)
+}
+
+// ReplaceN 最多和谐n个敏感词
+func ReplaceN(text string, repl rune, n int) string {
+	return Default().ReplaceN(text, repl, n)
+}
+
+// ReplaceN 同Replace，按匹配策略从左到右最多和谐n个互不重叠的敏感词，
+// 其余保持原样，n不大于0时不限制
+func (filter *Filter) ReplaceN(text string, repl rune, n int) string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	result := filter.rewriteN(text, n, Action{Kind: ActionReplace, Rune: repl})
+	filter.count(text, result != text)
+	return result
+}
+
+// FilterWordFirst 只过滤第一个敏感词
+func FilterWordFirst(text string) string {
+	return Default().FilterWordFirst(text)
+}
+
+// FilterWordFirst 同FilterWord，只过滤第一个敏感词，找到后即停止扫描
+func (filter *Filter) FilterWordFirst(text string) string {
+	return filter.FilterWordN(text, 1)
+}
+
+// FilterWordN 最多过滤n个敏感词
+func FilterWordN(text string, n int) string {
+	return Default().FilterWordN(text, n)
+}
+
+// FilterWordN 同FilterWord，按匹配策略从左到右最多过滤n个互不重叠的敏感词，
+// n不大于0时不限制
+func (filter *Filter) FilterWordN(text string, n int) string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	result := filter.rewriteN(text, n, Action{Kind: ActionBlock})
+	filter.count(text, result != text)
+	return result
+}
+
+// rewriteN 按分类动作改写text中的前n个命中，fallback为未设置动作时的处理，
+// 调用方需持有锁
+func (filter *Filter) rewriteN(text string, n int, fallback Action) string {
+	text, err := filter.input(text)
+	if err != nil {
+		return ""
+	}
+	if filter.skip(text) {
+		return text
+	}
+
+	var matches []Match
+	if filter.throughNoise {
+		matches = filter.noiseMatches(text)
+		if n > 0 && len(matches) > n {
+			matches = matches[:n]
+		}
+	} else {
+		matches = filter.limitedMatches(text, n)
+	}
+	result, _ := filter.rewriteMatches(text, matches, fallback)
+	return result
+}
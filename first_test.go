@@ -0,0 +1,36 @@
+package sensitive
+
+import "testing"
+
+func TestReplaceFirst(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾", "东西")
+
+	text := "垃圾东西，还是垃圾"
+	if got := filter.ReplaceFirst(text, '*'); got != "**东西，还是垃圾" {
+		t.Errorf("replace first, got %s", got)
+	}
+	if got := filter.ReplaceN(text, '*', 2); got != "****，还是垃圾" {
+		t.Errorf("replace n, got %s", got)
+	}
+	if got := filter.ReplaceN(text, '*', 0); got != filter.Replace(text, '*') {
+		t.Errorf("replace n without limit, got %s", got)
+	}
+	if got := filter.FilterWordFirst(text); got != "东西，还是垃圾" {
+		t.Errorf("filter word first, got %s", got)
+	}
+	if got := filter.FilterWordN(text, 3); got != "，还是" {
+		t.Errorf("filter word n, got %s", got)
+	}
+
+	// 分类动作和跨噪音匹配同样适用
+	filter.AddWordWithCategory("ad", "加微信")
+	filter.SetCategoryAction("ad", Action{Kind: ActionReplaceString, String: "[广告]"})
+	if got := filter.ReplaceFirst("加微信买东西", '*'); got != "[广告]买东西" {
+		t.Errorf("replace first with category action, got %s", got)
+	}
+	filter.SetMatchThroughNoise(true)
+	if got := filter.ReplaceFirst("垃 圾东 西", '*'); got != "***东 西" {
+		t.Errorf("replace first through noise, got %s", got)
+	}
+}
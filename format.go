@@ -0,0 +1,173 @@
+package sensitive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// sniffSize 识别格式时最多预读的字节数
+const sniffSize = 4096
+
+// loadFormat 识别词典格式并交给对应的解析器，非UTF-8编码的内容先转为UTF-8，
+// 编码的识别见EncodingAuto：
+//   - gzip：以1f 8b开头，解压后再识别一次
+//   - JSON：以[或{开头且能解析为字符串数组、{"word","category"}对象数组
+//     或{"words":[...]}之一，否则按纯文本处理
+//   - CSV：开头几行都含有相同个数的逗号，第一列为词，第二列为分类(可选)，
+//     第一行为word表头时跳过
+//   - 其余按每行一个词的纯文本处理
+//
+// 没有分类的词归入category
+func (filter *Filter) loadFormat(buf *bufio.Reader, enc Encoding, category string, report *LoadReport) error {
+	head, _ := buf.Peek(sniffSize)
+	if len(head) >= 2 && head[0] == 0x1f && head[1] == 0x8b {
+		gz, err := gzip.NewReader(buf)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return filter.loadFormat(bufio.NewReader(gz), enc, category, report)
+	}
+
+	if enc == EncodingAuto {
+		enc = detectEncoding(head)
+	}
+	if enc == EncodingUTF8 && bytes.HasPrefix(head, bomUTF8) {
+		buf.Discard(len(bomUTF8))
+		head, _ = buf.Peek(sniffSize)
+	} else if enc != EncodingUTF8 {
+		content, err := decodeAll(buf, enc)
+		if err != nil {
+			return err
+		}
+		return filter.loadFormat(bufio.NewReader(bytes.NewReader(content)), EncodingUTF8, category, report)
+	}
+
+	switch {
+	case looksLikeJSON(head):
+		content, err := io.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		if entries, ok := parseJSONDict(content); ok {
+			filter.loadEntries(entries, category, report)
+			return nil
+		}
+		return filter.loadPlain(bufio.NewReader(bytes.NewReader(content)), category, report)
+	case looksLikeCSV(head):
+		entries, err := parseCSVDict(buf)
+		if err != nil {
+			return err
+		}
+		filter.loadEntries(entries, category, report)
+		return nil
+	}
+	return filter.loadPlain(buf, category, report)
+}
+
+// dictEntry 结构化词典中的一项
+type dictEntry struct {
+	Word     string `json:"word"`
+	Category string `json:"category"`
+}
+
+// loadEntries 在一次修改中加载结构化词典，没有分类的词归入category
+func (filter *Filter) loadEntries(entries []dictEntry, category string, report *LoadReport) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	for _, entry := range entries {
+		if entry.Category == "" {
+			entry.Category = category
+		}
+		filter.addLine(entry.Word, entry.Category, report)
+	}
+	filter.commit()
+}
+
+func looksLikeJSON(head []byte) bool {
+	head = bytes.TrimLeft(head, " \t\r\n\uFEFF")
+	return len(head) > 0 && (head[0] == '[' || head[0] == '{')
+}
+
+// parseJSONDict 解析JSON词典，格式不符时返回false
+func parseJSONDict(content []byte) ([]dictEntry, bool) {
+	var words []string
+	if json.Unmarshal(content, &words) == nil {
+		entries := make([]dictEntry, len(words))
+		for i, word := range words {
+			entries[i].Word = word
+		}
+		return entries, true
+	}
+
+	var entries []dictEntry
+	if json.Unmarshal(content, &entries) == nil {
+		return entries, true
+	}
+
+	var object struct {
+		Words *[]string `json:"words"`
+	}
+	if json.Unmarshal(content, &object) == nil && object.Words != nil {
+		entries := make([]dictEntry, len(*object.Words))
+		for i, word := range *object.Words {
+			entries[i].Word = word
+		}
+		return entries, true
+	}
+	return nil, false
+}
+
+// looksLikeCSV 预读内容中的完整行(至少两行)都含有相同个数的逗号时视为CSV
+func looksLikeCSV(head []byte) bool {
+	end := bytes.LastIndexByte(head, '\n')
+	if end < 0 {
+		return false
+	}
+	lines := strings.Split(strings.TrimRight(string(head[:end]), "\r\n"), "\n")
+	if len(lines) < 2 {
+		return false
+	}
+	commas := strings.Count(lines[0], ",")
+	if commas == 0 {
+		return false
+	}
+	for _, line := range lines[1:] {
+		if strings.Count(line, ",") != commas {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCSVDict(rd io.Reader) ([]dictEntry, error) {
+	r := csv.NewReader(rd)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var entries []dictEntry
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := dictEntry{Word: strings.TrimSpace(record[0])}
+		if entries == nil && entry.Word == "word" {
+			entries = []dictEntry{}
+			continue
+		}
+		if len(record) > 1 {
+			entry.Category = strings.TrimSpace(record[1])
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,56 @@
+package sensitive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+func TestLoadFormats(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte("垃圾\n东西\n"))
+	w.Close()
+
+	tests := []struct {
+		name     string
+		content  []byte
+		words    []string
+		category map[string]string
+	}{
+		{"plain", []byte("垃圾\n东西\n"), []string{"垃圾", "东西"}, nil},
+		{"gzip", gz.Bytes(), []string{"垃圾", "东西"}, nil},
+		{"json strings", []byte(` ["垃圾", "东西"]`), []string{"垃圾", "东西"}, nil},
+		{"json objects", []byte(`[{"word": "加微信", "category": "ad"}, {"word": "垃圾"}]`),
+			[]string{"加微信", "垃圾"}, map[string]string{"加微信": "ad"}},
+		{"json words", []byte(`{"words": ["垃圾"]}`), []string{"垃圾"}, nil},
+		{"not json", []byte("[广告]\n垃圾\n"), []string{"[广告]", "垃圾"}, nil},
+		{"csv", []byte("word,category\n加微信,ad\n垃圾,\n"), []string{"加微信", "垃圾"}, map[string]string{"加微信": "ad"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := New()
+			if err := filter.LoadBytes(tt.content); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			filter.trie.Walk(func(word string) bool {
+				got = append(got, word)
+				return true
+			})
+			if len(got) != len(tt.words) {
+				t.Fatalf("got words %q, expect %q", got, tt.words)
+			}
+			for _, word := range tt.words {
+				if !filter.trie.Has(word) {
+					t.Errorf("missing %s", word)
+				}
+			}
+			for word, category := range tt.category {
+				if filter.Category(word) != category {
+					t.Errorf("category of %s, got %s", word, filter.Category(word))
+				}
+			}
+		})
+	}
+}
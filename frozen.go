@@ -0,0 +1,190 @@
+package sensitive
+
+// FrozenFilter 只读的过滤器，由Filter.Freeze生成。词典固定为双数组并始终
+// 开启预过滤，查询时不加锁，适合启动时加载一次词典、之后不再修改的场景
+type FrozenFilter struct {
+	filter *Filter
+}
+
+// Freeze 冻结当前词典
+func Freeze() *FrozenFilter {
+	return Default().Freeze()
+}
+
+// Freeze 以当前词典和配置生成只读的FrozenFilter，之后对filter的修改
+// 不会影响已生成的FrozenFilter
+func (filter *Filter) Freeze() *FrozenFilter {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return &FrozenFilter{filter: filter.frozen()}
+}
+
+// frozen 复制当前词典和全部影响查询的配置，返回的Filter只用于不加锁的查询，
+// 调用方需持有锁
+func (filter *Filter) frozen() *Filter {
+	frozen := &Filter{
+		da:            filter.compiledTable(),
+		noise:         filter.noise,
+		noiseSet:      filter.noiseSet,
+		noisePatterns: append([]noisePattern(nil), filter.noisePatterns...),
+		noiseFunc:     filter.noiseFunc,
+		throughNoise:  filter.throughNoise,
+		policy:        filter.policy,
+		overlap:       filter.overlap,
+		prefilter:     &prefilter{},
+		version:       filter.version,
+		meta:          copyMap(filter.meta),
+		usePriority:   filter.usePriority,
+		actions:       copyMap(filter.actions),
+		logHandler:    filter.logHandler,
+		sampler:       filter.sampler,
+		sampleRate:    filter.sampleRate,
+		events:        filter.events,
+		tracer:        filter.tracer,
+		exceptions:    copyMap(filter.exceptions),
+		rules:         copyMap(filter.rules),
+		ruleTrie:      filter.ruleTrie,
+		skipLinks:     filter.skipLinks,
+		tokenizer:     filter.tokenizer,
+		reverse:       filter.reverse,
+		separators:    filter.separators,
+		mergeSpans:    filter.mergeSpans,
+		lineJoin:      filter.lineJoin,
+		usernameSeps:  filter.usernameSeps,
+		profiles:      copyMap(filter.profiles),
+		maxTextLen:    filter.maxTextLen,
+		truncateText:  filter.truncateText,
+		utf8Policy:    filter.utf8Policy,
+	}
+	// 并行匹配、结果缓存和读音索引带有按版本更新的状态，副本各用一份
+	if p := filter.parallel; p != nil {
+		frozen.parallel = &parallelScan{minRunes: p.minRunes, workers: p.workers}
+	}
+	if filter.cache != nil {
+		frozen.cache = newResultCache(filter.cache.size)
+	}
+	if filter.phonetic != nil {
+		frozen.phonetic = &phoneticIndex{encode: filter.phonetic.encode}
+	}
+	frozen.rebuildPrefilter()
+	return frozen
+}
+
+// compiledDict 某一版本词典编译后的双数组
+type compiledDict struct {
+	version uint64
+	trie    *Trie
+	source  *DoubleArray
+	da      *DoubleArray
+}
+
+// compiledTable 返回带直接映射表的compiledCopy。词典没有修改时复用上一次的
+// 结果，连续调用Freeze或发布只读副本时不重复编译。自定义存储可能在过滤器
+// 之外被修改，每次都重新构建，调用方需持有锁
+func (filter *Filter) compiledTable() *DoubleArray {
+	if filter.store != nil {
+		return filter.compiledCopy().withCodeTable()
+	}
+	if c := filter.compiled.Load(); c != nil && c.version == filter.version &&
+		c.trie == filter.trie && c.source == filter.da {
+		return c.da
+	}
+	da := filter.compiledCopy().withCodeTable()
+	filter.compiled.Store(&compiledDict{version: filter.version, trie: filter.trie, source: filter.da, da: da})
+	return da
+}
+
+// compiledCopy 返回当前词典的双数组形式，可在filter之后被修改时继续使用，
+// 调用方需持有锁
+func (filter *Filter) compiledCopy() *DoubleArray {
+	switch {
+	case filter.da == nil:
+		return NewDoubleArray(filter.plainTrie())
+	case filter.da.mapping != nil:
+		return filter.da.clone()
+	default:
+		return filter.da
+	}
+}
+
+// withCodeTable 返回带BMP直接映射表的浅拷贝，字符数超出uint16范围时返回da本身
+func (da *DoubleArray) withCodeTable() *DoubleArray {
+	if len(da.alphabet) >= 1<<16 {
+		return da
+	}
+	indexed := *da
+	indexed.codes = make([]uint16, 1<<16)
+	for i, r := range da.alphabet {
+		if r >= 1<<16 {
+			break
+		}
+		indexed.codes[r] = uint16(i + 1)
+	}
+	return &indexed
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	result := make(map[K]V, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// Has 判断word是否为词典中的一个词
+func (frozen *FrozenFilter) Has(word string) bool {
+	return frozen.filter.da.Has(word)
+}
+
+// FilterWord 过滤敏感词
+func (frozen *FrozenFilter) FilterWord(text string) string {
+	return frozen.filter.filterWord(text)
+}
+
+// Replace 和谐敏感词
+func (frozen *FrozenFilter) Replace(text string, repl rune) string {
+	return frozen.filter.replaceCached(text, repl)
+}
+
+// FindIn 检测敏感词
+func (frozen *FrozenFilter) FindIn(text string) (bool, string) {
+	return frozen.filter.findInCached(text)
+}
+
+// FindAll 找到所有匹配词
+func (frozen *FrozenFilter) FindAll(text string) []string {
+	return frozen.filter.findAllCached(text)
+}
+
+// FindAllWithIndex 找到所有匹配词及其位置，不去重
+func (frozen *FrozenFilter) FindAllWithIndex(text string) []Match {
+	matches := frozen.filter.findAllWithIndex(text)
+	setOffsets(text, matches)
+	return matches
+}
+
+// Validate 检测字符串是否合法
+func (frozen *FrozenFilter) Validate(text string) (bool, string) {
+	return frozen.filter.validate(text)
+}
+
+// ValidateN 检查text，最多收集n个命中
+func (frozen *FrozenFilter) ValidateN(text string, n int) ValidationResult {
+	return frozen.filter.validateN(text, n)
+}
+
+// FilterWithDecision 和谐敏感词并返回命中和是否应拒绝整段文本
+func (frozen *FrozenFilter) FilterWithDecision(text string, repl rune) (string, []Match, bool) {
+	return frozen.filter.filterWithDecision(text, repl)
+}
+
+// FilterWithDetails 和谐敏感词并返回被处理的命中
+func (frozen *FrozenFilter) FilterWithDetails(text string, repl rune) (string, []Match) {
+	if frozen.filter.skip(text) {
+		return text, nil
+	}
+	return frozen.filter.rewrite(text, Action{Kind: ActionReplace, Rune: repl})
+}
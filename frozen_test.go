@@ -0,0 +1,245 @@
+package sensitive
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	filter := New()
+	filter.AddWord("东西", "坏人")
+	frozen := filter.Freeze()
+
+	filter.AddWord("好人")
+	filter.DelWord("东西")
+
+	if !frozen.Has("东西") || frozen.Has("好人") {
+		t.Errorf("frozen filter should not see later changes")
+	}
+	if got := frozen.Replace("好人有东西", '*'); got != "好人有**" {
+		t.Errorf("replace, got %s", got)
+	}
+	if got := frozen.FilterWord("坏人有东西"); got != "有" {
+		t.Errorf("filter, got %s", got)
+	}
+	if found, word := frozen.FindIn("坏 人"); !found || word != "坏人" {
+		t.Errorf("findin should remove noise, got %v %s", found, word)
+	}
+	if ok, _ := frozen.Validate("好人"); !ok {
+		t.Errorf("validate should pass")
+	}
+	if got := frozen.FindAll("东西坏人东西"); len(got) != 2 {
+		t.Errorf("findall, got %v", got)
+	}
+	if got := frozen.FindAllWithIndex("有东西"); len(got) != 1 || got[0] != (Match{Word: "东西", Start: 1, End: 3, ByteStart: 3, ByteEnd: 9, UTF16Start: 1, UTF16End: 3}) {
+		t.Errorf("findall with index, got %v", got)
+	}
+}
+
+func TestFreezeKeepsCategoryActions(t *testing.T) {
+	filter := New()
+	filter.AddWordWithCategory("ad", "加微信")
+	filter.SetCategoryAction("ad", Action{Kind: ActionReplaceString, String: "[广告]"})
+	frozen := filter.Freeze()
+	filter.SetCategoryAction("ad", Action{Kind: ActionBlock})
+
+	if got, matches := frozen.FilterWithDetails("请加微信", '*'); got != "请[广告]" || len(matches) != 1 {
+		t.Errorf("got %s %v", got, matches)
+	}
+}
+
+func TestFreezeConcurrent(t *testing.T) {
+	filter := New()
+	filter.AddWord("东西")
+	frozen := filter.Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if got := frozen.Replace("我有东西", '*'); got != "我有**" {
+					t.Errorf("got %s", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// featureCase 一组词典和配置，以及用来比较查询结果的文本
+type featureCase struct {
+	name  string
+	new   func() *Filter
+	setup func(filter *Filter)
+	texts []string
+}
+
+var featureCases = []featureCase{
+	{
+		name:  "noise",
+		setup: func(filter *Filter) { filter.AddWord("垃圾", "坏人") },
+		texts: []string{"这是垃 圾", "坏人坏人", "没有"},
+	},
+	{
+		name: "separators",
+		setup: func(filter *Filter) {
+			filter.AddWord("色情", "网站")
+			filter.SetSeparators("-.")
+		},
+		texts: []string{"色-情网.站", "色--情", "色情"},
+	},
+	{
+		name: "through noise",
+		setup: func(filter *Filter) {
+			filter.AddWord("垃圾")
+			filter.SetMatchThroughNoise(true)
+		},
+		texts: []string{"垃 圾", "垃*圾*"},
+	},
+	{
+		name: "exceptions and actions",
+		setup: func(filter *Filter) {
+			filter.AddWordWithCategory("ad", "加微信")
+			filter.AddWord("河蟹")
+			filter.SetCategoryAction("ad", Action{Kind: ActionReplaceString, String: "[广告]"})
+			if err := filter.AddException("河蟹", "", "(汤|粥)"); err != nil {
+				panic(err)
+			}
+		},
+		texts: []string{"请加微信", "河蟹汤", "河蟹河蟹粥"},
+	},
+	{
+		name: "priority",
+		setup: func(filter *Filter) {
+			filter.AddWordWithCategory("brand", "苹果手机")
+			filter.AddWord("苹果")
+			filter.SetPriority(10, "苹果手机")
+			filter.SetCategoryAction("brand", Action{Kind: ActionAllow})
+		},
+		texts: []string{"买苹果手机", "吃苹果"},
+	},
+	{
+		name: "policy and reverse",
+		setup: func(filter *Filter) {
+			filter.AddWord("东西", "东西南北", "坏人")
+			filter.SetMatchPolicy(MatchShortest)
+			filter.SetMatchReversed(true)
+		},
+		texts: []string{"东西南北", "人坏了"},
+	},
+	{
+		name: "links and merge",
+		setup: func(filter *Filter) {
+			filter.AddWord("sex", "色情", "情网")
+			filter.SetSkipLinks(true)
+			filter.SetMergeSpans(true)
+		},
+		texts: []string{"see https://sex.example.com", "色情网"},
+	},
+	{
+		name: "parallel",
+		setup: func(filter *Filter) {
+			filter.AddWord("垃圾", "坏人")
+			filter.SetParallel(4, 3)
+		},
+		texts: []string{"这是一段很长的文本，里面有垃圾也有坏人，垃圾坏人", "垃圾"},
+	},
+	{
+		name:  "store",
+		new:   func() *Filter { return NewWithStore(mapStore{}) },
+		setup: func(filter *Filter) { filter.AddWord("垃圾", "坏人") },
+		texts: []string{"这是垃圾", "坏 人"},
+	},
+	{
+		name: "text limit",
+		setup: func(filter *Filter) {
+			filter.AddWord("垃圾")
+			filter.SetMaxTextLen(9, true)
+		},
+		texts: []string{"垃圾在很后面的垃圾", "垃圾"},
+	},
+}
+
+// filter 按c构造过滤器并设置词典和配置
+func (c featureCase) filter() *Filter {
+	filter := New()
+	if c.new != nil {
+		filter = c.new()
+	}
+	c.setup(filter)
+	return filter
+}
+
+// queryResults 用FrozenFilter也有的查询方法检查text，返回全部结果
+func queryResults(q interface {
+	FindIn(string) (bool, string)
+	FindAll(string) []string
+	FindAllWithIndex(string) []Match
+	Validate(string) (bool, string)
+	ValidateN(string, int) ValidationResult
+	Replace(string, rune) string
+	FilterWord(string) string
+	FilterWithDetails(string, rune) (string, []Match)
+	FilterWithDecision(string, rune) (string, []Match, bool)
+}, text string) []interface{} {
+	found, word := q.FindIn(text)
+	valid, invalid := q.Validate(text)
+	details, detailMatches := q.FilterWithDetails(text, '*')
+	decided, decidedMatches, reject := q.FilterWithDecision(text, '*')
+	return []interface{}{
+		found, word, q.FindAll(text), q.FindAllWithIndex(text), valid, invalid,
+		q.ValidateN(text, 3), q.Replace(text, '*'), q.FilterWord(text),
+		details, detailMatches, decided, decidedMatches, reject,
+	}
+}
+
+func TestFreezeParity(t *testing.T) {
+	for _, c := range featureCases {
+		filter := c.filter()
+		frozen := filter.Freeze()
+		for _, text := range c.texts {
+			want, got := queryResults(filter, text), queryResults(frozen, text)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s %q: frozen %v, live %v", c.name, text, got, want)
+			}
+		}
+	}
+}
+
+func TestFreezeReusesCompiled(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	first := filter.Freeze()
+	if second := filter.Freeze(); second.filter.da != first.filter.da {
+		t.Errorf("unchanged dictionary compiled again")
+	}
+	filter.AddWord("坏人")
+	third := filter.Freeze()
+	if third.filter.da == first.filter.da || !third.Has("坏人") || first.Has("坏人") {
+		t.Errorf("changed dictionary not compiled again")
+	}
+}
+
+func BenchmarkFrozenReplace(b *testing.B) {
+	frozen := benchFilter().Freeze()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			frozen.Replace(benchText, '*')
+		}
+	})
+}
+
+func BenchmarkFilterReplaceParallel(b *testing.B) {
+	filter := benchFilter()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			filter.Replace(benchText, '*')
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package sensitive
+
+// MutationOp 词典修改的类型
+type MutationOp int
+
+const (
+	// MutationAdd 添加词语
+	MutationAdd MutationOp = iota
+	// MutationDel 删除词语
+	MutationDel
+)
+
+func (op MutationOp) String() string {
+	if op == MutationDel {
+		return "del"
+	}
+	return "add"
+}
+
+// Interceptor 词典修改的拦截器，返回非nil的错误时拒绝这次修改
+type Interceptor func(op MutationOp, word string) error
+
+// SetInterceptor 设置默认过滤器的修改拦截器
+func SetInterceptor(fn Interceptor) {
+	Default().SetInterceptor(fn)
+}
+
+// SetInterceptor 设置AddWord、AddWordWithCategory、AddWordTTL、AddWordSchedule、
+// DelWord、TryAddWord、TryDelWord和TryApplyDelta的拦截器，每个词修改前先调用fn，
+// 可用于校验、限流或接入审批流程。fn在不持有锁时调用，可以阻塞等待审批，
+// 但不能再修改filter。AddWord等没有返回值的方法跳过被拒绝的词，
+// TryAddWord、TryDelWord和TryApplyDelta返回fn的错误。加载词典、ApplyDelta等
+// 批量修改不经过拦截器，需要时先用CheckMutation检查。fn为nil时取消
+func (filter *Filter) SetInterceptor(fn Interceptor) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.interceptor = fn
+}
+
+// CheckMutation 用默认过滤器的拦截器检查修改
+func CheckMutation(op MutationOp, words ...string) error {
+	return Default().CheckMutation(op, words...)
+}
+
+// CheckMutation 用拦截器检查words的修改而不实际修改，返回第一个拒绝的错误，
+// 没有设置拦截器时返回nil。用于批量导入等不经过拦截器的修改
+func (filter *Filter) CheckMutation(op MutationOp, words ...string) error {
+	_, err := filter.intercept(op, words)
+	return err
+}
+
+// TryApplyDelta 经过拦截器原子地应用一次增量更新
+func TryApplyDelta(added, removed []string) error {
+	return Default().TryApplyDelta(added, removed)
+}
+
+// TryApplyDelta 同ApplyDelta，但added和removed中的每个词都先经过拦截器，
+// 任一词被拒绝时不做任何修改并返回第一个拒绝的错误
+func (filter *Filter) TryApplyDelta(added, removed []string) error {
+	if err := filter.CheckMutation(MutationAdd, added...); err != nil {
+		return err
+	}
+	if err := filter.CheckMutation(MutationDel, removed...); err != nil {
+		return err
+	}
+	filter.ApplyDelta(added, removed)
+	return nil
+}
+
+// intercept 返回拦截器允许的词及第一个拒绝的错误，调用方不能持有锁
+func (filter *Filter) intercept(op MutationOp, words []string) ([]string, error) {
+	filter.mu.RLock()
+	fn := filter.interceptor
+	filter.mu.RUnlock()
+	if fn == nil {
+		return words, nil
+	}
+
+	var (
+		allowed  = make([]string, 0, len(words))
+		rejected error
+	)
+	for _, word := range words {
+		if err := fn(op, word); err != nil {
+			if rejected == nil {
+				rejected = err
+			}
+			continue
+		}
+		allowed = append(allowed, word)
+	}
+	return allowed, rejected
+}
@@ -0,0 +1,72 @@
+package sensitive
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInterceptor(t *testing.T) {
+	var (
+		filter     = New()
+		errDenied  = errors.New("denied")
+		operations []string
+	)
+	filter.SetInterceptor(func(op MutationOp, word string) error {
+		operations = append(operations, op.String()+":"+word)
+		if word == "保护词" {
+			return errDenied
+		}
+		return nil
+	})
+
+	filter.AddWord("色情", "保护词")
+	filter.AddWordWithCategory("ad", "加微信")
+	if !filter.HasWord("色情") || filter.HasWord("保护词") || filter.Category("加微信") != "ad" {
+		t.Errorf("add word with interceptor")
+	}
+	if _, err := filter.TryAddWord("保护词"); !errors.Is(err, errDenied) {
+		t.Errorf("try add rejected word, got %v", err)
+	}
+	filter.DelWord("色情")
+	if filter.HasWord("色情") {
+		t.Errorf("del word with interceptor")
+	}
+	if existed, err := filter.TryDelWord("加微信"); !existed || err != nil {
+		t.Errorf("try del word, got %v %v", existed, err)
+	}
+
+	// 加载词典不经过拦截器
+	if err := filter.LoadBytes([]byte("保护词\n")); err != nil || !filter.HasWord("保护词") {
+		t.Errorf("load should bypass the interceptor, got %v", err)
+	}
+
+	expect := "add:色情,add:保护词,add:加微信,add:保护词,del:色情,del:加微信"
+	if got := strings.Join(operations, ","); got != expect {
+		t.Errorf("intercepted operations, got %s, expect %s", got, expect)
+	}
+
+	// TTL、生效时间和批量增量同样经过拦截器
+	filter.ApplyDelta(nil, []string{"保护词"})
+	filter.AddWordTTL("保护词", time.Hour)
+	filter.AddWordSchedule("保护词", Schedule{})
+	if filter.HasWord("保护词") || len(filter.schedules) != 0 {
+		t.Errorf("ttl and schedule adds should be intercepted")
+	}
+	if err := filter.TryApplyDelta([]string{"新词", "保护词"}, nil); !errors.Is(err, errDenied) || filter.HasWord("新词") {
+		t.Errorf("try apply delta with rejected word, got %v", err)
+	}
+	if err := filter.TryApplyDelta([]string{"新词"}, []string{"加微信"}); err != nil || !filter.HasWord("新词") {
+		t.Errorf("try apply delta, got %v", err)
+	}
+	if err := filter.CheckMutation(MutationAdd, "保护词"); !errors.Is(err, errDenied) {
+		t.Errorf("check mutation, got %v", err)
+	}
+
+	filter.SetInterceptor(nil)
+	filter.AddWord("保护词2")
+	if !filter.HasWord("保护词2") {
+		t.Errorf("add word after interceptor removed")
+	}
+}
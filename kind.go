@@ -0,0 +1,123 @@
+package sensitive
+
+import "sort"
+
+// MatchKind 命中的方式
+type MatchKind int
+
+const (
+	// KindExact 原文与词典中的词完全相同
+	KindExact MatchKind = iota
+	// KindObfuscated 词中夹有噪音、分隔字符或换行，见SetSeparators、SetLineJoin
+	KindObfuscated
+	// KindReversed 倒写的词，见SetMatchReversed
+	KindReversed
+	// KindPhonetic 读音相同、拼写不同的英文单词，见SetPhonetic
+	KindPhonetic
+)
+
+// 各种命中方式的置信度，精确命中为1，读音命中见PhoneticConfidence
+const (
+	ObfuscatedConfidence = 0.9
+	ReversedConfidence   = 0.8
+)
+
+func (k MatchKind) String() string {
+	switch k {
+	case KindObfuscated:
+		return "obfuscated"
+	case KindReversed:
+		return "reversed"
+	case KindPhonetic:
+		return "phonetic"
+	}
+	return "exact"
+}
+
+// Confidence 该命中方式的置信度，取值(0,1]
+func (k MatchKind) Confidence() float64 {
+	switch k {
+	case KindObfuscated:
+		return ObfuscatedConfidence
+	case KindReversed:
+		return ReversedConfidence
+	case KindPhonetic:
+		return PhoneticConfidence
+	}
+	return 1
+}
+
+// ScoredMatch 带有命中方式和置信度的命中，Word为词典中的词
+type ScoredMatch struct {
+	Match
+	Kind       MatchKind
+	Confidence float64
+}
+
+// FindAllScored 找到所有匹配词及其命中方式和置信度
+func FindAllScored(text string) []ScoredMatch {
+	return Default().FindAllScored(text)
+}
+
+// FindAllScored 同FindAllWithIndex，另外标出每个命中的方式和置信度，
+// 设置了SetPhonetic时一并返回FindPhonetic的命中，按起点排序。
+// 调用方可以只自动处理置信度高的命中，其余交给人工审核
+func (filter *Filter) FindAllScored(text string) []ScoredMatch {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	input, err := filter.input(text)
+	if err != nil {
+		return nil
+	}
+	var (
+		matches = filter.findAllWithIndex(text)
+		runes   = []rune(input)
+		scored  = make([]ScoredMatch, 0, len(matches))
+	)
+	filter.count(text, len(matches) > 0)
+	setOffsets(text, matches)
+	for _, m := range matches {
+		kind := matchKind(string(runes[m.Start:m.End]), m.Word)
+		scored = append(scored, ScoredMatch{Match: m, Kind: kind, Confidence: kind.Confidence()})
+	}
+	if filter.phonetic != nil {
+		for _, m := range filter.findPhonetic(text) {
+			scored = append(scored, ScoredMatch{Match: m.Match, Kind: KindPhonetic, Confidence: m.Confidence})
+		}
+		sort.SliceStable(scored, func(i, j int) bool { return scored[i].Start < scored[j].Start })
+	}
+	if len(scored) == 0 {
+		return nil
+	}
+	return scored
+}
+
+// matchKind 比较原文中的一段与词典中的词，判断命中方式
+func matchKind(original, word string) MatchKind {
+	switch {
+	case original == word:
+		return KindExact
+	case isSubsequence(word, original, false):
+		return KindObfuscated
+	case isSubsequence(word, original, true):
+		return KindReversed
+	}
+	return KindObfuscated
+}
+
+// isSubsequence 判断word的各个字是否按顺序(reversed时按逆序)出现在s中
+func isSubsequence(word, s string, reversed bool) bool {
+	want := []rune(word)
+	if reversed {
+		for i, j := 0, len(want)-1; i < j; i, j = i+1, j-1 {
+			want[i], want[j] = want[j], want[i]
+		}
+	}
+	for _, r := range s {
+		if len(want) > 0 && r == want[0] {
+			want = want[1:]
+		}
+	}
+	return len(want) == 0
+}
@@ -0,0 +1,42 @@
+package sensitive
+
+import "testing"
+
+func TestFindAllScored(t *testing.T) {
+	filter := New()
+	filter.AddWord("敏感词", "法轮功", "fuck")
+	filter.SetSeparators(DefaultSeparators)
+	filter.SetMatchReversed(true)
+	filter.SetPhonetic(Metaphone)
+
+	got := filter.FindAllScored("敏感词，法-轮-功，词感敏，phuck")
+	expect := []struct {
+		word       string
+		kind       MatchKind
+		confidence float64
+	}{
+		{"敏感词", KindExact, 1},
+		{"法轮功", KindObfuscated, ObfuscatedConfidence},
+		{"敏感词", KindReversed, ReversedConfidence},
+		{"fuck", KindPhonetic, PhoneticConfidence},
+	}
+	if len(got) != len(expect) {
+		t.Fatalf("scored matches, got %+v", got)
+	}
+	for i, e := range expect {
+		if got[i].Word != e.word || got[i].Kind != e.kind || got[i].Confidence != e.confidence {
+			t.Errorf("match %d, got %s %s %v, expect %s %s %v",
+				i, got[i].Word, got[i].Kind, got[i].Confidence, e.word, e.kind, e.confidence)
+		}
+	}
+	if m := got[1]; m.Start != 4 || m.End != 9 || m.ByteStart != 12 {
+		t.Errorf("obfuscated span, got %+v", m.Match)
+	}
+
+	if got := filter.FindAllScored("干净"); got != nil {
+		t.Errorf("clean text, got %+v", got)
+	}
+	if KindPhonetic.String() != "phonetic" || KindExact.Confidence() != 1 {
+		t.Errorf("kind string or confidence")
+	}
+}
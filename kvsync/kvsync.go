@@ -0,0 +1,92 @@
+// Package kvsync 从etcd、Consul等键值存储同步词典。
+//
+// 本包不依赖具体的客户端，只定义了Source接口。词典以"前缀+词语"为键存放，
+// 以etcd(go.etcd.io/etcd/client/v3)为例：
+//
+//	func (s etcdSource) Snapshot(ctx context.Context) ([]string, uint64, error) {
+//		rsp, err := s.kv.Get(ctx, s.prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+//		if err != nil {
+//			return nil, 0, err
+//		}
+//		var words []string
+//		for _, kv := range rsp.Kvs {
+//			words = append(words, strings.TrimPrefix(string(kv.Key), s.prefix))
+//		}
+//		return words, uint64(rsp.Header.Revision), nil
+//	}
+//
+//	func (s etcdSource) Watch(ctx context.Context, revision uint64, fn func(sensitive.Delta) error) error {
+//		ch := s.watcher.Watch(ctx, s.prefix, clientv3.WithPrefix(), clientv3.WithRev(int64(revision)+1))
+//		for rsp := range ch {
+//			if err := rsp.Err(); err != nil {
+//				return err
+//			}
+//			delta := sensitive.Delta{Version: uint64(rsp.Header.Revision)}
+//			for _, ev := range rsp.Events {
+//				word := strings.TrimPrefix(string(ev.Kv.Key), s.prefix)
+//				if ev.Type == clientv3.EventTypeDelete {
+//					delta.Removed = append(delta.Removed, word)
+//				} else {
+//					delta.Added = append(delta.Added, word)
+//				}
+//			}
+//			if err := fn(delta); err != nil {
+//				return err
+//			}
+//		}
+//		return ctx.Err()
+//	}
+//
+// Consul的阻塞查询每次返回前缀下的全部键，Watch中以Reset为true的Delta推送即可。
+package kvsync
+
+import (
+	"context"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// Source 键值存储中的词典
+type Source interface {
+	// Snapshot 返回当前的全部词语及对应的存储修订号
+	Snapshot(ctx context.Context) (words []string, revision uint64, err error)
+	// Watch 从revision之后开始，将存储中的每次变更以一个Delta调用fn，
+	// 阻塞直到ctx结束、出错或fn返回错误
+	Watch(ctx context.Context, revision uint64, fn func(sensitive.Delta) error) error
+}
+
+// Sync 先用Source的快照替换filter的整个词典，再持续应用变更，每次变更都是
+// 原子的。快照或订阅出错(如修订号已被压缩)时等待retry后重新同步，直到ctx结束。
+// 同一存储上的所有实例因此会在数秒内收敛到相同的词典
+func Sync(ctx context.Context, filter *sensitive.Filter, source Source, retry time.Duration) error {
+	for {
+		if err := syncOnce(ctx, filter, source); ctx.Err() != nil {
+			return ctx.Err()
+		} else if err == nil {
+			// 订阅正常结束，立即重新同步
+			continue
+		}
+
+		timer := time.NewTimer(retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func syncOnce(ctx context.Context, filter *sensitive.Filter, source Source) error {
+	words, revision, err := source.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	filter.ResetWords(words...)
+
+	return source.Watch(ctx, revision, func(delta sensitive.Delta) error {
+		delta.Apply(filter)
+		return nil
+	})
+}
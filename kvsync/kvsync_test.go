@@ -0,0 +1,99 @@
+package kvsync
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// memSource 内存中的键值存储，只保留最近的变更，更早的修订号视为已压缩
+type memSource struct {
+	mu       sync.Mutex
+	words    map[string]bool
+	revision uint64
+	changes  chan sensitive.Delta
+}
+
+var errCompacted = errors.New("compacted")
+
+func (s *memSource) Snapshot(ctx context.Context) ([]string, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var words []string
+	for word := range s.words {
+		words = append(words, word)
+	}
+	return words, s.revision, nil
+}
+
+func (s *memSource) Watch(ctx context.Context, revision uint64, fn func(sensitive.Delta) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case delta := <-s.changes:
+			if delta.Version != revision+1 {
+				return errCompacted
+			}
+			revision = delta.Version
+			if err := fn(delta); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *memSource) put(added, removed []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, word := range added {
+		s.words[word] = true
+	}
+	for _, word := range removed {
+		delete(s.words, word)
+	}
+	s.revision++
+	s.changes <- sensitive.Delta{Version: s.revision, Added: added, Removed: removed}
+}
+
+func waitWords(t *testing.T, filter *sensitive.Filter, want []string) {
+	deadline := time.Now().Add(time.Second)
+	for !reflect.DeepEqual(filter.Words(), want) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := filter.Words(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestSync(t *testing.T) {
+	source := &memSource{words: map[string]bool{"色情": true}, revision: 5, changes: make(chan sensitive.Delta, 8)}
+	filter := sensitive.New()
+	filter.AddWord("旧词")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Sync(ctx, filter, source, 10*time.Millisecond) }()
+	waitWords(t, filter, []string{"色情"})
+
+	source.put([]string{"赌博"}, []string{"色情"})
+	waitWords(t, filter, []string{"赌博"})
+
+	// 跳过一个修订号，订阅出错后应重新取快照
+	source.mu.Lock()
+	source.words["毒品"] = true
+	source.revision++
+	source.mu.Unlock()
+	source.put([]string{"枪支"}, nil)
+	waitWords(t, filter, []string{"枪支", "毒品", "赌博"})
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("got %v", err)
+	}
+}
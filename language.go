@@ -0,0 +1,153 @@
+package sensitive
+
+import (
+	"sync"
+	"unicode"
+)
+
+// LanguageDetector 语言检测器，返回文本的语言标签，无法判断时返回空字符串
+type LanguageDetector interface {
+	Detect(text string) string
+}
+
+// LanguageDetectorFunc 将函数适配为LanguageDetector
+type LanguageDetectorFunc func(text string) string
+
+// Detect 调用函数本身
+func (fn LanguageDetectorFunc) Detect(text string) string {
+	return fn(text)
+}
+
+// scripts DetectScript识别的文字及对应的语言标签
+var scripts = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Han, "zh"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Thai, "th"},
+	{unicode.Latin, "en"},
+}
+
+// DetectScript 按文字系统粗略判断语言：取字符数最多的文字，
+// 含有假名的汉字文本判断为日文。适合作为没有专门检测器时的默认实现
+func DetectScript(text string) string {
+	counts := make(map[string]int)
+	for _, r := range text {
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+
+	lang, best := "", 0
+	for _, s := range scripts {
+		if n := counts[s.lang]; n > best {
+			lang, best = s.lang, n
+		}
+	}
+	return lang
+}
+
+// LanguageFilter 按语言划分词典的过滤器，先检测文本语言，
+// 再只用该语言的词典检查，而不是用全部词典检查所有文本
+type LanguageFilter struct {
+	mu       sync.RWMutex
+	filters  map[string]*Filter
+	fallback string
+	detector LanguageDetector
+}
+
+// NewLanguageFilter 返回一个按语言路由的过滤器，detector为nil时使用DetectScript
+func NewLanguageFilter(detector LanguageDetector) *LanguageFilter {
+	if detector == nil {
+		detector = LanguageDetectorFunc(DetectScript)
+	}
+	return &LanguageFilter{
+		filters:  make(map[string]*Filter),
+		detector: detector,
+	}
+}
+
+// Register 注册某个语言的过滤器
+func (lf *LanguageFilter) Register(lang string, filter *Filter) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	lf.filters[lang] = filter
+}
+
+// SetFallback 设置检测不出语言或该语言没有注册过滤器时使用的语言
+func (lf *LanguageFilter) SetFallback(lang string) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	lf.fallback = lang
+}
+
+// Filter 返回某个语言的过滤器，未注册时返回nil
+func (lf *LanguageFilter) Filter(lang string) *Filter {
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+	return lf.filters[lang]
+}
+
+// Route 返回检测到的语言及用于检查text的过滤器，没有可用的过滤器时返回nil
+func (lf *LanguageFilter) Route(text string) (string, *Filter) {
+	lang := lf.detector.Detect(text)
+
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+	if filter, ok := lf.filters[lang]; ok {
+		return lang, filter
+	}
+	return lf.fallback, lf.filters[lf.fallback]
+}
+
+// FindIn 用文本所属语言的词典检测敏感词
+func (lf *LanguageFilter) FindIn(text string) (bool, string) {
+	if _, filter := lf.Route(text); filter != nil {
+		return filter.FindIn(text)
+	}
+	return false, ""
+}
+
+// Validate 用文本所属语言的词典检测字符串是否合法
+func (lf *LanguageFilter) Validate(text string) (bool, string) {
+	if _, filter := lf.Route(text); filter != nil {
+		return filter.Validate(text)
+	}
+	return true, ""
+}
+
+// FindAll 用文本所属语言的词典找到所有匹配词
+func (lf *LanguageFilter) FindAll(text string) []string {
+	if _, filter := lf.Route(text); filter != nil {
+		return filter.FindAll(text)
+	}
+	return nil
+}
+
+// Replace 用文本所属语言的词典和谐敏感词
+func (lf *LanguageFilter) Replace(text string, repl rune) string {
+	if _, filter := lf.Route(text); filter != nil {
+		return filter.Replace(text, repl)
+	}
+	return text
+}
+
+// FilterWord 用文本所属语言的词典过滤敏感词
+func (lf *LanguageFilter) FilterWord(text string) string {
+	if _, filter := lf.Route(text); filter != nil {
+		return filter.FilterWord(text)
+	}
+	return text
+}
@@ -0,0 +1,45 @@
+package sensitive
+
+import "testing"
+
+func TestDetectScript(t *testing.T) {
+	testcases := []struct {
+		Text   string
+		Expect string
+	}{
+		{"这篇文章真的好", "zh"},
+		{"これは日本語です", "ja"},
+		{"안녕하세요", "ko"},
+		{"hello world", "en"},
+		{"привет", "ru"},
+		{"12345", ""},
+	}
+
+	for _, tc := range testcases {
+		if got := DetectScript(tc.Text); got != tc.Expect {
+			t.Errorf("detect %s, got %s, expect %s", tc.Text, got, tc.Expect)
+		}
+	}
+}
+
+func TestLanguageFilter(t *testing.T) {
+	zh := New()
+	zh.AddWord("垃圾")
+	en := New()
+	en.AddWord("trash")
+
+	lf := NewLanguageFilter(nil)
+	lf.Register("zh", zh)
+	lf.Register("en", en)
+	lf.SetFallback("zh")
+
+	if got := lf.Replace("this is trash", '*'); got != "this is *****" {
+		t.Errorf("replace english, got %s", got)
+	}
+	if got := lf.Replace("这篇文章是垃圾trash", '*'); got != "这篇文章是**trash" {
+		t.Errorf("replace chinese, got %s", got)
+	}
+	if lang, filter := lf.Route("12345"); lang != "zh" || filter != zh {
+		t.Errorf("route fallback, got %s %p", lang, filter)
+	}
+}
@@ -0,0 +1,120 @@
+package sensitive
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Layer 分层词典中的一层，如基础、地区、租户词典。Suppressed为要屏蔽的
+// 下层词语，只对更低的层生效
+type Layer struct {
+	Name       string
+	Words      []string
+	Suppressed []string
+}
+
+// ParseLayer 从rd读取一层词典：每行一个词，"-词"屏蔽下层的该词，
+// 空行和以#开头的注释行被跳过，行首的\用于转义，如"\-1"表示词"-1"
+func ParseLayer(name string, rd io.Reader) (Layer, error) {
+	layer := Layer{Name: name}
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "", text[0] == '#':
+			continue
+		case text[0] == '-':
+			if word := strings.TrimSpace(text[1:]); word != "" {
+				layer.Suppressed = append(layer.Suppressed, word)
+			}
+			continue
+		case text[0] == '\\':
+			text = text[1:]
+		}
+		layer.Words = append(layer.Words, text)
+	}
+	return layer, scanner.Err()
+}
+
+// LoadLayer 以文件路径为名称读取一层词典，格式见ParseLayer
+func LoadLayer(path string) (Layer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Layer{}, err
+	}
+	defer f.Close()
+	return ParseLayer(path, f)
+}
+
+// LayeredDict 由各层词典合并得到的生效词典
+type LayeredDict struct {
+	// origins 生效的词及加入它的层
+	origins map[string]string
+	// suppressed 被屏蔽的词及屏蔽它的层
+	suppressed map[string]string
+}
+
+// ResolveLayers 按从低到高的顺序合并各层：每层先屏蔽其Suppressed中已由下层
+// 加入的词，再加入自己的词。结果只取决于各层的内容和顺序
+func ResolveLayers(layers ...Layer) *LayeredDict {
+	d := &LayeredDict{
+		origins:    make(map[string]string),
+		suppressed: make(map[string]string),
+	}
+	for _, layer := range layers {
+		for _, word := range layer.Suppressed {
+			if _, ok := d.origins[word]; ok {
+				delete(d.origins, word)
+				d.suppressed[word] = layer.Name
+			}
+		}
+		for _, word := range layer.Words {
+			if _, ok := d.origins[word]; !ok {
+				d.origins[word] = layer.Name
+			}
+			delete(d.suppressed, word)
+		}
+	}
+	return d
+}
+
+// Words 按字典序返回生效的全部词
+func (d *LayeredDict) Words() []string {
+	return sortedWords(d.origins)
+}
+
+// SuppressedWords 按字典序返回被屏蔽的词
+func (d *LayeredDict) SuppressedWords() []string {
+	return sortedWords(d.suppressed)
+}
+
+// Explain 返回word在合并结果中的来源：生效时layer为最先加入它的层，
+// 被屏蔽时suppressedBy为屏蔽它的层，都为空表示没有任何层加入过它
+func (d *LayeredDict) Explain(word string) (layer, suppressedBy string) {
+	return d.origins[word], d.suppressed[word]
+}
+
+func sortedWords(m map[string]string) []string {
+	words := make([]string, 0, len(m))
+	for word := range m {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// LoadLayers 用分层词典替换默认过滤器的词典
+func LoadLayers(layers ...Layer) *LayeredDict {
+	return Default().LoadLayers(layers...)
+}
+
+// LoadLayers 合并各层并用结果一次性替换整个词典，见ResolveLayers，
+// 返回的LayeredDict用于查看生效的词及其来源
+func (filter *Filter) LoadLayers(layers ...Layer) *LayeredDict {
+	d := ResolveLayers(layers...)
+	filter.ResetWords(d.Words()...)
+	return d
+}
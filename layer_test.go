@@ -0,0 +1,49 @@
+package sensitive
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLayers(t *testing.T) {
+	base, err := ParseLayer("base", strings.NewReader("# 基础词典\n色情\n赌博\n枪支\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	regional, _ := ParseLayer("regional", strings.NewReader("-赌博\n彩票\n\\-1\n"))
+	tenant, _ := ParseLayer("tenant", strings.NewReader("赌博\n-枪支\n-不存在\n"))
+
+	if expect := (Layer{Name: "regional", Words: []string{"彩票", "-1"}, Suppressed: []string{"赌博"}}); !reflect.DeepEqual(regional, expect) {
+		t.Errorf("parse layer, got %+v, expect %+v", regional, expect)
+	}
+
+	filter := New()
+	filter.AddWord("旧词")
+	d := filter.LoadLayers(base, regional, tenant)
+
+	expect := []string{"-1", "彩票", "色情", "赌博"}
+	if words := d.Words(); !reflect.DeepEqual(words, expect) {
+		t.Errorf("effective words, got %v, expect %v", words, expect)
+	}
+	if words := filter.WordsWithPrefix("", 0); !reflect.DeepEqual(words, expect) {
+		t.Errorf("filter words, got %v, expect %v", words, expect)
+	}
+	if words := d.SuppressedWords(); !reflect.DeepEqual(words, []string{"枪支"}) {
+		t.Errorf("suppressed words, got %v", words)
+	}
+
+	cases := []struct {
+		word, layer, suppressedBy string
+	}{
+		{"色情", "base", ""},
+		{"赌博", "tenant", ""},
+		{"枪支", "", "tenant"},
+		{"不存在", "", ""},
+	}
+	for _, c := range cases {
+		if layer, by := d.Explain(c.word); layer != c.layer || by != c.suppressedBy {
+			t.Errorf("explain %s, got %q %q, expect %q %q", c.word, layer, by, c.layer, c.suppressedBy)
+		}
+	}
+}
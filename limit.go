@@ -0,0 +1,128 @@
+package sensitive
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrTextTooLong 查询的文本超过了SetMaxTextLen设置的上限
+	ErrTextTooLong = errors.New("sensitive: text too long")
+	// ErrInvalidUTF8 查询的文本不是合法的UTF-8，见UTF8Reject
+	ErrInvalidUTF8 = errors.New("sensitive: invalid utf-8 text")
+)
+
+// UTF8Policy 查询文本不是合法的UTF-8时的处理方式
+type UTF8Policy int
+
+const (
+	// UTF8BestEffort 不检查，每个非法字节按一个U+FFFD匹配。Replace等改写方法
+	// 的结果中，非法字节视匹配路径可能原样保留，也可能变为U+FFFD
+	UTF8BestEffort UTF8Policy = iota
+	// UTF8Sanitize 先将每个非法字节替换为U+FFFD再查询，改写方法的结果总是
+	// 合法的UTF-8。替换不改变rune下标，命中位置与原文一致
+	UTF8Sanitize
+	// UTF8Reject 拒绝查询，结果同超过长度上限时拒绝查询，错误为ErrInvalidUTF8
+	UTF8Reject
+)
+
+// SetUTF8Policy 设置默认过滤器处理非法UTF-8文本的方式
+func SetUTF8Policy(policy UTF8Policy) {
+	Default().SetUTF8Policy(policy)
+}
+
+// SetUTF8Policy 设置查询文本不是合法的UTF-8时的处理方式，对所有查询方法
+// 生效，默认为UTF8BestEffort
+func (filter *Filter) SetUTF8Policy(policy UTF8Policy) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.utf8Policy = policy
+}
+
+// SetMaxTextLen 设置默认过滤器查询文本的最大字节数
+func SetMaxTextLen(n int, truncate bool) {
+	Default().SetMaxTextLen(n, truncate)
+}
+
+// SetMaxTextLen 设置查询文本的最大字节数，避免超长输入消耗大量CPU，
+// n不大于0时不限制。超过上限时：truncate为true则在字符边界截断，只查询前n个
+// 字节，Replace等改写方法也只返回截断后的部分；truncate为false则拒绝查询，
+// ValidateN返回ValidationRejected和ErrTextTooLong，FilterWithDecision判定为拒绝，
+// Validate返回false，FindIn、FindAll等查找方法返回未命中，Replace、FilterWord等
+// 改写方法返回空字符串。是否被拒绝或截断可用CheckInput判断，
+// ValidationResult.Truncated也会标出截断
+func (filter *Filter) SetMaxTextLen(n int, truncate bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.maxTextLen = n
+	filter.truncateText = truncate
+}
+
+// CheckInput 检查默认过滤器是否会拒绝或截断text
+func CheckInput(text string) (truncated bool, err error) {
+	return Default().CheckInput(text)
+}
+
+// CheckInput 检查text是否会被SetMaxTextLen、SetUTF8Policy拒绝或截断，
+// 拒绝时返回ErrTextTooLong或ErrInvalidUTF8。FindIn、FindAll等方法对被拒绝的
+// 输入只返回未命中，需要区分时先调用CheckInput，被拒绝的输入不应视为合法
+func (filter *Filter) CheckInput(text string) (truncated bool, err error) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.checkInput(text)
+}
+
+// checkInput CheckInput的实现，不复制text，调用方需持有锁
+func (filter *Filter) checkInput(text string) (truncated bool, err error) {
+	if filter.maxTextLen > 0 && len(text) > filter.maxTextLen {
+		if !filter.truncateText {
+			return false, ErrTextTooLong
+		}
+		truncated = true
+	}
+	if filter.utf8Policy == UTF8Reject && !utf8.ValidString(text) {
+		return truncated, ErrInvalidUTF8
+	}
+	return truncated, nil
+}
+
+// input 按长度上限和UTF-8策略返回实际查询的文本，拒绝查询时返回错误，
+// 调用方需持有锁
+func (filter *Filter) input(text string) (string, error) {
+	if filter.maxTextLen > 0 && len(text) > filter.maxTextLen {
+		if !filter.truncateText {
+			return "", ErrTextTooLong
+		}
+		n := filter.maxTextLen
+		for n > 0 && !utf8.RuneStart(text[n]) {
+			n--
+		}
+		text = text[:n]
+	}
+	if filter.utf8Policy != UTF8BestEffort && !utf8.ValidString(text) {
+		if filter.utf8Policy == UTF8Reject {
+			return "", ErrInvalidUTF8
+		}
+		text = sanitizeUTF8(text)
+	}
+	return text, nil
+}
+
+// sanitizeUTF8 将text中的每个非法字节替换为U+FFFD
+func sanitizeUTF8(text string) string {
+	var b strings.Builder
+	b.Grow(len(text) + 8)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteRune(utf8.RuneError)
+		} else {
+			b.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
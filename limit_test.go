@@ -0,0 +1,94 @@
+package sensitive
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxTextLen(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+
+	// "好垃圾"为9个字节
+	filter.SetMaxTextLen(6, false)
+	if valid, _ := filter.Validate("好垃圾"); valid {
+		t.Errorf("validate too long text, got valid")
+	}
+	if found, _ := filter.FindIn("好垃圾"); found {
+		t.Errorf("find in too long text, got found")
+	}
+	if got := filter.Replace("好垃圾", '*'); got != "" {
+		t.Errorf("replace too long text, got %q", got)
+	}
+	if got := filter.ValidateN("好垃圾", 1); got.Status != ValidationRejected || !errors.Is(got.Err, ErrTextTooLong) {
+		t.Errorf("validate n too long text, got %+v", got)
+	}
+	if got := filter.ValidateN("垃圾", 1); got.Status != ValidationViolations {
+		t.Errorf("validate n text within limit, got %+v", got)
+	}
+	if _, err := filter.CheckInput("好垃圾"); !errors.Is(err, ErrTextTooLong) {
+		t.Errorf("check too long input, got %v", err)
+	}
+	if got, _, reject := filter.FilterWithDecision("好垃圾", '*'); got != "" || !reject {
+		t.Errorf("filter with decision too long text, got %q %v", got, reject)
+	}
+
+	// 截断在字符边界，7个字节只保留"好垃"
+	filter.SetMaxTextLen(7, true)
+	if got := filter.Replace("好垃圾", '*'); got != "好垃" {
+		t.Errorf("replace truncated text, got %q", got)
+	}
+	if got := filter.FindAll("垃圾好垃圾"); len(got) != 1 {
+		t.Errorf("find all truncated text, got %v", got)
+	}
+	if got := filter.FindAllWithIndex("垃圾好垃圾"); len(got) != 1 || got[0].End != 2 {
+		t.Errorf("find all with index truncated text, got %v", got)
+	}
+	if truncated, err := filter.CheckInput("好垃圾"); !truncated || err != nil {
+		t.Errorf("check truncated input, got %v %v", truncated, err)
+	}
+	if got := filter.ValidateN("好垃圾", 1); !got.Truncated || got.Status != ValidationClean {
+		t.Errorf("validate n truncated text, got %+v", got)
+	}
+
+	filter.SetMaxTextLen(0, false)
+	if got := filter.Replace("好垃圾", '*'); got != "好**" {
+		t.Errorf("replace without limit, got %q", got)
+	}
+}
+
+func TestUTF8Policy(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+
+	text := "垃\xff圾垃圾"
+	if found, word := filter.FindIn(text); !found || word != "垃圾" {
+		t.Errorf("best effort find in, got %v %q", found, word)
+	}
+	// 不含敏感词时原样返回
+	if got := filter.Replace("好\xff", '*'); got != "好\xff" {
+		t.Errorf("best effort replace, got %q", got)
+	}
+
+	filter.SetUTF8Policy(UTF8Sanitize)
+	if got := filter.Replace("好\xff", '*'); got != "好\ufffd" {
+		t.Errorf("sanitized replace, got %q", got)
+	}
+	if got := filter.Replace(text, '*'); got != "垃\ufffd圾**" {
+		t.Errorf("sanitized replace, got %q", got)
+	}
+	if got := filter.FindAllWithIndex(text); len(got) != 1 || got[0].Start != 3 || got[0].ByteStart != 7 {
+		t.Errorf("sanitized find all with index, got %v", got)
+	}
+
+	filter.SetUTF8Policy(UTF8Reject)
+	if got := filter.ValidateN(text, 1); got.Status != ValidationRejected || !errors.Is(got.Err, ErrInvalidUTF8) {
+		t.Errorf("rejected validate n, got %+v", got)
+	}
+	if found, _ := filter.FindIn(text); found {
+		t.Errorf("rejected find in, got found")
+	}
+	if got := filter.ValidateN("好垃圾", 1); got.Status != ValidationViolations {
+		t.Errorf("valid text under reject policy, got %+v", got)
+	}
+}
@@ -0,0 +1,31 @@
+package sensitive
+
+// DefaultLineJoin 默认的跨行分隔：换行及其两侧的空白，以及下一行开头的
+// 列表标记，如"-"、"•"、"2."、"3、"
+const DefaultLineJoin = `[ \t]*[\r\n]+\s*(?:(?:[-*+•·]|\d{1,3}[.)、])[ \t]*)?`
+
+// SetLineJoin 设置默认过滤器的跨行分隔
+func SetLineJoin(pattern string) error {
+	return Default().SetLineJoin(pattern)
+}
+
+// SetLineJoin 设置跨行匹配时忽略的分隔，一般为DefaultLineJoin。设置后匹配时
+// 先删除匹配pattern的分隔，"敏\n感\n词"、按列表逐项拆开的词也能命中，
+// 命中报告词典中的词，区间为原文中从词首到词尾的整段，Replace等方法
+// 处理整段，词两侧的分隔不受影响。与噪音不同，分隔只在这一步被忽略。
+// pattern为空时取消
+func (filter *Filter) SetLineJoin(pattern string) error {
+	var re regex
+	if pattern != "" {
+		var err error
+		if re, err = compileRegex(pattern); err != nil {
+			return err
+		}
+	}
+
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.lineJoin = re
+	return nil
+}
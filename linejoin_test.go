@@ -0,0 +1,40 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLineJoin(t *testing.T) {
+	filter := New()
+	filter.AddWord("敏感词")
+
+	text := "列表：\n1. 敏\n2. 感\n3. 词\n完"
+	if got := filter.FindAllWithIndex(text); got != nil {
+		t.Errorf("find all before line join, got %v", got)
+	}
+
+	if err := filter.SetLineJoin("("); err == nil {
+		t.Errorf("set invalid line join should fail")
+	}
+	if err := filter.SetLineJoin(DefaultLineJoin); err != nil {
+		t.Fatal(err)
+	}
+	expect := []Match{{Word: "敏感词", Start: 7, End: 18, ByteStart: 13, ByteEnd: 30, UTF16Start: 7, UTF16End: 18}}
+	if got := filter.FindAllWithIndex(text); !reflect.DeepEqual(got, expect) {
+		t.Errorf("find all with line join, got %v, expect %v", got, expect)
+	}
+	if got := filter.Replace("敏\r\n感\n- 词\n", '*'); got != "********\n" {
+		t.Errorf("replace with line join, got %q", got)
+	}
+	if got := filter.FilterWord("a 敏\n感 词"); got != "a 敏\n感 词" {
+		t.Errorf("filter with line join should not skip spaces, got %q", got)
+	}
+
+	if err := filter.SetLineJoin(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := filter.FindAllWithIndex(text); got != nil {
+		t.Errorf("find all after line join disabled, got %v", got)
+	}
+}
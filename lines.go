@@ -0,0 +1,63 @@
+package sensitive
+
+import (
+	"bytes"
+	"errors"
+)
+
+// DefaultMaxLineLength 加载纯文本词典时默认允许的最大行长(字节数)
+const DefaultMaxLineLength = 1 << 20
+
+// ErrLineTooLong 词典中有超过最大行长的行被跳过
+var ErrLineTooLong = errors.New("sensitive: dictionary line too long")
+
+// SetMaxLineLength 设置加载纯文本词典时允许的最大行长
+func SetMaxLineLength(n int) {
+	Default().SetMaxLineLength(n)
+}
+
+// SetMaxLineLength 设置加载纯文本词典时允许的最大行长(字节数)，n<=0时恢复默认值。
+// 更长的行被整行跳过，不会被截断后当作词加入
+func (filter *Filter) SetMaxLineLength(n int) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if n <= 0 {
+		n = DefaultMaxLineLength
+	}
+	filter.maxLineLength = n
+}
+
+// lineSplitter 按行切分的bufio.SplitFunc，跳过超过max字节的行并计数，
+// 去掉行尾的\r
+type lineSplitter struct {
+	max       int
+	skipping  bool
+	oversized int
+}
+
+func (s *lineSplitter) split(data []byte, atEOF bool) (int, []byte, error) {
+	i := bytes.IndexByte(data, '\n')
+	if s.skipping {
+		// 丢弃超长行的剩余部分
+		if i < 0 {
+			return len(data), nil, nil
+		}
+		s.skipping = false
+		return i + 1, nil, nil
+	}
+
+	switch {
+	case i > s.max:
+		s.oversized++
+		return i + 1, nil, nil
+	case i >= 0:
+		return i + 1, bytes.TrimSuffix(data[:i], []byte{'\r'}), nil
+	case len(data) > s.max:
+		s.oversized++
+		s.skipping = true
+		return len(data), nil, nil
+	case atEOF && len(data) > 0:
+		return len(data), bytes.TrimSuffix(data, []byte{'\r'}), nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,47 @@
+package sensitive
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadLines(t *testing.T) {
+	filter := New()
+	long := strings.Repeat("长", 3000)
+	if err := filter.LoadBytes([]byte("垃圾\r\n  东西 \r\n" + long + "\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"垃圾", "东西", long} {
+		if !filter.trie.Has(word) {
+			t.Errorf("missing %.12s", word)
+		}
+	}
+	if filter.trie.Has("垃圾\r") {
+		t.Errorf("carriage return should be trimmed")
+	}
+}
+
+func TestLoadOversizedLines(t *testing.T) {
+	content := "垃圾\n" + strings.Repeat("x", 100) + "\n东西\n" + strings.Repeat("y", 100)
+
+	filter := New()
+	filter.SetMaxLineLength(50)
+	err := filter.LoadBytes([]byte(content))
+	if !errors.Is(err, ErrLineTooLong) {
+		t.Errorf("got %v", err)
+	}
+	if !filter.trie.Has("垃圾") || !filter.trie.Has("东西") || filter.trie.Has(strings.Repeat("x", 50)) {
+		t.Errorf("oversized lines should be skipped as a whole")
+	}
+
+	filter = New()
+	filter.SetMaxLineLength(50)
+	report, err := filter.LoadWithReport(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Lines != 4 || report.Added != 2 || report.Oversized != 2 {
+		t.Errorf("got %+v", *report)
+	}
+}
@@ -0,0 +1,65 @@
+package sensitive
+
+import "sync"
+
+// linkPattern 匹配URL和邮箱地址
+const linkPattern = `(?i)(?:\b(?:https?|ftp)://|\bwww\.)[^\s<>"'\p{Han}]+|[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`
+
+var (
+	linkOnce  sync.Once
+	linkRegex regex
+)
+
+// links 第一次使用时编译linkPattern，不支持正则的构建中返回nil
+func links() regex {
+	linkOnce.Do(func() {
+		linkRegex, _ = compileRegex(linkPattern)
+	})
+	return linkRegex
+}
+
+// FindLinks 找出文本中的URL和邮箱地址
+func FindLinks(text string) []Match {
+	matches := runeMatches(text, links().FindAllStringIndex(text, -1))
+	setOffsets(text, matches)
+	return matches
+}
+
+// SetSkipLinks 设置是否跳过URL和邮箱地址中的命中
+func SetSkipLinks(skip bool) {
+	Default().SetSkipLinks(skip)
+}
+
+// SetSkipLinks 设置是否跳过URL和邮箱地址中的命中。商品链接和邮箱中
+// 常有偶然构成敏感词的片段，开启后与URL或邮箱有重叠的命中都会被忽略。
+// 需要单独标记链接本身时可使用FindLinks
+func (filter *Filter) SetSkipLinks(skip bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.skipLinks = skip
+}
+
+// dropLinked 去掉与链接重叠的命中，links和matches均按起点升序排列
+func dropLinked(matches, links []Match) []Match {
+	if len(links) == 0 {
+		return matches
+	}
+	kept := matches[:0]
+	for _, m := range matches {
+		linked := false
+		for _, link := range links {
+			if m.Start < link.End && link.Start < m.End {
+				linked = true
+				break
+			}
+			if link.Start >= m.End {
+				break
+			}
+		}
+		if !linked {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
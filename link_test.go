@@ -0,0 +1,30 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindLinks(t *testing.T) {
+	expect := []Match{
+		{Word: "https://example.com/sex", Start: 3, End: 26, ByteStart: 9, ByteEnd: 32, UTF16Start: 3, UTF16End: 26},
+		{Word: "admin@sexshop.com", Start: 31, End: 48, ByteStart: 45, ByteEnd: 62, UTF16Start: 31, UTF16End: 48},
+	}
+	if got := FindLinks("打开：https://example.com/sex 或发邮件admin@sexshop.com"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("find links, got %v, expect %v", got, expect)
+	}
+}
+
+func TestSkipLinks(t *testing.T) {
+	filter := New()
+	filter.AddWord("sex", "色情")
+	filter.SetSkipLinks(true)
+
+	text := "色情网站https://example.com/sex 联系sex@example.com"
+	if got, expect := filter.Replace(text, '*'), "**网站https://example.com/sex 联系sex@example.com"; got != expect {
+		t.Errorf("replace, got %s, expect %s", got, expect)
+	}
+	if got := filter.FindAll("see www.sexy.com"); got != nil {
+		t.Errorf("findall inside link, got %v", got)
+	}
+}
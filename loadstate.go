@@ -0,0 +1,144 @@
+package sensitive
+
+import (
+	"context"
+	"time"
+)
+
+// LoadStatus 词典加载的进度
+type LoadStatus int
+
+const (
+	// LoadIdle 还没有加载过词典
+	LoadIdle LoadStatus = iota
+	// LoadLoading 正在加载
+	LoadLoading
+	// LoadReady 最近一次加载成功
+	LoadReady
+	// LoadFailed 最近一次加载失败
+	LoadFailed
+)
+
+func (s LoadStatus) String() string {
+	switch s {
+	case LoadLoading:
+		return "loading"
+	case LoadReady:
+		return "ready"
+	case LoadFailed:
+		return "failed"
+	}
+	return "idle"
+}
+
+// LoadState 最近一次加载词典的状态
+type LoadState struct {
+	Status LoadStatus
+	// Source 词典来源，文件路径或URL，从io.Reader加载时为空
+	Source string
+	// Err 加载失败的原因
+	Err error
+	// Time 进入当前状态的时间
+	Time time.Time
+	// LastSuccess 最近一次加载成功的时间
+	LastSuccess time.Time
+}
+
+// Ready 默认过滤器是否已成功加载过词典
+func Ready() bool {
+	return Default().Ready()
+}
+
+// Ready 是否已成功加载过词典。之后的加载失败时仍然使用已有的词典，
+// 因此仍返回true，可用于服务的就绪探针
+func (filter *Filter) Ready() bool {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return !filter.loadState.LastSuccess.IsZero()
+}
+
+// GetLoadState 返回默认过滤器的加载状态
+func GetLoadState() LoadState {
+	return Default().LoadState()
+}
+
+// LoadState 返回最近一次加载词典的状态
+func (filter *Filter) LoadState() LoadState {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.loadState
+}
+
+// WaitReady 等待默认过滤器就绪
+func WaitReady(ctx context.Context) error {
+	return Default().WaitReady(ctx)
+}
+
+// WaitReady 等待词典首次加载成功，ctx结束时返回ctx.Err()
+func (filter *Filter) WaitReady(ctx context.Context) error {
+	filter.mu.RLock()
+	ready := filter.ready
+	filter.mu.RUnlock()
+	if ready == nil {
+		// 零值的filter(如由gob解码生成)没有经过New
+		filter.mu.Lock()
+		ready = filter.readyChan()
+		filter.mu.Unlock()
+	}
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// readyChan 返回首次加载成功时关闭的channel，由New分配，零值的filter
+// 在此补上，调用方需持有写锁
+func (filter *Filter) readyChan() chan struct{} {
+	if filter.ready == nil {
+		filter.ready = make(chan struct{})
+	}
+	return filter.ready
+}
+
+// beginLoad 标记开始从source加载，调用方不能持有锁
+func (filter *Filter) beginLoad(source string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.loadState.Status = LoadLoading
+	filter.loadState.Source = source
+	filter.loadState.Err = nil
+	filter.loadState.Time = time.Now()
+}
+
+// recordLoad 记录从source加载的结果并原样返回err，调用方不能持有锁
+func (filter *Filter) recordLoad(source string, err error) error {
+	filter.mu.Lock()
+	now := time.Now()
+	var elapsed time.Duration
+	if filter.loadState.Status == LoadLoading {
+		elapsed = now.Sub(filter.loadState.Time)
+	}
+	filter.loadState.Source = source
+	filter.loadState.Err = err
+	filter.loadState.Time = now
+	if err != nil {
+		filter.loadState.Status = LoadFailed
+		filter.loadErrors++
+	} else {
+		filter.loadState.Status = LoadReady
+		if filter.loadState.LastSuccess.IsZero() {
+			close(filter.readyChan())
+		}
+		filter.loadState.LastSuccess = now
+	}
+	state, version, events := filter.loadState, filter.version, filter.events
+	filter.mu.Unlock()
+
+	if events != nil {
+		events.loadFinished(state, version, elapsed)
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadState(t *testing.T) {
+	filter := New()
+	if filter.Ready() || filter.LoadState().Status != LoadIdle {
+		t.Fatalf("new filter, got %+v", filter.LoadState())
+	}
+
+	path := filepath.Join(t.TempDir(), "dict.txt")
+	if err := filter.LoadWordDict(path); err == nil {
+		t.Fatal("expect error for missing file")
+	}
+	state := filter.LoadState()
+	if filter.Ready() || state.Status != LoadFailed || state.Source != path || !errors.Is(state.Err, os.ErrNotExist) {
+		t.Errorf("failed load, got %+v", state)
+	}
+
+	os.WriteFile(path, []byte("色情\n"), 0o644)
+	if err := filter.LoadWordDict(path); err != nil {
+		t.Fatal(err)
+	}
+	state = filter.LoadState()
+	if !filter.Ready() || state.Status != LoadReady || state.Err != nil || state.LastSuccess.IsZero() {
+		t.Errorf("successful load, got %+v", state)
+	}
+
+	// 之后的失败不影响就绪状态
+	filter.LoadChecksum(strings.NewReader("东西"), "00")
+	if state = filter.LoadState(); !filter.Ready() || state.Status != LoadFailed || state.Status.String() != "failed" {
+		t.Errorf("failed reload, got %+v", state)
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	filter := New()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := filter.WaitReady(ctx); err != context.DeadlineExceeded {
+		t.Errorf("got %v, expect deadline exceeded", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- filter.WaitReady(context.Background()) }()
+	filter.Load(strings.NewReader("色情"))
+	filter.Load(strings.NewReader("赌博"))
+	if err := <-done; err != nil {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package sensitive
+
+import "unicode/utf8"
+
+// HasWord 判断word是否在词典中
+func HasWord(word string) bool {
+	return Default().HasWord(word)
+}
+
+// HasWord 判断word是否为词典中的一个完整的词
+func (filter *Filter) HasWord(word string) bool {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.matcher().Has(word)
+}
+
+// WordsWithPrefix 返回以prefix开头的词
+func WordsWithPrefix(prefix string, limit int) []string {
+	return Default().WordsWithPrefix(prefix, limit)
+}
+
+// WordsWithPrefix 按字典序返回词典中以prefix开头的词(包括prefix本身)，
+// 最多limit个，limit<=0表示不限制。用于管理后台的自动补全
+func (filter *Filter) WordsWithPrefix(prefix string, limit int) []string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	var words []string
+	filter.matcher().WalkPrefix(prefix, func(word string) bool {
+		words = append(words, word)
+		return limit <= 0 || len(words) < limit
+	})
+	return words
+}
+
+// LongestWord 返回默认过滤器中最长的词的长度
+func LongestWord() int {
+	return Default().LongestWord()
+}
+
+// LongestWord 返回词典中最长的词的长度(rune数)，需要遍历词典。
+// 分段处理长文本时，段与段之间至少保留LongestWord()-1个字符才不会漏掉跨段的词，
+// 设置了分隔字符等时见CarryLen
+func (filter *Filter) LongestWord() int {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	longest := 0
+	filter.matcher().Walk(func(word string) bool {
+		if n := utf8.RuneCountInString(word); n > longest {
+			longest = n
+		}
+		return true
+	})
+	return longest
+}
+
+// CarryLen 返回默认过滤器分段处理时text末尾需要保留的rune数
+func CarryLen(text string, keep int) int {
+	return Default().CarryLen(text, keep)
+}
+
+// CarryLen 分段处理长文本时，返回text末尾需要留到下一段一起匹配的rune数，
+// 即包含最后keep个参与匹配的字符的最短后缀，keep一般为LongestWord()-1。
+// 设置了SetSeparators、SetLineJoin或开启SetMatchThroughNoise时，夹在词中的
+// 分隔和噪音不计入keep，一个词在原文中可以跨过比词长更多的字符
+func (filter *Filter) CarryLen(text string, keep int) int {
+	if keep <= 0 {
+		return 0
+	}
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	normalizers := filter.joinNormalizers()
+	if filter.throughNoise {
+		normalizers = append(filter.noiseNormalizers(), normalizers...)
+	}
+	n := utf8.RuneCountInString(text)
+	if len(normalizers) == 0 {
+		if keep > n {
+			return n
+		}
+		return keep
+	}
+	runes, index := normalize(text, normalizers)
+	if len(runes) <= keep {
+		return n
+	}
+	return n - index[len(runes)-keep]
+}
@@ -0,0 +1,63 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordsWithPrefix(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情", "色情网站", "色狼", "黄色", "色")
+
+	for _, compiled := range []bool{false, true} {
+		if compiled {
+			filter.Compile()
+		}
+		if !filter.HasWord("色情") || filter.HasWord("色情网") || filter.HasWord("") {
+			t.Errorf("compiled %v, has word mismatch", compiled)
+		}
+		if got := filter.WordsWithPrefix("色情", 0); !reflect.DeepEqual(got, []string{"色情", "色情网站"}) {
+			t.Errorf("compiled %v, got %v", compiled, got)
+		}
+		if got := filter.WordsWithPrefix("色", 2); !reflect.DeepEqual(got, []string{"色", "色情"}) {
+			t.Errorf("compiled %v, limit, got %v", compiled, got)
+		}
+		if got := filter.WordsWithPrefix("红", 0); got != nil {
+			t.Errorf("compiled %v, missing prefix, got %v", compiled, got)
+		}
+		if got := filter.WordsWithPrefix("", 0); len(got) != 5 {
+			t.Errorf("compiled %v, empty prefix, got %v", compiled, got)
+		}
+	}
+}
+
+func TestLongestWord(t *testing.T) {
+	filter := New()
+	if got := filter.LongestWord(); got != 0 {
+		t.Errorf("empty, got %d", got)
+	}
+	filter.AddWord("色情", "色情网站", "ab")
+	if got := filter.LongestWord(); got != 4 {
+		t.Errorf("got %d", got)
+	}
+}
+
+func TestCarryLen(t *testing.T) {
+	filter := New()
+	if got := filter.CarryLen("这是垃--", 3); got != 3 {
+		t.Errorf("carry len, got %d", got)
+	}
+	if got := filter.CarryLen("垃", 3); got != 1 {
+		t.Errorf("carry len of short text, got %d", got)
+	}
+
+	// 分隔字符不计入keep
+	filter.SetSeparators("-")
+	if got := filter.CarryLen("这是垃--", 1); got != 3 {
+		t.Errorf("carry len with separators, got %d", got)
+	}
+	filter.SetMatchThroughNoise(true)
+	if got := filter.CarryLen("这是垃 -", 2); got != 4 {
+		t.Errorf("carry len through noise, got %d", got)
+	}
+}
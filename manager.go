@@ -0,0 +1,227 @@
+package sensitive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Manager 管理一个过滤器的多个词典来源，按各自的间隔刷新，失败时按重试策略
+// 重试。任一来源的内容变化后，用全部来源最近一次成功获取的内容重新构建词典，
+// 一次性替换过滤器的词典，查询不会看到只加载了一部分的词典
+type Manager struct {
+	filter *Filter
+
+	mu       sync.Mutex
+	sources  []*managedSource
+	retry    RetryPolicy
+	onError  func(name string, err error)
+	cancel   context.CancelFunc
+	routines sync.WaitGroup
+}
+
+// managedSource 一个来源及其最近一次成功获取的内容
+type managedSource struct {
+	name     string
+	source   Source
+	interval time.Duration
+	content  []byte
+	version  string
+	fetched  bool
+}
+
+// NewManager 返回管理filter词典来源的Manager
+func NewManager(filter *Filter) *Manager {
+	return &Manager{filter: filter}
+}
+
+// Add 添加名为name的来源，interval为Start之后的刷新间隔，为0时只在Refresh时获取。
+// 构建词典时按添加顺序加载各个来源
+func (m *Manager) Add(name string, source Source, interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sources = append(m.sources, &managedSource{name: name, source: source, interval: interval})
+}
+
+// SetRetryPolicy 设置获取来源失败时的重试策略，默认不重试
+func (m *Manager) SetRetryPolicy(policy RetryPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retry = policy
+}
+
+// OnError 设置后台刷新失败时的回调，重试用尽后调用一次
+func (m *Manager) OnError(fn func(name string, err error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onError = fn
+}
+
+// Refresh 立即获取全部来源，有来源的内容变化时重新构建词典。
+// 获取失败的来源继续使用上次成功获取的内容；还有来源从未成功获取时
+// 不修改词典。返回全部来源的错误
+func (m *Manager) Refresh(ctx context.Context) error {
+	m.mu.Lock()
+	sources := append([]*managedSource(nil), m.sources...)
+	m.mu.Unlock()
+
+	var (
+		errs    []error
+		changed bool
+	)
+	for _, s := range sources {
+		ok, err := m.refresh(ctx, s)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		changed = changed || ok
+	}
+	if changed {
+		if err := m.rebuild(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Start 在后台按各来源的间隔刷新，直到ctx结束或调用Stop。
+// 首次加载应先调用Refresh以便处理错误
+func (m *Manager) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		return
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	for _, s := range m.sources {
+		if s.interval <= 0 {
+			continue
+		}
+		m.routines.Add(1)
+		go m.run(ctx, s)
+	}
+}
+
+// Stop 停止后台刷新并等待进行中的刷新结束
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	cancel := m.cancel
+	m.cancel = nil
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		m.routines.Wait()
+	}
+}
+
+func (m *Manager) run(ctx context.Context, s *managedSource) {
+	defer m.routines.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := m.refresh(ctx, s)
+		if err == nil && changed {
+			err = m.rebuild()
+		}
+		if err != nil && ctx.Err() == nil {
+			m.mu.Lock()
+			onError := m.onError
+			m.mu.Unlock()
+			if onError != nil {
+				onError(s.name, err)
+			}
+		}
+	}
+}
+
+// refresh 按重试策略获取s，返回内容是否变化
+func (m *Manager) refresh(ctx context.Context, s *managedSource) (bool, error) {
+	m.mu.Lock()
+	policy := m.retry
+	m.mu.Unlock()
+
+	var (
+		content []byte
+		version string
+		err     error
+		backoff = policy.InitialBackoff
+	)
+	for attempt := 0; ; attempt++ {
+		content, version, err = fetchSource(ctx, s.source)
+		if err == nil || attempt >= policy.MaxRetries || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-time.After(policy.jitter(backoff)):
+		case <-ctx.Done():
+		}
+		backoff = policy.next(backoff)
+	}
+	if err != nil {
+		m.filter.beginLoad(s.name)
+		return false, m.filter.recordLoad(s.name, fmt.Errorf("sensitive: source %s: %w", s.name, err))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// 源不提供版本时比较内容，内容不变就不必重建词典
+	if s.fetched && version == s.version && (version != "" || bytes.Equal(content, s.content)) {
+		return false, nil
+	}
+	s.content, s.version, s.fetched = content, version, true
+	return true, nil
+}
+
+func fetchSource(ctx context.Context, source Source) ([]byte, string, error) {
+	rc, version, err := source.Fetch(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, version, nil
+}
+
+// rebuild 用全部来源的内容构建新词典并替换，有来源从未成功获取时不做修改
+func (m *Manager) rebuild() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.sources))
+	for _, s := range m.sources {
+		if !s.fetched {
+			return nil
+		}
+		names = append(names, s.name)
+	}
+	name := strings.Join(names, ",")
+
+	filter := m.filter
+	filter.mu.RLock()
+	tmp := New()
+	tmp.maxLineLength = filter.maxLineLength
+	filter.mu.RUnlock()
+
+	filter.beginLoad(name)
+	for _, s := range m.sources {
+		if err := tmp.Load(bytes.NewReader(s.content)); err != nil {
+			return filter.recordLoad(name, fmt.Errorf("sensitive: source %s: %w", s.name, err))
+		}
+	}
+	filter.swap(tmp)
+	return filter.recordLoad(name, nil)
+}
@@ -0,0 +1,132 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memSource 内存中的来源，fails次失败后返回words
+type memSource struct {
+	mu      sync.Mutex
+	words   string
+	version string
+	fails   int
+	calls   int
+}
+
+func (s *memSource) set(words, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.words, s.version = words, version
+}
+
+func (s *memSource) Fetch(ctx context.Context) (io.ReadCloser, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.fails > 0 {
+		s.fails--
+		return nil, "", errors.New("unavailable")
+	}
+	return io.NopCloser(strings.NewReader(s.words)), s.version, nil
+}
+
+func TestManagerRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dict.txt")
+	if err := os.WriteFile(path, []byte("垃圾\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db := &memSource{words: "加微信|ad\n", version: "1", fails: 1}
+
+	filter := New()
+	m := NewManager(filter)
+	m.Add("file", FileSource{Path: path}, 0)
+	m.Add("db", db, 0)
+
+	// 有来源从未成功获取时不修改词典
+	if err := m.Refresh(context.Background()); err == nil {
+		t.Fatal("expect error from db source")
+	}
+	if filter.HasWord("垃圾") || filter.Ready() {
+		t.Errorf("dictionary should not be built without every source")
+	}
+
+	m.SetRetryPolicy(RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})
+	db.fails = 1
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !filter.HasWord("垃圾") || filter.Category("加微信") != "ad" {
+		t.Errorf("dictionary not built, words %v", filter.Words())
+	}
+
+	// 版本不变时不重新构建
+	version := filter.Version()
+	if err := m.Refresh(context.Background()); err != nil || filter.Version() != version {
+		t.Errorf("unchanged sources should not rebuild, err %v", err)
+	}
+
+	// 获取失败时继续使用上次的内容
+	db.set("加QQ\n", "2")
+	db.fails = 5
+	if err := m.Refresh(context.Background()); err == nil {
+		t.Errorf("expect error after retries")
+	}
+	if !filter.HasWord("加微信") || filter.HasWord("加QQ") {
+		t.Errorf("failed source should keep its last content")
+	}
+
+	db.fails = 0
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if filter.HasWord("加微信") || !filter.HasWord("加QQ") || !filter.HasWord("垃圾") {
+		t.Errorf("dictionary not replaced, words %v", filter.Words())
+	}
+
+	// 没有版本时比较内容
+	db.set("加QQ\n", "")
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	version = filter.Version()
+	if err := m.Refresh(context.Background()); err != nil || filter.Version() != version {
+		t.Errorf("unversioned source with same content should not rebuild, err %v", err)
+	}
+	db.set("加微信\n", "")
+	if err := m.Refresh(context.Background()); err != nil || !filter.HasWord("加微信") {
+		t.Errorf("unversioned source with new content should rebuild, err %v", err)
+	}
+}
+
+func TestManagerStart(t *testing.T) {
+	db := &memSource{words: "垃圾\n", version: "1"}
+	filter := New()
+	m := NewManager(filter)
+	m.Add("db", db, 5*time.Millisecond)
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Start(context.Background())
+	defer m.Stop()
+	db.set("加微信\n", "2")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !filter.HasWord("加微信") {
+		if time.Now().After(deadline) {
+			t.Fatal("source not refreshed in background")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if filter.HasWord("垃圾") {
+		t.Errorf("old words should be swapped out")
+	}
+}
@@ -0,0 +1,93 @@
+package sensitive
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Manifest 列出多个词典文件的清单
+type Manifest struct {
+	Dictionaries []ManifestEntry `json:"dictionaries"`
+}
+
+// ManifestEntry 清单中的一个词典文件
+type ManifestEntry struct {
+	// URL 词典地址，相对地址相对于清单地址解析
+	URL string `json:"url"`
+	// Category 词典中的词所属的分类，为空表示不分类
+	Category string `json:"category"`
+	// SHA256 词典内容的十六进制SHA-256校验和，为空时不校验
+	SHA256 string `json:"sha256"`
+}
+
+// LoadNetManifest 按清单加载多个网络词典
+func LoadNetManifest(url string) error {
+	return Default().LoadNetManifest(url)
+}
+
+// LoadNetManifest 下载url处的JSON清单，并发下载其中列出的全部词典并校验，
+// 全部成功后再依次加载到各自的分类中；任一词典下载或校验失败时不加载任何词典
+func (filter *Filter) LoadNetManifest(url string) error {
+	manifest, err := filter.fetchManifest(url)
+	if err != nil {
+		return err
+	}
+
+	var (
+		contents = make([][]byte, len(manifest.Dictionaries))
+		errs     = make([]error, len(manifest.Dictionaries))
+		wg       sync.WaitGroup
+	)
+	for i, entry := range manifest.Dictionaries {
+		wg.Add(1)
+		go func(i int, entry ManifestEntry) {
+			defer wg.Done()
+			contents[i], errs[i] = filter.fetchEntry(url, entry)
+		}(i, entry)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for i, entry := range manifest.Dictionaries {
+		if err := filter.loadCategory(entry.Category, bytes.NewReader(contents[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (filter *Filter) fetchManifest(url string) (*Manifest, error) {
+	body, err := filter.fetch(url, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	manifest := &Manifest{}
+	if err := json.NewDecoder(body).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("sensitive: parse manifest %s: %w", url, err)
+	}
+	return manifest, nil
+}
+
+// fetchEntry 下载清单中的一个词典并校验
+func (filter *Filter) fetchEntry(base string, entry ManifestEntry) ([]byte, error) {
+	ref, err := url.Parse(entry.URL)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	location := baseURL.ResolveReference(ref).String()
+
+	return filter.fetchVerified(location, 5*time.Second, entry.SHA256)
+}
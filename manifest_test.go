@@ -0,0 +1,59 @@
+package sensitive
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func manifestServer(t *testing.T, sum string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dicts/ad.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# 广告\r\n加微信\r\n")
+	})
+	mux.HandleFunc("/dicts/base.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "垃圾\n")
+	})
+	mux.HandleFunc("/dicts/manifest.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"dictionaries": [
+			{"url": "base.txt"},
+			{"url": "/dicts/ad.txt", "category": "ad", "sha256": %q}
+		]}`, sum)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestLoadNetManifest(t *testing.T) {
+	srv := manifestServer(t, sha256Hex("# 广告\r\n加微信\r\n"))
+	defer srv.Close()
+
+	filter := New()
+	if err := filter.LoadNetManifest(srv.URL + "/dicts/manifest.json"); err != nil {
+		t.Fatal(err)
+	}
+	if got := filter.Replace("垃圾，加微信", '*'); got != "**，***" {
+		t.Errorf("replace, got %s", got)
+	}
+	if filter.Category("加微信") != "ad" {
+		t.Errorf("category not loaded")
+	}
+	if filter.Category("# 广告") != "" || filter.Category("加微信\r") != "" {
+		t.Errorf("comment or line ending loaded as a word")
+	}
+}
+
+func TestLoadNetManifestChecksumMismatch(t *testing.T) {
+	srv := manifestServer(t, "00")
+	defer srv.Close()
+
+	filter := New()
+	if err := filter.LoadNetManifest(srv.URL + "/dicts/manifest.json"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("got %v", err)
+	}
+	if filter.trie.Has("垃圾") {
+		t.Errorf("nothing should be loaded when any dictionary fails")
+	}
+}
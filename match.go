@@ -0,0 +1,80 @@
+package sensitive
+
+import "sort"
+
+// Match 一次命中的结果，区间均为左闭右开。Start和End为命中词在原文中的rune下标；
+// ByteStart和ByteEnd为字节下标，可直接用于text[ByteStart:ByteEnd]；
+// UTF16Start和UTF16End为UTF-16码元下标，与JavaScript、Java等语言中字符串的
+// 下标一致。三组下标由返回Match的查询方法在一次遍历中同时算出
+type Match struct {
+	Word  string
+	Start int
+	End   int
+
+	ByteStart  int
+	ByteEnd    int
+	UTF16Start int
+	UTF16End   int
+}
+
+// setOffsets 按rune下标补全matches在text中的字节和UTF-16下标，只遍历text一次
+func setOffsets(text string, matches []Match) {
+	if len(matches) == 0 {
+		return
+	}
+	ptrs := make([]*Match, len(matches))
+	for i := range matches {
+		ptrs[i] = &matches[i]
+	}
+	fillOffsets(text, ptrs)
+}
+
+// fillOffsets setOffsets的实现，matches可以分散在不同的结构中
+func fillOffsets(text string, matches []*Match) {
+	positions := make([]int, 0, 2*len(matches))
+	for _, m := range matches {
+		positions = append(positions, m.Start, m.End)
+	}
+	sort.Ints(positions)
+	n := 0
+	for i, p := range positions {
+		if i == 0 || p != positions[n-1] {
+			positions[n] = p
+			n++
+		}
+	}
+	positions = positions[:n]
+
+	var (
+		bytes = make([]int, n)
+		units = make([]int, n)
+		next  = 0
+		pos   = 0
+		unit  = 0
+	)
+	for i, r := range text {
+		for next < n && positions[next] == pos {
+			bytes[next], units[next] = i, unit
+			next++
+		}
+		if next == n {
+			break
+		}
+		pos++
+		if r >= 0x10000 {
+			unit += 2
+		} else {
+			unit++
+		}
+	}
+	for ; next < n; next++ {
+		bytes[next], units[next] = len(text), unit
+	}
+
+	for _, m := range matches {
+		i := sort.SearchInts(positions, m.Start)
+		j := sort.SearchInts(positions, m.End)
+		m.ByteStart, m.UTF16Start = bytes[i], units[i]
+		m.ByteEnd, m.UTF16End = bytes[j], units[j]
+	}
+}
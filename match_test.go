@@ -0,0 +1,47 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindAllWithIndex(t *testing.T) {
+	filter := New()
+	filter.AddWord("ABC", "BCD", "AB")
+
+	expect := []Match{{Word: "ABC", Start: 0, End: 3, ByteStart: 0, ByteEnd: 3, UTF16Start: 0, UTF16End: 3}}
+	if got := filter.FindAllWithIndex("ABCD"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("findallwithindex, got %v, expect %v", got, expect)
+	}
+
+	filter.SetOverlap(true)
+	expect = []Match{{Word: "AB", Start: 0, End: 2, ByteStart: 0, ByteEnd: 2, UTF16Start: 0, UTF16End: 2}, {Word: "ABC", Start: 0, End: 3, ByteStart: 0, ByteEnd: 3, UTF16Start: 0, UTF16End: 3}, {Word: "BCD", Start: 1, End: 4, ByteStart: 1, ByteEnd: 4, UTF16Start: 1, UTF16End: 4}}
+	if got := filter.FindAllWithIndex("ABCD"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("overlap findallwithindex, got %v, expect %v", got, expect)
+	}
+}
+
+func TestMatchOffsets(t *testing.T) {
+	filter := New()
+	filter.AddWord("敏感", "ab")
+
+	// 😀在UTF-16中占两个码元
+	text := "😀敏感x😀ab"
+	got := filter.FindAllWithIndex(text)
+	expect := []Match{
+		{Word: "敏感", Start: 1, End: 3, ByteStart: 4, ByteEnd: 10, UTF16Start: 2, UTF16End: 4},
+		{Word: "ab", Start: 5, End: 7, ByteStart: 15, ByteEnd: 17, UTF16Start: 7, UTF16End: 9},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("match offsets, got %v, expect %v", got, expect)
+	}
+	for _, m := range got {
+		if text[m.ByteStart:m.ByteEnd] != m.Word {
+			t.Errorf("byte offsets of %q, got %q", m.Word, text[m.ByteStart:m.ByteEnd])
+		}
+	}
+
+	if got := filter.matcher().FindAllWithIndex(text, MatchDefault, false); !reflect.DeepEqual(got, expect) {
+		t.Errorf("trie match offsets, got %v, expect %v", got, expect)
+	}
+}
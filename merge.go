@@ -0,0 +1,168 @@
+package sensitive
+
+// MergedSpan 相邻或重叠的命中合并成的一段区间，Word为区间在原文中的文本，
+// Matches为区间内的全部命中
+type MergedSpan struct {
+	Match
+	Matches []Match
+}
+
+// SetMergeSpans 设置默认过滤器是否合并相邻的命中
+func SetMergeSpans(enable bool) {
+	Default().SetMergeSpans(enable)
+}
+
+// SetMergeSpans 开启后Replace、FilterWord等把首尾相接或相互重叠的命中合并为
+// 一段整体处理，如词典中有"垃圾"和"圾桶"时"垃圾桶"整段被和谐，
+// ActionReplaceString只替换一次。合并后不再按匹配策略选取，
+// 动作不同的命中不合并，与已有区间重叠时被忽略
+func (filter *Filter) SetMergeSpans(enable bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.mergeSpans = enable
+}
+
+// FindMergedSpans 找出默认过滤器中合并后的命中区间
+func FindMergedSpans(text string) []MergedSpan {
+	return Default().FindMergedSpans(text)
+}
+
+// FindMergedSpans 返回text中相邻或重叠的命中合并成的区间，不论是否开启了SetMergeSpans
+func (filter *Filter) FindMergedSpans(text string) []MergedSpan {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	text, err := filter.input(text)
+	if err != nil || filter.skip(text) {
+		return nil
+	}
+	return filter.spans(text, Action{Kind: ActionReplace})
+}
+
+// FilterWithMergedSpans 和谐敏感词并返回合并后的区间
+func FilterWithMergedSpans(text string, repl rune) (string, []MergedSpan) {
+	return Default().FilterWithMergedSpans(text, repl)
+}
+
+// FilterWithMergedSpans 同FilterWithDetails，按合并后的区间和谐敏感词并返回这些区间，
+// 不论是否开启了SetMergeSpans
+func (filter *Filter) FilterWithMergedSpans(text string, repl rune) (string, []MergedSpan) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	text, err := filter.input(text)
+	if err != nil {
+		return "", nil
+	}
+	if filter.skip(text) {
+		return text, nil
+	}
+	fallback := Action{Kind: ActionReplace, Rune: repl}
+	spans := filter.spans(text, fallback)
+	return filter.rewriteSpans(text, spans, fallback), spans
+}
+
+// spans 合并text中的命中并补全各个下标，开启跨噪音匹配时在去噪后的文本上合并，
+// 只隔着噪音字符的命中也视为相邻，调用方需持有锁
+func (filter *Filter) spans(text string, fallback Action) []MergedSpan {
+	var spans []MergedSpan
+	if filter.throughNoise {
+		runes, index := normalize(text, filter.noiseNormalizers())
+		spans = filter.joinSpans(filter.allMatches(string(runes)), fallback)
+		for i := range spans {
+			s := &spans[i]
+			s.Start, s.End = index[s.Start], index[s.End-1]+1
+			for j, m := range s.Matches {
+				s.Matches[j].Start, s.Matches[j].End = index[m.Start], index[m.End-1]+1
+			}
+		}
+	} else {
+		spans = filter.joinSpans(filter.allMatches(text), fallback)
+	}
+
+	var ptrs []*Match
+	for i := range spans {
+		ptrs = append(ptrs, &spans[i].Match)
+		for j := range spans[i].Matches {
+			ptrs = append(ptrs, &spans[i].Matches[j])
+		}
+	}
+	fillOffsets(text, ptrs)
+	for i := range spans {
+		spans[i].Word = text[spans[i].ByteStart:spans[i].ByteEnd]
+	}
+	return spans
+}
+
+// joinSpans 从左到右合并按起点、终点升序排列的命中，调用方需持有锁
+func (filter *Filter) joinSpans(all []Match, fallback Action) []MergedSpan {
+	var (
+		spans  []MergedSpan
+		action Action
+	)
+	for _, m := range all {
+		_, a := filter.actionOf(m.Word, fallback)
+		if n := len(spans); n > 0 && m.Start <= spans[n-1].End {
+			last := &spans[n-1]
+			if a != action {
+				if m.Start < last.End {
+					continue
+				}
+			} else {
+				if m.End > last.End {
+					last.End = m.End
+				}
+				last.Matches = append(last.Matches, m)
+				continue
+			}
+		}
+		spans = append(spans, MergedSpan{Match: Match{Start: m.Start, End: m.End}, Matches: []Match{m}})
+		action = a
+	}
+	return spans
+}
+
+// rewriteSpans 按分类动作改写text中的区间，调用方需持有锁
+func (filter *Filter) rewriteSpans(text string, spans []MergedSpan, fallback Action) string {
+	var (
+		runes  = []rune(text)
+		result = make([]rune, 0, len(runes))
+		left   = 0
+	)
+	for _, s := range spans {
+		category, action := filter.actionOf(s.Matches[0].Word, fallback)
+		if action.Kind == ActionReject {
+			action = fallback
+		}
+
+		result = append(result, runes[left:s.Start]...)
+		left = s.End
+		switch action.Kind {
+		case ActionReplace:
+			for i := s.Start; i < s.End; i++ {
+				result = append(result, action.Rune)
+			}
+		case ActionReplaceString:
+			result = append(result, []rune(action.String)...)
+		case ActionBlock:
+		case ActionLog:
+			result = append(result, runes[s.Start:s.End]...)
+			if filter.logHandler != nil {
+				for _, m := range s.Matches {
+					filter.logHandler(category, m)
+				}
+			}
+		}
+	}
+	return string(append(result, runes[left:]...))
+}
+
+// spanMatches 按顺序返回区间内的全部命中
+func spanMatches(spans []MergedSpan) []Match {
+	var matches []Match
+	for _, s := range spans {
+		matches = append(matches, s.Matches...)
+	}
+	return matches
+}
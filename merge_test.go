@@ -0,0 +1,56 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeSpans(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾", "圾桶", "东西")
+
+	text := "这个垃圾桶东西，真是垃圾"
+	if got := filter.Replace(text, '*'); got != "这个**桶**，真是**" {
+		t.Errorf("replace without merging, got %s", got)
+	}
+
+	filter.SetMergeSpans(true)
+	if got := filter.Replace(text, '*'); got != "这个*****，真是**" {
+		t.Errorf("replace with merging, got %s", got)
+	}
+	if got := filter.FilterWord(text); got != "这个，真是" {
+		t.Errorf("filter word with merging, got %s", got)
+	}
+
+	result, spans := filter.FilterWithMergedSpans(text, '*')
+	if result != "这个*****，真是**" {
+		t.Errorf("filter with spans, got %s", result)
+	}
+	var words []string
+	for _, s := range spans {
+		words = append(words, s.Word)
+	}
+	if !reflect.DeepEqual(words, []string{"垃圾桶东西", "垃圾"}) {
+		t.Fatalf("merged spans, got %v", words)
+	}
+	first := spans[0]
+	if first.Start != 2 || first.End != 7 || first.ByteStart != 6 || first.ByteEnd != 21 || len(first.Matches) != 3 {
+		t.Errorf("first span, got %+v", first)
+	}
+	if got := filter.FindMergedSpans(text); !reflect.DeepEqual(got, spans) {
+		t.Errorf("find spans, got %+v", got)
+	}
+
+	// 分类动作不同的命中不合并，相同的只替换一次
+	filter.AddWordWithCategory("ad", "加微信", "加QQ")
+	filter.SetCategoryAction("ad", Action{Kind: ActionReplaceString, String: "[广告]"})
+	if got := filter.Replace("加微信加QQ垃圾", '*'); got != "[广告]**" {
+		t.Errorf("replace with category action, got %s", got)
+	}
+
+	// 跨噪音匹配时只隔着噪音的命中视为相邻
+	filter.SetMatchThroughNoise(true)
+	if got := filter.Replace("垃 圾 东西", '*'); got != "******" {
+		t.Errorf("replace through noise, got %s", got)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package sensitive
+
+// mmapFile 当前平台不支持内存映射，返回nil由调用方改为读入内存
+func mmapFile(path string) ([]byte, error) {
+	return nil, nil
+}
+
+func munmap(data []byte) error {
+	return nil
+}
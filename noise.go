@@ -0,0 +1,195 @@
+package sensitive
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// 内置的噪音预设，可用于AddNoisePreset
+const (
+	// NoiseWhitespace 空白字符，包括全角空格
+	NoiseWhitespace = "whitespace"
+	// NoisePunctuation 标点和符号
+	NoisePunctuation = "punctuation"
+	// NoiseInvisible 零宽字符等不可见的格式控制字符
+	NoiseInvisible = "invisible"
+)
+
+var noisePresets = map[string]string{
+	NoiseWhitespace:  `[\s\x{3000}]+`,
+	NoisePunctuation: `[\p{P}\p{S}]+`,
+	NoiseInvisible:   `[\p{Cf}\x{034F}\x{180E}]+`,
+}
+
+// ErrUnknownPreset 没有该名称的内置噪音预设
+var ErrUnknownPreset = errors.New("sensitive: unknown noise preset")
+
+// noisePattern 按名称维护的噪音正则
+type noisePattern struct {
+	name string
+	re   regex
+}
+
+// AddNoisePattern 添加一个命名的噪音模式
+func AddNoisePattern(name, pattern string) error {
+	return Default().AddNoisePattern(name, pattern)
+}
+
+// AddNoisePattern 添加一个命名的噪音模式。去噪时先应用UpdateNoisePattern
+// 设置的默认模式，再按添加顺序依次应用各命名模式；同名模式被替换，位置不变
+func (filter *Filter) AddNoisePattern(name, pattern string) error {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return err
+	}
+
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	for i := range filter.noisePatterns {
+		if filter.noisePatterns[i].name == name {
+			filter.noisePatterns[i].re = re
+			return nil
+		}
+	}
+	filter.noisePatterns = append(filter.noisePatterns, noisePattern{name: name, re: re})
+	return nil
+}
+
+// AddNoisePreset 添加内置的噪音预设
+func AddNoisePreset(preset string) error {
+	return Default().AddNoisePreset(preset)
+}
+
+// AddNoisePreset 以预设名为名称添加内置的噪音预设，如NoiseWhitespace
+func (filter *Filter) AddNoisePreset(preset string) error {
+	pattern, ok := noisePresets[preset]
+	if !ok {
+		return ErrUnknownPreset
+	}
+	return filter.AddNoisePattern(preset, pattern)
+}
+
+// RemoveNoisePattern 删除命名的噪音模式
+func RemoveNoisePattern(name string) bool {
+	return Default().RemoveNoisePattern(name)
+}
+
+// RemoveNoisePattern 删除命名的噪音模式，不存在时返回false
+func (filter *Filter) RemoveNoisePattern(name string) bool {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	for i := range filter.noisePatterns {
+		if filter.noisePatterns[i].name == name {
+			filter.noisePatterns = append(filter.noisePatterns[:i:i], filter.noisePatterns[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SetNoiseFunc 设置噪音字符判定函数
+func SetNoiseFunc(fn func(r rune) bool) {
+	Default().SetNoiseFunc(fn)
+}
+
+// SetNoiseFunc 设置噪音字符判定函数，fn返回true的字符在去噪时被删除，
+// 在全部噪音正则之后应用，用于正则难以表达的规则(如某几个Unicode区间)。
+// fn为nil时取消
+func (filter *Filter) SetNoiseFunc(fn func(r rune) bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.noiseFunc = fn
+}
+
+// noiseRegexps 按应用顺序返回全部噪音正则，调用方需持有锁
+func (filter *Filter) noiseRegexps() []regex {
+	res := make([]regex, 0, len(filter.noisePatterns)+1)
+	if filter.noise != nil {
+		res = append(res, filter.noise)
+	}
+	for _, p := range filter.noisePatterns {
+		res = append(res, p.re)
+	}
+	return res
+}
+
+// removeNoise 依次应用全部噪音模式，调用方需持有锁
+func (filter *Filter) removeNoise(text string) string {
+	if filter.noiseSet != nil {
+		text = strings.Map(filter.noiseSet.drop, text)
+	}
+	for _, re := range filter.noiseRegexps() {
+		text = re.ReplaceAllString(text, "")
+	}
+	if filter.noiseFunc != nil {
+		text = strings.Map(filter.dropNoise, text)
+	}
+	return text
+}
+
+// removeNoiseBytes 同removeNoise，调用方需持有锁
+func (filter *Filter) removeNoiseBytes(text []byte) []byte {
+	if filter.noiseSet != nil {
+		text = bytes.Map(filter.noiseSet.drop, text)
+	}
+	for _, re := range filter.noiseRegexps() {
+		text = re.ReplaceAll(text, nil)
+	}
+	if filter.noiseFunc != nil {
+		text = bytes.Map(filter.dropNoise, text)
+	}
+	return text
+}
+
+// dropNoise 用于strings.Map，噪音字符映射为-1即删除
+func (filter *Filter) dropNoise(r rune) rune {
+	if filter.noiseFunc(r) {
+		return -1
+	}
+	return r
+}
+
+// noiseNormalizers 以归一化器的形式返回全部噪音模式，调用方需持有锁
+func (filter *Filter) noiseNormalizers() []Normalizer {
+	var normalizers []Normalizer
+	if filter.noiseSet != nil {
+		normalizers = append(normalizers, RuneRemover(filter.noiseSet.has))
+	}
+	for _, re := range filter.noiseRegexps() {
+		normalizers = append(normalizers, &RegexpRemover{re: re})
+	}
+	if filter.noiseFunc != nil {
+		normalizers = append(normalizers, RuneRemover(filter.noiseFunc))
+	}
+	return normalizers
+}
+
+// SetMatchThroughNoise 设置Replace、FilterWord是否跨过噪音字符匹配
+func SetMatchThroughNoise(enable bool) {
+	Default().SetMatchThroughNoise(enable)
+}
+
+// SetMatchThroughNoise 开启后Replace、FilterWord、FilterWithDetails等在去噪后的
+// 文本上匹配，并处理原文中从词首到词尾的整段，包括夹在词中的噪音字符，
+// 如"傻 逼"整体替换为"***"。词两侧的噪音字符不受影响
+func (filter *Filter) SetMatchThroughNoise(enable bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.throughNoise = enable
+}
+
+// noiseMatches 在去噪后的文本上选出命中，并将其位置还原为原文中的区间，
+// Word仍为词典中的词，调用方需持有锁
+func (filter *Filter) noiseMatches(text string) []Match {
+	runes, index := normalize(text, filter.noiseNormalizers())
+	matches := filter.matches(string(runes))
+	for i, m := range matches {
+		matches[i].Start, matches[i].End = index[m.Start], index[m.End-1]+1
+	}
+	return matches
+}
@@ -0,0 +1,60 @@
+package sensitive
+
+// DefaultNoiseRunes 默认的噪音字符，与正则`[\|\s&%$@*]+`等价
+const DefaultNoiseRunes = "|\t\n\f\r &%$@*"
+
+// runeSet 字符集合，ASCII字符用位图判定
+type runeSet struct {
+	ascii [2]uint64
+	other map[rune]struct{}
+}
+
+func newRuneSet(chars string) *runeSet {
+	if chars == "" {
+		return nil
+	}
+	set := &runeSet{}
+	for _, r := range chars {
+		if r < 128 {
+			set.ascii[r/64] |= 1 << (uint(r) % 64)
+			continue
+		}
+		if set.other == nil {
+			set.other = make(map[rune]struct{})
+		}
+		set.other[r] = struct{}{}
+	}
+	return set
+}
+
+func (set *runeSet) has(r rune) bool {
+	if r >= 0 && r < 128 {
+		return set.ascii[r/64]&(1<<(uint(r)%64)) != 0
+	}
+	_, ok := set.other[r]
+	return ok
+}
+
+// drop 用于strings.Map，集合中的字符映射为-1即删除
+func (set *runeSet) drop(r rune) rune {
+	if set.has(r) {
+		return -1
+	}
+	return r
+}
+
+// UpdateNoiseRunes 用字符集合更新去噪模式
+func UpdateNoiseRunes(chars string) {
+	Default().UpdateNoiseRunes(chars)
+}
+
+// UpdateNoiseRunes 用字符集合代替UpdateNoisePattern的正则作为默认去噪模式，
+// chars中的每个字符都是噪音，为空时不去噪。默认即为DefaultNoiseRunes。
+// 只使用字符集合时去噪不依赖regexp，也比正则快得多
+func (filter *Filter) UpdateNoiseRunes(chars string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.noiseSet = newRuneSet(chars)
+	filter.noise = nil
+}
@@ -0,0 +1,42 @@
+package sensitive
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestDefaultNoiseRunes(t *testing.T) {
+	re := regexp.MustCompile(`[\|\s&%$@*]+`)
+	set := newRuneSet(DefaultNoiseRunes)
+	for r := rune(0); r < 0x3100; r++ {
+		if got, want := set.has(r), re.MatchString(string(r)); got != want {
+			t.Errorf("%U: got %v, want %v", r, got, want)
+		}
+	}
+}
+
+func TestUpdateNoiseRunes(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情")
+	if found, _ := filter.FindIn("色 | 情"); !found {
+		t.Errorf("default noise runes should be removed")
+	}
+
+	filter.UpdateNoiseRunes("·　")
+	if found, _ := filter.FindIn("色·　情"); !found {
+		t.Errorf("custom noise runes should be removed")
+	}
+	if found, _ := filter.FindIn("色|情"); found {
+		t.Errorf("default noise runes should be replaced")
+	}
+
+	filter.UpdateNoisePattern(`-+`)
+	if found, _ := filter.FindIn("色--情"); !found || filter.noiseSet != nil {
+		t.Errorf("noise pattern should replace the noise runes")
+	}
+
+	filter.UpdateNoiseRunes("")
+	if got := filter.RemoveNoise("色-情"); got != "色-情" {
+		t.Errorf("empty noise runes, got %s", got)
+	}
+}
@@ -0,0 +1,88 @@
+package sensitive
+
+import "testing"
+
+func TestNoisePatterns(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+
+	if found, _ := filter.FindIn("垃，圾"); found {
+		t.Errorf("punctuation should not be noise by default")
+	}
+	if err := filter.AddNoisePreset(NoisePunctuation); err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.AddNoisePreset(NoiseInvisible); err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.AddNoisePattern("x", `x`); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"垃，圾", "垃​圾", "垃x圾", "垃 ,x​圾"} {
+		if found, word := filter.FindIn(text); !found || word != "垃圾" {
+			t.Errorf("findin %q, got %v %s", text, found, word)
+		}
+	}
+	if got := filter.RemoveNoise("a b,c​xd"); got != "abcd" {
+		t.Errorf("remove noise, got %q", got)
+	}
+	if found, _, runeOffset, _ := filter.FindInIndex("好，垃x圾"); !found || runeOffset != 2 {
+		t.Errorf("findin index, got %v %d", found, runeOffset)
+	}
+
+	if !filter.RemoveNoisePattern("x") || filter.RemoveNoisePattern("x") {
+		t.Errorf("remove noise pattern should report existence")
+	}
+	if found, _ := filter.FindIn("垃x圾"); found {
+		t.Errorf("removed pattern should no longer apply")
+	}
+	if err := filter.AddNoisePreset("unknown"); err != ErrUnknownPreset {
+		t.Errorf("unknown preset, got %v", err)
+	}
+}
+
+func TestNoiseFunc(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	// 删除所有emoji区间的字符
+	filter.SetNoiseFunc(func(r rune) bool { return r >= 0x1F300 && r <= 0x1FAFF })
+
+	if found, word := filter.FindIn("垃😀圾"); !found || word != "垃圾" {
+		t.Errorf("findin, got %v %s", found, word)
+	}
+	if found, _ := filter.FindInBytes([]byte("垃🔥圾")); !found {
+		t.Errorf("findin bytes should apply noise func")
+	}
+	if found, _, runeOffset, byteOffset := filter.FindInIndex("好垃😀圾"); !found || runeOffset != 1 || byteOffset != 3 {
+		t.Errorf("findin index, got %v %d %d", found, runeOffset, byteOffset)
+	}
+
+	filter.SetNoiseFunc(nil)
+	if found, _ := filter.FindIn("垃😀圾"); found {
+		t.Errorf("noise func should be cleared")
+	}
+}
+
+func TestMatchThroughNoise(t *testing.T) {
+	filter := New()
+	filter.AddWord("傻逼", "垃圾")
+
+	if got := filter.Replace("你 傻 逼", '*'); got != "你 傻 逼" {
+		t.Errorf("noise should not be skipped by default, got %s", got)
+	}
+
+	filter.SetMatchThroughNoise(true)
+	if got := filter.Replace("你 傻 逼 啊", '*'); got != "你 *** 啊" {
+		t.Errorf("replace, got %s", got)
+	}
+	if got := filter.FilterWord("垃&&圾，傻|逼"); got != "，" {
+		t.Errorf("filter, got %s", got)
+	}
+	if got := string(filter.ReplaceBytes(nil, []byte("垃 圾"), '*')); got != "***" {
+		t.Errorf("replace bytes, got %s", got)
+	}
+	got, matches := filter.FilterWithDetails("是垃 圾", '*')
+	if got != "是***" || len(matches) != 1 || matches[0] != (Match{Word: "垃圾", Start: 1, End: 4, ByteStart: 3, ByteEnd: 10, UTF16Start: 1, UTF16End: 4}) {
+		t.Errorf("filter with details, got %s %v", got, matches)
+	}
+}
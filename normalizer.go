@@ -0,0 +1,156 @@
+package sensitive
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Normalizer 文本归一化器。runes为待处理的字符，index[i]为runes[i]在原文中
+// 的rune下标；返回处理后的字符及对应的原文下标，二者长度相同，
+// 借此可将归一化文本上的命中还原到原文中的位置
+type Normalizer interface {
+	Normalize(runes []rune, index []int) ([]rune, []int)
+}
+
+// RuneMapper 逐个字符映射的归一化器，如大小写折叠
+type RuneMapper func(r rune) rune
+
+// Normalize 将每个字符替换为映射后的字符
+func (fn RuneMapper) Normalize(runes []rune, index []int) ([]rune, []int) {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		out[i] = fn(r)
+	}
+	return out, index
+}
+
+// RuneRemover 删除部分字符的归一化器，fn返回true的字符被删除
+type RuneRemover func(r rune) bool
+
+// Normalize 删除fn返回true的字符
+func (fn RuneRemover) Normalize(runes []rune, index []int) ([]rune, []int) {
+	var (
+		out    = make([]rune, 0, len(runes))
+		outIdx = make([]int, 0, len(index))
+	)
+	for i, r := range runes {
+		if !fn(r) {
+			out = append(out, r)
+			outIdx = append(outIdx, index[i])
+		}
+	}
+	return out, outIdx
+}
+
+// RegexpRemover 删除匹配正则的片段的归一化器
+type RegexpRemover struct {
+	re regex
+}
+
+// NewRegexpRemover 返回删除匹配pattern的片段的归一化器
+func NewRegexpRemover(pattern string) (*RegexpRemover, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &RegexpRemover{re: re}, nil
+}
+
+// Normalize 删除匹配正则的片段
+func (rr *RegexpRemover) Normalize(runes []rune, index []int) ([]rune, []int) {
+	text := string(runes)
+	locs := rr.re.FindAllStringIndex(text, -1)
+	if len(locs) == 0 {
+		return runes, index
+	}
+
+	var (
+		out    = make([]rune, 0, len(runes))
+		outIdx = make([]int, 0, len(index))
+		pos    = 0
+		offset = 0
+	)
+	for _, loc := range locs {
+		n := utf8.RuneCountInString(text[offset:loc[0]])
+		out = append(out, runes[pos:pos+n]...)
+		outIdx = append(outIdx, index[pos:pos+n]...)
+		pos += n + utf8.RuneCountInString(text[loc[0]:loc[1]])
+		offset = loc[1]
+	}
+	out = append(out, runes[pos:]...)
+	outIdx = append(outIdx, index[pos:]...)
+	return out, outIdx
+}
+
+var (
+	// CaseFolder 将字母转为小写
+	CaseFolder = RuneMapper(unicode.ToLower)
+	// WidthFolder 将全角ASCII字符和全角空格转为半角
+	WidthFolder = RuneMapper(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			return r - '！' + '!'
+		}
+		return r
+	})
+	// SpaceRemover 删除所有空白字符
+	SpaceRemover = RuneRemover(unicode.IsSpace)
+)
+
+// spanNormalizer 由多个字符折叠为一个字符的归一化器另外实现的接口。
+// start[i]、end[i]为runes[i]对应的原文区间，返回处理后各字符对应的区间，
+// 使命中还原到原文时覆盖被折叠的全部字符
+type spanNormalizer interface {
+	normalizeSpan(runes []rune, start, end []int) ([]rune, []int, []int)
+}
+
+// normalize 依次应用归一化器，返回归一化后的字符及其原文下标
+func normalize(text string, normalizers []Normalizer) ([]rune, []int) {
+	runes := []rune(text)
+	index := make([]int, len(runes))
+	for i := range index {
+		index[i] = i
+	}
+	for _, n := range normalizers {
+		runes, index = n.Normalize(runes, index)
+	}
+	return runes, index
+}
+
+// normalizeSpans 同normalize，返回每个字符对应的原文区间[start, end)
+func normalizeSpans(text string, normalizers []Normalizer) (runes []rune, start, end []int) {
+	runes = []rune(text)
+	start = make([]int, len(runes))
+	end = make([]int, len(runes))
+	for i := range runes {
+		start[i], end[i] = i, i+1
+	}
+	for _, n := range normalizers {
+		if sn, ok := n.(spanNormalizer); ok {
+			runes, start, end = sn.normalizeSpan(runes, start, end)
+			continue
+		}
+
+		// 以处理前的下标调用Normalize，再换算为原文区间
+		pos := make([]int, len(runes))
+		for i := range pos {
+			pos[i] = i
+		}
+		runes, pos = n.Normalize(runes, pos)
+		nextStart, nextEnd := make([]int, len(pos)), make([]int, len(pos))
+		for i, p := range pos {
+			nextStart[i], nextEnd[i] = start[p], end[p]
+		}
+		start, end = nextStart, nextEnd
+	}
+	return runes, start, end
+}
+
+// restore 将归一化文本上的命中还原为原文中的位置和词语，
+// 命中以emoji结尾时延伸到整个emoji序列
+func restore(m Match, original []rune, start, end []int) Match {
+	from, to := start[m.Start], emojiEnd(original, end[m.End-1])
+	return Match{Word: string(original[from:to]), Start: from, End: to}
+}
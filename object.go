@@ -0,0 +1,90 @@
+package sensitive
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ObjectStore 读取对象存储中的对象，S3、GCS、OSS等的SDK只需包装一层即可使用，
+// 本包不依赖任何SDK。例如使用aws-sdk-go-v2的S3：
+//
+//	type s3Store struct{ client *s3.Client }
+//
+//	func (s s3Store) GetObject(ctx context.Context, bucket, key, versionID string) (io.ReadCloser, sensitive.ObjectInfo, error) {
+//		input := &s3.GetObjectInput{Bucket: &bucket, Key: &key}
+//		if versionID != "" {
+//			input.VersionId = &versionID
+//		}
+//		out, err := s.client.GetObject(ctx, input)
+//		if err != nil {
+//			return nil, sensitive.ObjectInfo{}, err
+//		}
+//		return out.Body, sensitive.ObjectInfo{VersionID: aws.ToString(out.VersionId), ETag: aws.ToString(out.ETag)}, nil
+//	}
+//
+// GCS对应storage.ObjectHandle的Generation和NewReader，OSS对应
+// Bucket.GetObject及oss.VersionId选项
+type ObjectStore interface {
+	// GetObject 读取bucket中的key，versionID为空时读取最新版本
+	GetObject(ctx context.Context, bucket, key, versionID string) (io.ReadCloser, ObjectInfo, error)
+}
+
+// ObjectInfo 对象的版本信息，不支持的字段留空
+type ObjectInfo struct {
+	// VersionID 开启版本控制时对象的版本号，GCS为generation
+	VersionID    string
+	ETag         string
+	LastModified time.Time
+}
+
+// version 按VersionID、ETag、LastModified的顺序选取作为来源版本的字段
+func (info ObjectInfo) version() string {
+	switch {
+	case info.VersionID != "":
+		return info.VersionID
+	case info.ETag != "":
+		return info.ETag
+	case !info.LastModified.IsZero():
+		return strconv.FormatInt(info.LastModified.UnixNano(), 10)
+	}
+	return ""
+}
+
+// ObjectSource 对象存储中的词典，可交给Manager定时刷新
+type ObjectSource struct {
+	Store  ObjectStore
+	Bucket string
+	Key    string
+	// VersionID 固定读取的版本，为空时读取最新版本
+	VersionID string
+}
+
+// Fetch 读取对象，版本号、ETag或修改时间作为来源版本
+func (source ObjectSource) Fetch(ctx context.Context) (io.ReadCloser, string, error) {
+	rc, info, err := source.Store.GetObject(ctx, source.Bucket, source.Key, source.VersionID)
+	if err != nil {
+		return nil, "", err
+	}
+	return rc, info.version(), nil
+}
+
+// LoadObject 从对象存储加载词典
+func LoadObject(ctx context.Context, store ObjectStore, bucket, key, versionID string) error {
+	return Default().LoadObject(ctx, store, bucket, key, versionID)
+}
+
+// LoadObject 读取对象存储中bucket的key并加载，versionID为空时读取最新版本，
+// 加载状态中的来源记为"bucket/key"
+func (filter *Filter) LoadObject(ctx context.Context, store ObjectStore, bucket, key, versionID string) error {
+	name := bucket + "/" + key
+	filter.beginLoad(name)
+	rc, _, err := store.GetObject(ctx, bucket, key, versionID)
+	if err != nil {
+		return filter.recordLoad(name, err)
+	}
+	defer rc.Close()
+	return filter.load(name, bufio.NewReader(rc), EncodingAuto, nil)
+}
@@ -0,0 +1,67 @@
+package sensitive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeBucket 带版本控制的内存对象存储
+type fakeBucket struct {
+	versions map[string][]string
+}
+
+func (b *fakeBucket) GetObject(ctx context.Context, bucket, key, versionID string) (io.ReadCloser, ObjectInfo, error) {
+	versions := b.versions[bucket+"/"+key]
+	if len(versions) == 0 {
+		return nil, ObjectInfo{}, fmt.Errorf("no such key %s", key)
+	}
+	i := len(versions) - 1
+	if versionID != "" {
+		fmt.Sscan(versionID, &i)
+	}
+	return io.NopCloser(strings.NewReader(versions[i])), ObjectInfo{VersionID: fmt.Sprint(i)}, nil
+}
+
+func TestLoadObject(t *testing.T) {
+	store := &fakeBucket{versions: map[string][]string{"dicts/words.txt": {"垃圾\n", "加微信\n"}}}
+
+	filter := New()
+	if err := filter.LoadObject(context.Background(), store, "dicts", "words.txt", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if !filter.HasWord("垃圾") || filter.HasWord("加微信") {
+		t.Errorf("pinned version not loaded, words %v", filter.Words())
+	}
+	if state := filter.LoadState(); state.Source != "dicts/words.txt" || state.Status != LoadReady {
+		t.Errorf("load state, got %+v", state)
+	}
+	if err := filter.LoadObject(context.Background(), store, "dicts", "missing.txt", ""); err == nil {
+		t.Errorf("expect error for missing object")
+	}
+}
+
+func TestObjectSource(t *testing.T) {
+	store := &fakeBucket{versions: map[string][]string{"dicts/words.txt": {"垃圾\n"}}}
+	filter := New()
+	m := NewManager(filter)
+	m.Add("oss", ObjectSource{Store: store, Bucket: "dicts", Key: "words.txt"}, 0)
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	version := filter.Version()
+	if err := m.Refresh(context.Background()); err != nil || filter.Version() != version {
+		t.Errorf("same object version should not rebuild")
+	}
+
+	store.versions["dicts/words.txt"] = append(store.versions["dicts/words.txt"], "加微信\n")
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !filter.HasWord("加微信") || filter.HasWord("垃圾") {
+		t.Errorf("new object version not loaded, words %v", filter.Words())
+	}
+}
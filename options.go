@@ -0,0 +1,139 @@
+package sensitive
+
+// Options 单次查询的选项，只影响本次调用，不修改过滤器的状态
+type Options struct {
+	// CaseFold 匹配前将文本中的字母转为小写，词典中的词应为小写
+	CaseFold bool
+	// MaxMatches 最多处理的命中个数，<=0表示不限制
+	MaxMatches int
+	// Categories 只处理属于这些分类的词，为空时处理全部
+	Categories []string
+	// DisableNoise 不去除噪音字符。默认在去噪后的文本上匹配，
+	// 命中在原文中的区间包括夹在词中的噪音字符
+	DisableNoise bool
+	// MinLevel 只处理等级不低于MinLevel的词，见Level
+	MinLevel int
+	// Policy 匹配策略，为MatchDefault时使用过滤器的策略
+	Policy MatchPolicy
+}
+
+// optMatches 按选项选出text中互不重叠的命中，位置为原文中的rune下标，
+// Word为词典中的词，调用方需持有锁
+func (filter *Filter) optMatches(text string, opts Options) []Match {
+	text, err := filter.input(text)
+	if err != nil {
+		return nil
+	}
+	var normalizers []Normalizer
+	if opts.CaseFold {
+		normalizers = append(normalizers, CaseFolder)
+	}
+	if !opts.DisableNoise {
+		normalizers = append(normalizers, filter.noiseNormalizers()...)
+	}
+	runes, index := normalize(text, normalizers)
+	cleaned := string(runes)
+	if filter.skip(cleaned) {
+		return nil
+	}
+
+	var (
+		all        = filter.allMatches(cleaned)
+		categories map[string]struct{}
+		policy     = opts.Policy
+	)
+	if len(opts.Categories) > 0 {
+		categories = categorySet(opts.Categories)
+	}
+	if policy == MatchDefault {
+		policy = filter.policy
+	}
+
+	// 先筛选再选取，被筛掉的词不会挡住与其重叠的词
+	kept := all[:0]
+	for _, m := range all {
+		if categories != nil {
+			if _, ok := categories[filter.meta[m.Word].category]; !ok {
+				continue
+			}
+		}
+		if filter.meta[m.Word].level < opts.MinLevel {
+			continue
+		}
+		kept = append(kept, m)
+	}
+
+	matches := selectMatches(kept, policy)
+	if opts.MaxMatches > 0 && len(matches) > opts.MaxMatches {
+		matches = matches[:opts.MaxMatches]
+	}
+	for i, m := range matches {
+		matches[i].Start, matches[i].End = index[m.Start], index[m.End-1]+1
+	}
+	return matches
+}
+
+// FindInOpt 按选项检测敏感词
+func FindInOpt(text string, opts Options) (bool, string) {
+	return Default().FindInOpt(text, opts)
+}
+
+// FindInOpt 按选项检测敏感词，返回第一个命中的词
+func (filter *Filter) FindInOpt(text string, opts Options) (bool, string) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	opts.MaxMatches = 1
+	if matches := filter.optMatches(text, opts); len(matches) > 0 {
+		return true, matches[0].Word
+	}
+	return false, ""
+}
+
+// FindAllOpt 按选项找到所有匹配词
+func FindAllOpt(text string, opts Options) []string {
+	return Default().FindAllOpt(text, opts)
+}
+
+// FindAllOpt 按选项找到所有匹配词，按出现顺序去重
+func (filter *Filter) FindAllOpt(text string, opts Options) []string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return uniqueWords(filter.optMatches(text, opts))
+}
+
+// ReplaceOpt 按选项和谐敏感词
+func ReplaceOpt(text string, repl rune, opts Options) string {
+	return Default().ReplaceOpt(text, repl, opts)
+}
+
+// ReplaceOpt 按选项和谐敏感词，设置了分类动作的词按其动作处理
+func (filter *Filter) ReplaceOpt(text string, repl rune, opts Options) string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	text, err := filter.input(text)
+	if err != nil {
+		return ""
+	}
+	result, _ := filter.rewriteMatches(text, filter.optMatches(text, opts), Action{Kind: ActionReplace, Rune: repl})
+	return result
+}
+
+// FilterWordOpt 按选项过滤敏感词
+func FilterWordOpt(text string, opts Options) string {
+	return Default().FilterWordOpt(text, opts)
+}
+
+// FilterWordOpt 按选项过滤敏感词，设置了分类动作的词按其动作处理
+func (filter *Filter) FilterWordOpt(text string, opts Options) string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	text, err := filter.input(text)
+	if err != nil {
+		return ""
+	}
+	result, _ := filter.rewriteMatches(text, filter.optMatches(text, opts), Action{Kind: ActionBlock})
+	return result
+}
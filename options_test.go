@@ -0,0 +1,40 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	filter := New()
+	filter.AddWord("bad", "垃圾")
+	filter.AddWordWithCategory("ad", "加微信")
+
+	text := "BAD 垃 圾 bad 请加微信"
+	if got := filter.FindAllOpt(text, Options{}); !reflect.DeepEqual(got, []string{"垃圾", "bad", "加微信"}) {
+		t.Errorf("default options, got %v", got)
+	}
+	if got := filter.FindAllOpt(text, Options{CaseFold: true}); !reflect.DeepEqual(got, []string{"bad", "垃圾", "加微信"}) {
+		t.Errorf("case fold, got %v", got)
+	}
+	if got := filter.FindAllOpt(text, Options{DisableNoise: true}); !reflect.DeepEqual(got, []string{"bad", "加微信"}) {
+		t.Errorf("disable noise, got %v", got)
+	}
+	if got := filter.FindAllOpt(text, Options{Categories: []string{"ad"}}); !reflect.DeepEqual(got, []string{"加微信"}) {
+		t.Errorf("categories, got %v", got)
+	}
+	if got := filter.ReplaceOpt(text, '*', Options{CaseFold: true, MaxMatches: 2}); got != "*** *** bad 请加微信" {
+		t.Errorf("replace, got %s", got)
+	}
+	if got := filter.FilterWordOpt(text, Options{Categories: []string{"ad"}}); got != "BAD 垃 圾 bad 请" {
+		t.Errorf("filter, got %s", got)
+	}
+	if found, word := filter.FindInOpt("Bad", Options{CaseFold: true}); !found || word != "bad" {
+		t.Errorf("findin, got %v %s", found, word)
+	}
+
+	// 选项不影响过滤器本身
+	if got := filter.Replace("BAD", '*'); got != "BAD" {
+		t.Errorf("filter state changed, got %s", got)
+	}
+}
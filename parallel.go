@@ -0,0 +1,111 @@
+package sensitive
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// parallelScan 长文本分段并行匹配的配置，longest按词典版本缓存最长词长
+type parallelScan struct {
+	minRunes int
+	workers  int
+
+	mu      sync.Mutex
+	version uint64
+	longest int
+	valid   bool
+}
+
+// SetParallel 设置默认过滤器的并行匹配
+func SetParallel(minRunes, workers int) {
+	Default().SetParallel(minRunes, workers)
+}
+
+// SetParallel 开启后FindAll和FindAllWithIndex对不少于minRunes个字符的文本
+// 分成workers段并行匹配，段与段之间重叠LongestWord()-1个字符，横跨分段处的
+// 词不会漏掉，结果与串行匹配完全一致。适合几百KB的长文档；短文本上并行的
+// 开销大于收益。分类动作、例外规则等需要逐个检查命中的配置下不生效。
+// workers<=1时关闭
+func (filter *Filter) SetParallel(minRunes, workers int) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if workers <= 1 {
+		filter.parallel = nil
+		return
+	}
+	filter.parallel = &parallelScan{minRunes: minRunes, workers: workers}
+}
+
+// parallelMatches 需要并行匹配时返回text中所有位置上的全部命中，
+// 按起点、终点升序排列；否则ok为false，调用方需持有锁
+func (filter *Filter) parallelMatches(text string) (matches []Match, ok bool) {
+	p := filter.parallel
+	if p == nil || len(text) < p.minRunes || utf8.RuneCountInString(text) < p.minRunes {
+		return nil, false
+	}
+
+	var (
+		runes   = []rune(text)
+		overlap = p.longestWord(filter) - 1
+		size    = (len(runes) + p.workers - 1) / p.workers
+		shards  = make([][]Match, p.workers)
+		wg      sync.WaitGroup
+		m       = filter.matcher()
+	)
+	if overlap < 0 {
+		return nil, true
+	}
+	for i := range shards {
+		start := i * size
+		if start >= len(runes) {
+			break
+		}
+		end := start + size
+		if end > len(runes) {
+			end = len(runes)
+		}
+
+		wg.Add(1)
+		go func(i, start, end int) {
+			defer wg.Done()
+			limit := end + overlap
+			if limit > len(runes) {
+				limit = len(runes)
+			}
+			// 只保留起点在本段内的命中，终点可以落在重叠部分
+			for _, match := range m.matchIndex(string(runes[start:limit]), MatchDefault, true) {
+				if match.Start >= end-start {
+					continue
+				}
+				match.Start += start
+				match.End += start
+				shards[i] = append(shards[i], match)
+			}
+		}(i, start, end)
+	}
+	wg.Wait()
+
+	for _, shard := range shards {
+		matches = append(matches, shard...)
+	}
+	return matches, true
+}
+
+// longestWord 返回词典中最长词的长度，调用方需持有filter的锁
+func (p *parallelScan) longestWord(filter *Filter) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.valid && p.version == filter.version {
+		return p.longest
+	}
+
+	p.longest = 0
+	filter.matcher().Walk(func(word string) bool {
+		if n := utf8.RuneCountInString(word); n > p.longest {
+			p.longest = n
+		}
+		return true
+	})
+	p.version, p.valid = filter.version, true
+	return p.longest
+}
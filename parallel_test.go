@@ -0,0 +1,56 @@
+package sensitive
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParallelMatches(t *testing.T) {
+	var (
+		words  = []string{"色情", "色情网站", "情网", "赌", "赌博网站", "网站"}
+		alpha  = []rune("色情网站赌博好的")
+		rng    = rand.New(rand.NewSource(1))
+		serial = New()
+		par    = New()
+	)
+	serial.AddWord(words...)
+	par.AddWord(words...)
+	par.SetParallel(1, 4)
+
+	for _, policy := range []MatchPolicy{MatchDefault, MatchLongest, MatchShortest} {
+		for _, overlap := range []bool{false, true} {
+			serial.SetMatchPolicy(policy)
+			par.SetMatchPolicy(policy)
+			serial.SetOverlap(overlap)
+			par.SetOverlap(overlap)
+
+			for i := 0; i < 50; i++ {
+				runes := make([]rune, rng.Intn(40))
+				for j := range runes {
+					runes[j] = alpha[rng.Intn(len(alpha))]
+				}
+				text := string(runes)
+
+				if got, expect := par.FindAllWithIndex(text), serial.FindAllWithIndex(text); !reflect.DeepEqual(got, expect) {
+					t.Fatalf("policy %d overlap %v, find all with index %q, got %v, expect %v", policy, overlap, text, got, expect)
+				}
+				if got, expect := par.FindAll(text), serial.FindAll(text); !reflect.DeepEqual(got, expect) {
+					t.Fatalf("policy %d, find all %q, got %v, expect %v", policy, text, got, expect)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkFindAllWithIndexParallel(b *testing.B) {
+	filter := benchFilter()
+	filter.SetParallel(1<<10, 8)
+	text := strings.Repeat(benchText, 1<<12)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		filter.FindAllWithIndex(text)
+	}
+}
@@ -0,0 +1,167 @@
+// Package persist 将词典的修改写入嵌入式KV数据库，重启后直接从数据库恢复，
+// 不需要另外的词典来源。
+//
+// 本包不依赖具体的数据库，只定义了Store接口。以bbolt(go.etcd.io/bbolt)为例，
+// 每个词为bucket中的一个键：
+//
+//	func (s boltStore) Load() ([]string, error) {
+//		var words []string
+//		err := s.db.View(func(tx *bolt.Tx) error {
+//			return tx.Bucket(s.bucket).ForEach(func(k, _ []byte) error {
+//				words = append(words, string(k))
+//				return nil
+//			})
+//		})
+//		return words, err
+//	}
+//
+//	func (s boltStore) Apply(delta sensitive.Delta) error {
+//		return s.db.Update(func(tx *bolt.Tx) error {
+//			if delta.Reset {
+//				if err := tx.DeleteBucket(s.bucket); err != nil {
+//					return err
+//				}
+//			}
+//			b, err := tx.CreateBucketIfNotExists(s.bucket)
+//			if err != nil {
+//				return err
+//			}
+//			for _, word := range delta.Removed {
+//				if err := b.Delete([]byte(word)); err != nil {
+//					return err
+//				}
+//			}
+//			for _, word := range delta.Added {
+//				if err := b.Put([]byte(word), nil); err != nil {
+//					return err
+//				}
+//			}
+//			return nil
+//		})
+//	}
+//
+// Badger的实现类似，在db.Update中用txn.Set、txn.Delete写入，
+// Load时以IteratorOptions{PrefetchValues: false}遍历全部键。
+package persist
+
+import (
+	"sync"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// Store 保存词典的数据库
+type Store interface {
+	// Load 返回保存的全部词语
+	Load() ([]string, error)
+	// Apply 在一个事务中写入一次修改，Reset为true时先清空再写入Added
+	Apply(delta sensitive.Delta) error
+}
+
+// Persister 将filter的修改按顺序写入Store
+type Persister struct {
+	store  Store
+	cancel func()
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []sensitive.Delta
+	writing bool
+	closed  bool
+	err     error
+	done    chan struct{}
+}
+
+// Open 从store恢复filter的词典，之后filter的每次修改(AddWord、DelWord、
+// 加载词典等)都在后台按顺序写入store。store为空时(如首次启动)改为将filter
+// 当前的词典写入store。应在其他goroutine修改filter之前调用
+func Open(filter *sensitive.Filter, store Store) (*Persister, error) {
+	words, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if len(words) > 0 {
+		filter.ResetWords(words...)
+	} else if err := store.Apply(sensitive.Delta{Reset: true, Added: filter.WordsWithPrefix("", 0)}); err != nil {
+		return nil, err
+	}
+
+	p := &Persister{store: store, done: make(chan struct{})}
+	p.cond = sync.NewCond(&p.mu)
+	// Watch的回调持有filter的写锁，只入队，由后台goroutine写入
+	p.cancel = filter.Watch(p.enqueue)
+	go p.run()
+	return p, nil
+}
+
+func (p *Persister) enqueue(delta sensitive.Delta) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.pending = append(p.pending, delta)
+	p.cond.Broadcast()
+}
+
+func (p *Persister) run() {
+	defer close(p.done)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		for len(p.pending) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.pending) == 0 {
+			return
+		}
+
+		batch := p.pending
+		p.pending = nil
+		p.writing = true
+		p.mu.Unlock()
+		var err error
+		for _, delta := range batch {
+			if err = p.store.Apply(delta); err != nil {
+				break
+			}
+		}
+		p.mu.Lock()
+		p.writing = false
+		if err != nil && p.err == nil {
+			p.err = err
+		}
+		p.cond.Broadcast()
+	}
+}
+
+// Flush 等待已发生的修改全部写入store，返回第一次写入失败的错误
+func (p *Persister) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.pending) > 0 || p.writing {
+		p.cond.Wait()
+	}
+	return p.err
+}
+
+// Err 返回第一次写入失败的错误。写入失败后store与filter不再一致，
+// 应重新Open
+func (p *Persister) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Close 停止记录修改，等待已发生的修改写完后返回Err()
+func (p *Persister) Close() error {
+	p.cancel()
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	<-p.done
+	return p.Err()
+}
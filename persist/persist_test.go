@@ -0,0 +1,92 @@
+package persist
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// memStore 内存中的Store，fail非nil时写入失败
+type memStore struct {
+	mu    sync.Mutex
+	words map[string]bool
+	fail  error
+}
+
+func (s *memStore) Load() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var words []string
+	for word := range s.words {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words, nil
+}
+
+func (s *memStore) Apply(delta sensitive.Delta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail != nil {
+		return s.fail
+	}
+	if delta.Reset {
+		s.words = make(map[string]bool)
+	}
+	for _, word := range delta.Removed {
+		delete(s.words, word)
+	}
+	for _, word := range delta.Added {
+		s.words[word] = true
+	}
+	return nil
+}
+
+func TestPersister(t *testing.T) {
+	store := &memStore{words: make(map[string]bool)}
+
+	// 首次启动时store为空，写入filter当前的词典
+	filter := sensitive.New()
+	filter.AddWord("色情")
+	p, err := Open(filter, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter.AddWord("赌博", "毒品")
+	filter.DelWord("色情")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	filter.AddWord("关闭后")
+
+	expect := []string{"毒品", "赌博"}
+	if words, _ := store.Load(); !reflect.DeepEqual(words, expect) {
+		t.Errorf("stored words, got %v, expect %v", words, expect)
+	}
+
+	// 重启后从store恢复
+	restarted := sensitive.New()
+	restarted.AddWord("旧词")
+	p, err = Open(restarted, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := restarted.WordsWithPrefix("", 0); !reflect.DeepEqual(words, expect) {
+		t.Errorf("restored words, got %v, expect %v", words, expect)
+	}
+
+	store.mu.Lock()
+	store.fail = errors.New("disk full")
+	store.mu.Unlock()
+	restarted.AddWord("色情")
+	if err := p.Flush(); err == nil || err.Error() != "disk full" {
+		t.Errorf("flush after failed write, got %v", err)
+	}
+	if err := p.Close(); err == nil {
+		t.Errorf("close after failed write should return the error")
+	}
+}
@@ -0,0 +1,336 @@
+package sensitive
+
+import (
+	"strings"
+	"sync"
+)
+
+// PhoneticConfidence 读音命中的置信度，精确命中为1
+const PhoneticConfidence = 0.6
+
+// PhoneticEncoder 将英文单词编码为读音，读音相同的拼写视为同一个词
+type PhoneticEncoder func(word string) string
+
+// PhoneticMatch 一次读音命中，Word为词典中的词，Start和End为原文中的rune区间，
+// Text为原文中的拼写
+type PhoneticMatch struct {
+	Match
+	Text       string
+	Confidence float64
+}
+
+// phoneticIndex 词典中纯字母词的读音索引，词典修改后按版本号重建
+type phoneticIndex struct {
+	mu      sync.Mutex
+	encode  PhoneticEncoder
+	version uint64
+	codes   map[string][]string
+}
+
+// SetPhonetic 设置默认过滤器的读音编码
+func SetPhonetic(encode PhoneticEncoder) {
+	Default().SetPhonetic(encode)
+}
+
+// SetPhonetic 为词典中由ASCII字母组成的词建立读音索引，供FindPhonetic查找
+// "phuck"、"biatch"之类的变体拼写，一般使用Metaphone。encode为nil时关闭
+func (filter *Filter) SetPhonetic(encode PhoneticEncoder) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if encode == nil {
+		filter.phonetic = nil
+		return
+	}
+	filter.phonetic = &phoneticIndex{encode: encode}
+}
+
+// FindPhonetic 在默认过滤器中查找读音命中
+func FindPhonetic(text string) []PhoneticMatch {
+	return Default().FindPhonetic(text)
+}
+
+// FindPhonetic 找出text中与词典中的词读音相同、拼写不同的英文单词。
+// 单词按连续的ASCII字母切分，不区分大小写；拼写与词典中的词相同的单词
+// 由普通匹配负责，这里不再报告。读音命中的误报率高于精确命中，
+// 置信度为PhoneticConfidence。没有调用过SetPhonetic时返回nil
+func (filter *Filter) FindPhonetic(text string) []PhoneticMatch {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.findPhonetic(text)
+}
+
+// findPhonetic FindPhonetic的实现，调用方需持有读锁
+func (filter *Filter) findPhonetic(text string) []PhoneticMatch {
+	index := filter.phonetic
+	if index == nil {
+		return nil
+	}
+	text, err := filter.input(text)
+	if err != nil {
+		return nil
+	}
+	codes := index.lookup(filter)
+
+	var matches []PhoneticMatch
+	for _, token := range asciiWords(text) {
+		lower := strings.ToLower(token.Word)
+		code := index.encode(lower)
+		if code == "" {
+			continue
+		}
+		if words := codes[code]; len(words) > 0 && !containsString(words, lower) {
+			matches = append(matches, PhoneticMatch{
+				Match:      Match{Word: words[0], Start: token.Start, End: token.End},
+				Text:       token.Word,
+				Confidence: PhoneticConfidence,
+			})
+		}
+	}
+	ptrs := make([]*Match, len(matches))
+	for i := range matches {
+		ptrs[i] = &matches[i].Match
+	}
+	fillOffsets(text, ptrs)
+	return matches
+}
+
+// lookup 返回与当前词典版本一致的索引，调用方需持有读锁
+func (index *phoneticIndex) lookup(filter *Filter) map[string][]string {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if index.codes != nil && index.version == filter.version {
+		return index.codes
+	}
+	index.codes = make(map[string][]string)
+	filter.matcher().Walk(func(word string) bool {
+		if !isASCIIWord(word) {
+			return true
+		}
+		lower := strings.ToLower(word)
+		if code := index.encode(lower); code != "" {
+			index.codes[code] = append(index.codes[code], lower)
+		}
+		return true
+	})
+	index.version = filter.version
+	return index.codes
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isASCIIWord(word string) bool {
+	if word == "" {
+		return false
+	}
+	for i := 0; i < len(word); i++ {
+		if !isASCIILetter(word[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// asciiWords 切分出text中的连续ASCII字母，位置为rune下标
+func asciiWords(text string) []Match {
+	var (
+		words []Match
+		start = -1
+		begin = 0
+		pos   = 0
+	)
+	for i, r := range text {
+		if r < 0x80 && isASCIILetter(byte(r)) {
+			if start < 0 {
+				start, begin = pos, i
+			}
+		} else if start >= 0 {
+			words = append(words, Match{Word: text[begin:i], Start: start, End: pos})
+			start = -1
+		}
+		pos++
+	}
+	if start >= 0 {
+		words = append(words, Match{Word: text[begin:], Start: start, End: pos})
+	}
+	return words
+}
+
+// Soundex 返回word的Soundex编码，如"Robert"为"R163"，不含字母时返回空字符串。
+// Soundex保留首字母，因此无法归并"phuck"和"fuck"，一般应使用Metaphone
+func Soundex(word string) string {
+	const codes = "01230120022455012623010202"
+
+	var (
+		out  []byte
+		last byte
+	)
+	for i := 0; i < len(word) && len(out) < 4; i++ {
+		c := word[i] | 0x20
+		if c < 'a' || c > 'z' {
+			continue
+		}
+		code := codes[c-'a']
+		if out == nil {
+			out = append(out, c-'a'+'A')
+			last = code
+			continue
+		}
+		if code != '0' && code != last {
+			out = append(out, code)
+		}
+		// h和w不隔断相同的编码，元音隔断
+		if c != 'h' && c != 'w' {
+			last = code
+		}
+	}
+	if out == nil {
+		return ""
+	}
+	for len(out) < 4 {
+		out = append(out, '0')
+	}
+	return string(out)
+}
+
+// Metaphone 返回word的Metaphone编码，如"phuck"和"fuck"均为"FK"，
+// "biatch"和"bitch"均为"BX"。非字母字符被忽略，'0'表示th的发音
+func Metaphone(word string) string {
+	w := make([]byte, 0, len(word))
+	for i := 0; i < len(word); i++ {
+		if c := word[i] | 0x20; c >= 'a' && c <= 'z' {
+			w = append(w, c)
+		}
+	}
+	if len(w) == 0 {
+		return ""
+	}
+
+	switch {
+	case hasPrefix(w, "kn"), hasPrefix(w, "gn"), hasPrefix(w, "pn"), hasPrefix(w, "ae"), hasPrefix(w, "wr"):
+		w = w[1:]
+	case w[0] == 'x':
+		w[0] = 's'
+	case hasPrefix(w, "wh"):
+		w = append([]byte{'w'}, w[2:]...)
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	vowel := func(c byte) bool {
+		return c == 'a' || c == 'e' || c == 'i' || c == 'o' || c == 'u'
+	}
+	frontVowel := func(c byte) bool {
+		return c == 'e' || c == 'i' || c == 'y'
+	}
+
+	var out []byte
+	for i, c := range w {
+		// 除c外，相邻的重复字母只编码一次
+		if c != 'c' && c == at(i-1) {
+			continue
+		}
+		switch c {
+		case 'a', 'e', 'i', 'o', 'u':
+			if i == 0 {
+				out = append(out, c-'a'+'A')
+			}
+		case 'b':
+			if !(at(i-1) == 'm' && i == len(w)-1) {
+				out = append(out, 'B')
+			}
+		case 'c':
+			switch {
+			case at(i+1) == 'i' && at(i+2) == 'a', at(i+1) == 'h' && at(i-1) != 's':
+				out = append(out, 'X')
+			case frontVowel(at(i + 1)):
+				if at(i-1) != 's' {
+					out = append(out, 'S')
+				}
+			default:
+				out = append(out, 'K')
+			}
+		case 'd':
+			if at(i+1) == 'g' && frontVowel(at(i+2)) {
+				out = append(out, 'J')
+			} else {
+				out = append(out, 'T')
+			}
+		case 'g':
+			switch {
+			case at(i+1) == 'h' && i+2 < len(w) && !vowel(at(i+2)):
+			case at(i+1) == 'n' && (i+2 == len(w) || string(w[i+1:]) == "ned"):
+			case frontVowel(at(i+1)) && at(i-1) != 'g':
+				out = append(out, 'J')
+			default:
+				out = append(out, 'K')
+			}
+		case 'h':
+			if i > 0 && strings.IndexByte("csptg", at(i-1)) >= 0 || vowel(at(i-1)) && !vowel(at(i+1)) {
+				break
+			}
+			out = append(out, 'H')
+		case 'k':
+			if at(i-1) != 'c' {
+				out = append(out, 'K')
+			}
+		case 'p':
+			if at(i+1) == 'h' {
+				out = append(out, 'F')
+			} else {
+				out = append(out, 'P')
+			}
+		case 'q':
+			out = append(out, 'K')
+		case 's':
+			if at(i+1) == 'h' || at(i+1) == 'i' && (at(i+2) == 'o' || at(i+2) == 'a') {
+				out = append(out, 'X')
+			} else {
+				out = append(out, 'S')
+			}
+		case 't':
+			switch {
+			case at(i+1) == 'i' && (at(i+2) == 'o' || at(i+2) == 'a'):
+				out = append(out, 'X')
+			case at(i+1) == 'h':
+				out = append(out, '0')
+			case at(i+1) == 'c' && at(i+2) == 'h':
+			default:
+				out = append(out, 'T')
+			}
+		case 'v':
+			out = append(out, 'F')
+		case 'w', 'y':
+			if vowel(at(i + 1)) {
+				out = append(out, c-'a'+'A')
+			}
+		case 'x':
+			out = append(out, 'K', 'S')
+		case 'z':
+			out = append(out, 'S')
+		default:
+			// f j l m n r
+			out = append(out, c-'a'+'A')
+		}
+	}
+	return string(out)
+}
+
+func hasPrefix(w []byte, prefix string) bool {
+	return len(w) >= len(prefix) && string(w[:len(prefix)]) == prefix
+}
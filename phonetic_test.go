@@ -0,0 +1,69 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetaphone(t *testing.T) {
+	cases := map[string]string{
+		"fuck":    "FK",
+		"phuck":   "FK",
+		"bitch":   "BX",
+		"biatch":  "BX",
+		"Knight":  "NT",
+		"thumb":   "0M",
+		"science": "SNS",
+		"":        "",
+	}
+	for word, expect := range cases {
+		if got := Metaphone(word); got != expect {
+			t.Errorf("metaphone %q, got %s, expect %s", word, got, expect)
+		}
+	}
+}
+
+func TestSoundex(t *testing.T) {
+	cases := map[string]string{
+		"Robert":   "R163",
+		"Rupert":   "R163",
+		"Ashcraft": "A261",
+		"Tymczak":  "T522",
+		"Pfister":  "P236",
+		"a":        "A000",
+		"123":      "",
+	}
+	for word, expect := range cases {
+		if got := Soundex(word); got != expect {
+			t.Errorf("soundex %q, got %s, expect %s", word, got, expect)
+		}
+	}
+}
+
+func TestFindPhonetic(t *testing.T) {
+	filter := New()
+	filter.AddWord("fuck", "bitch", "傻逼")
+
+	if matches := filter.FindPhonetic("phuck"); matches != nil {
+		t.Errorf("find phonetic before enabled, got %v", matches)
+	}
+
+	filter.SetPhonetic(Metaphone)
+	expect := []PhoneticMatch{
+		{Match{Word: "fuck", Start: 2, End: 7, ByteStart: 6, ByteEnd: 11, UTF16Start: 2, UTF16End: 7}, "Phuck", PhoneticConfidence},
+		{Match{Word: "bitch", Start: 13, End: 19, ByteStart: 17, ByteEnd: 23, UTF16Start: 13, UTF16End: 19}, "biatch", PhoneticConfidence},
+	}
+	if got := filter.FindPhonetic("哈，Phuck you, biatch! fuck"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("find phonetic, got %v, expect %v", got, expect)
+	}
+
+	// 词典修改后重建索引
+	filter.AddWord("shit")
+	if got := filter.FindPhonetic("shyt"); len(got) != 1 || got[0].Word != "shit" {
+		t.Errorf("find phonetic after add, got %v", got)
+	}
+	filter.SetPhonetic(nil)
+	if got := filter.FindPhonetic("shyt"); got != nil {
+		t.Errorf("find phonetic after disabled, got %v", got)
+	}
+}
@@ -0,0 +1,262 @@
+package sensitive
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Hit 流水线中的一次命中，位置为原文中的rune下标
+type Hit struct {
+	Match
+	Category string
+}
+
+// Matcher 流水线中的匹配器，在归一化后的文本上查找命中，
+// 返回的位置为该文本中的rune下标
+type Matcher interface {
+	Hits(text string) []Hit
+}
+
+// Hits 实现Matcher，按匹配策略返回互不重叠的命中及其分类
+func (filter *Filter) Hits(text string) []Hit {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	var hits []Hit
+	for _, m := range filter.matches(text) {
+		hits = append(hits, Hit{Match: m, Category: filter.meta[m.Word].category})
+	}
+	return hits
+}
+
+// RegexpMatcher 用正则表达式查找命中的匹配器
+type RegexpMatcher struct {
+	re       regex
+	category string
+}
+
+// NewRegexpMatcher 返回匹配pattern的匹配器，命中归入category分类
+func NewRegexpMatcher(category, pattern string) (*RegexpMatcher, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &RegexpMatcher{re: re, category: category}, nil
+}
+
+// Hits 返回正则的全部命中
+func (rm *RegexpMatcher) Hits(text string) []Hit {
+	var hits []Hit
+	for _, m := range runeMatches(text, rm.re.FindAllStringIndex(text, -1)) {
+		hits = append(hits, Hit{Match: m, Category: rm.category})
+	}
+	return hits
+}
+
+// runeMatches 将字节区间转为rune区间的命中
+func runeMatches(text string, locs [][]int) []Match {
+	var (
+		matches []Match
+		offset  = 0
+		runes   = 0
+	)
+	for _, loc := range locs {
+		if loc[0] == loc[1] {
+			continue
+		}
+		runes += utf8.RuneCountInString(text[offset:loc[0]])
+		start := runes
+		runes += utf8.RuneCountInString(text[loc[0]:loc[1]])
+		offset = loc[1]
+		matches = append(matches, Match{Word: text[loc[0]:loc[1]], Start: start, End: runes})
+	}
+	return matches
+}
+
+// Result 流水线的处理结果，Hits中的位置和词语均对应原文
+type Result struct {
+	Text string
+	Hits []Hit
+	// Reject 是否有命中的分类动作为ActionReject，整段文本应被拒绝
+	Reject bool
+}
+
+// Pipeline 由归一化器、匹配器和分类动作组成的处理流程，
+// 构建后只读，可以并发使用
+type Pipeline struct {
+	normalizers   []Normalizer
+	matchers      []Matcher
+	actions       map[string]Action
+	defaultAction Action
+	onLog         func(Hit)
+}
+
+// Run 对text执行整个流程：归一化 → 各匹配器查找命中 → 还原到原文位置并
+// 从左到右取互不重叠的命中(同一起点取最长) → 按分类动作改写原文
+func (p *Pipeline) Run(text string) Result {
+	var (
+		original          = []rune(text)
+		runes, start, end = normalizeSpans(text, p.normalizers)
+		normalized        = string(runes)
+		all               []Hit
+	)
+	for _, m := range p.matchers {
+		for _, hit := range m.Hits(normalized) {
+			hit.Match = restore(hit.Match, original, start, end)
+			all = append(all, hit)
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Start != all[j].Start {
+			return all[i].Start < all[j].Start
+		}
+		return all[i].End > all[j].End
+	})
+	ptrs := make([]*Match, len(all))
+	for i := range all {
+		ptrs[i] = &all[i].Match
+	}
+	fillOffsets(text, ptrs)
+
+	var (
+		result = make([]rune, 0, len(original))
+		hits   []Hit
+		reject bool
+		left   = 0
+	)
+	for _, hit := range all {
+		if hit.Start < left {
+			continue
+		}
+
+		action, ok := p.actions[hit.Category]
+		if !ok {
+			action = p.defaultAction
+		}
+		if action.Kind == ActionReject {
+			reject = true
+			action = p.defaultAction
+		}
+		if action.Kind != ActionAllow {
+			hits = append(hits, hit)
+		}
+		result = append(result, original[left:hit.Start]...)
+		left = hit.End
+		switch action.Kind {
+		case ActionReplace:
+			for i := hit.Start; i < hit.End; i++ {
+				result = append(result, action.Rune)
+			}
+		case ActionReplaceString:
+			result = append(result, []rune(action.String)...)
+		case ActionBlock:
+		case ActionLog:
+			result = append(result, original[hit.Start:hit.End]...)
+			if p.onLog != nil {
+				p.onLog(hit)
+			}
+		case ActionAllow:
+			result = append(result, original[hit.Start:hit.End]...)
+		}
+	}
+	return Result{Text: string(append(result, original[left:]...)), Hits: hits, Reject: reject}
+}
+
+// PipelineBuilder 逐步构建Pipeline
+type PipelineBuilder struct {
+	pipeline *Pipeline
+	err      error
+}
+
+// NewPipelineBuilder 返回一个空的构建器，默认动作为用'*'替换
+func NewPipelineBuilder() *PipelineBuilder {
+	return &PipelineBuilder{pipeline: &Pipeline{
+		actions:       make(map[string]Action),
+		defaultAction: Action{Kind: ActionReplace, Rune: '*'},
+	}}
+}
+
+// Normalize 追加归一化器，按添加顺序执行
+func (b *PipelineBuilder) Normalize(normalizers ...Normalizer) *PipelineBuilder {
+	b.pipeline.normalizers = append(b.pipeline.normalizers, normalizers...)
+	return b
+}
+
+// RemoveNoise 追加删除匹配pattern的片段的归一化器
+func (b *PipelineBuilder) RemoveNoise(pattern string) *PipelineBuilder {
+	rr, err := NewRegexpRemover(pattern)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	return b.Normalize(rr)
+}
+
+// MapEmoji 追加将emoji序列翻译为词语的归一化器，见EmojiMapper
+func (b *PipelineBuilder) MapEmoji(mapping map[string]string) *PipelineBuilder {
+	return b.Normalize(NewEmojiMapper(mapping))
+}
+
+// CollapseRepeats 追加折叠连续重复字符的归一化器，见RepeatCollapser
+func (b *PipelineBuilder) CollapseRepeats(threshold int) *PipelineBuilder {
+	return b.Normalize(NewRepeatCollapser(threshold))
+}
+
+// Match 追加匹配器，*Filter本身就是一个匹配器
+func (b *PipelineBuilder) Match(matchers ...Matcher) *PipelineBuilder {
+	b.pipeline.matchers = append(b.pipeline.matchers, matchers...)
+	return b
+}
+
+// MatchRegexp 追加正则匹配器，命中归入category分类
+func (b *PipelineBuilder) MatchRegexp(category, pattern string) *PipelineBuilder {
+	rm, err := NewRegexpMatcher(category, pattern)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	return b.Match(rm)
+}
+
+// Action 设置某个分类的处理动作
+func (b *PipelineBuilder) Action(category string, action Action) *PipelineBuilder {
+	b.pipeline.actions[category] = action
+	return b
+}
+
+// DefaultAction 设置没有对应分类动作时的处理动作
+func (b *PipelineBuilder) DefaultAction(action Action) *PipelineBuilder {
+	b.pipeline.defaultAction = action
+	return b
+}
+
+// OnLog 设置ActionLog动作的回调
+func (b *PipelineBuilder) OnLog(fn func(Hit)) *PipelineBuilder {
+	b.pipeline.onLog = fn
+	return b
+}
+
+// Build 返回构建好的Pipeline，构建过程中的第一个错误(如正则不合法)在此返回
+func (b *PipelineBuilder) Build() (*Pipeline, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	p := b.pipeline
+	b.pipeline = &Pipeline{
+		normalizers:   append([]Normalizer(nil), p.normalizers...),
+		matchers:      append([]Matcher(nil), p.matchers...),
+		actions:       make(map[string]Action, len(p.actions)),
+		defaultAction: p.defaultAction,
+		onLog:         p.onLog,
+	}
+	for category, action := range p.actions {
+		b.pipeline.actions[category] = action
+	}
+	return p, nil
+}
+
+func (b *PipelineBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
@@ -0,0 +1,51 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	filter := New()
+	filter.AddWordWithCategory("abuse", "fuck", "傻逼")
+
+	p, err := NewPipelineBuilder().
+		RemoveNoise(`[\s\-_.]+`).
+		Normalize(WidthFolder, CaseFolder).
+		Match(filter).
+		MatchRegexp("contact", `1[3-9]\d{9}`).
+		Action("contact", Action{Kind: ActionReplaceString, String: "[号码]"}).
+		DefaultAction(Action{Kind: ActionReplace, Rune: '#'}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := p.Run("Ｆ-U c K，傻 逼，电话138 0013 8000")
+	if expect := "#######，###，电话[号码]"; res.Text != expect {
+		t.Errorf("pipeline text, got %s, expect %s", res.Text, expect)
+	}
+	expectHits := []Hit{
+		{Match{Word: "Ｆ-U c K", Start: 0, End: 7, ByteStart: 0, ByteEnd: 9, UTF16Start: 0, UTF16End: 7}, "abuse"},
+		{Match{Word: "傻 逼", Start: 8, End: 11, ByteStart: 12, ByteEnd: 19, UTF16Start: 8, UTF16End: 11}, "abuse"},
+		{Match{Word: "138 0013 8000", Start: 14, End: 27, ByteStart: 28, ByteEnd: 41, UTF16Start: 14, UTF16End: 27}, "contact"},
+	}
+	if !reflect.DeepEqual(res.Hits, expectHits) {
+		t.Errorf("pipeline hits, got %v, expect %v", res.Hits, expectHits)
+	}
+	if res.Reject {
+		t.Errorf("pipeline should not reject")
+	}
+
+	reject, err := NewPipelineBuilder().Match(filter).Action("abuse", Action{Kind: ActionReject}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := reject.Run("你是傻逼"); !res.Reject || res.Text != "你是**" {
+		t.Errorf("pipeline reject, got %+v", res)
+	}
+
+	if _, err := NewPipelineBuilder().MatchRegexp("bad", "(").Build(); err == nil {
+		t.Errorf("build with invalid pattern should fail")
+	}
+}
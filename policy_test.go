@@ -0,0 +1,37 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchPolicy(t *testing.T) {
+	filter := New()
+	filter.AddWord("色", "色情", "色情网站")
+
+	testcases := []struct {
+		Policy        MatchPolicy
+		Text          string
+		ExpectReplace string
+		ExpectFilter  string
+		ExpectFindAll []string
+	}{
+		{MatchLongest, "这是色情网站吗", "这是****吗", "这是吗", []string{"色情网站"}},
+		{MatchShortest, "这是色情网站吗", "这是*情网站吗", "这是情网站吗", []string{"色"}},
+		{MatchLongest, "色情色", "***", "", []string{"色情", "色"}},
+		{MatchShortest, "色情色", "*情*", "情", []string{"色"}},
+	}
+
+	for _, tc := range testcases {
+		filter.SetMatchPolicy(tc.Policy)
+		if got := filter.Replace(tc.Text, '*'); got != tc.ExpectReplace {
+			t.Errorf("policy %d replace %s, got %s, expect %s", tc.Policy, tc.Text, got, tc.ExpectReplace)
+		}
+		if got := filter.FilterWord(tc.Text); got != tc.ExpectFilter {
+			t.Errorf("policy %d filter %s, got %s, expect %s", tc.Policy, tc.Text, got, tc.ExpectFilter)
+		}
+		if got := filter.FindAll(tc.Text); !reflect.DeepEqual(got, tc.ExpectFindAll) {
+			t.Errorf("policy %d findall %s, got %v, expect %v", tc.Policy, tc.Text, got, tc.ExpectFindAll)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package sensitive
+
+import "sync"
+
+// maxPooledRunes 超过该容量的缓冲区用完后直接丢弃，避免池中长期持有大块内存
+const maxPooledRunes = 64 << 10
+
+var runePool = sync.Pool{
+	New: func() any {
+		buf := make([]rune, 0, 256)
+		return &buf
+	},
+}
+
+// getRunes 从池中取一个缓冲区并将text解码进去，用完需调用putRunes归还
+func getRunes(text string) *[]rune {
+	buf := runePool.Get().(*[]rune)
+	runes := (*buf)[:0]
+	for _, r := range text {
+		runes = append(runes, r)
+	}
+	*buf = runes
+	return buf
+}
+
+// getBuffer 从池中取一个容量至少为size的空缓冲区
+func getBuffer(size int) *[]rune {
+	buf := runePool.Get().(*[]rune)
+	if cap(*buf) < size {
+		*buf = make([]rune, 0, size)
+	}
+	*buf = (*buf)[:0]
+	return buf
+}
+
+func putRunes(buf *[]rune) {
+	if cap(*buf) > maxPooledRunes {
+		return
+	}
+	runePool.Put(buf)
+}
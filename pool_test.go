@@ -0,0 +1,60 @@
+package sensitive
+
+import "testing"
+
+func benchFilter() *Filter {
+	filter := New()
+	filter.LoadWordDict("./dict/dict.txt")
+	return filter
+}
+
+const benchText = "这篇文章真的好垃圾啊，fuck，我为长者续一秒，hello world, 这是一段比较长的正常文本，用来模拟线上的请求内容。"
+
+func BenchmarkReplace(b *testing.B) {
+	filter := benchFilter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		filter.Replace(benchText, '*')
+	}
+}
+
+func BenchmarkFilterWord(b *testing.B) {
+	filter := benchFilter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		filter.FilterWord(benchText)
+	}
+}
+
+func BenchmarkReplaceClean(b *testing.B) {
+	filter := benchFilter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		filter.Replace("hello world, 这是一段比较长的正常文本", '*')
+	}
+}
+
+func TestPooledBuffersConcurrent(t *testing.T) {
+	filter := New()
+	filter.AddWord("东西", "坏人")
+
+	done := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 1000; j++ {
+				if got := filter.Replace("我有东西给坏人", '*'); got != "我有**给**" {
+					t.Errorf("replace got %s", got)
+					return
+				}
+				if got := filter.FilterWord("我有东西给坏人"); got != "我有给" {
+					t.Errorf("filter got %s", got)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		<-done
+	}
+}
@@ -0,0 +1,46 @@
+package sensitive
+
+// prefilter 记录所有词首字符的位图。文本中没有任何字符命中位图时，
+// 可以确定不含敏感词，无需转换为[]rune并遍历Trie。
+// BMP以外的字符折叠进同一位图，只会产生误判为可能命中，不会漏判
+type prefilter struct {
+	bits [1 << 16 / 64]uint64
+}
+
+func prefilterIndex(r rune) uint32 {
+	u := uint32(r)
+	return (u ^ u>>16) & 0xFFFF
+}
+
+func (p *prefilter) add(r rune) {
+	i := prefilterIndex(r)
+	p.bits[i/64] |= 1 << (i % 64)
+}
+
+func (p *prefilter) addWord(word string) {
+	for _, r := range word {
+		p.add(r)
+		return
+	}
+}
+
+// mayMatch 判断text中是否可能含有敏感词
+func (p *prefilter) mayMatch(text string) bool {
+	for _, r := range text {
+		i := prefilterIndex(r)
+		if p.bits[i/64]&(1<<(i%64)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// firstRunes 返回所有词的首字符
+func (tree *Trie) firstRunes() []rune {
+	return sortedKeys(tree.Root.Children)
+}
+
+// firstRunes 返回所有词的首字符
+func (da *DoubleArray) firstRunes() []rune {
+	return da.children(0)
+}
@@ -0,0 +1,31 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrefilter(t *testing.T) {
+	filter := New()
+	filter.EnablePrefilter(true)
+	filter.AddWord("垃圾", "😀笑")
+
+	if got := filter.Replace("这篇文章真的好垃圾", '*'); got != "这篇文章真的好**" {
+		t.Errorf("replace, got %s, expect 这篇文章真的好**", got)
+	}
+	if got := filter.Replace("这篇文章真的好", '*'); got != "这篇文章真的好" {
+		t.Errorf("replace clean text, got %s", got)
+	}
+	if got := filter.FindAll("哈哈😀笑"); !reflect.DeepEqual(got, []string{"😀笑"}) {
+		t.Errorf("findall, got %v, expect [😀笑]", got)
+	}
+
+	filter.Compile()
+	filter.EnablePrefilter(true)
+	if pass, first := filter.Validate("真垃圾"); pass || first != "垃圾" {
+		t.Errorf("validate after compile, got %v %s", pass, first)
+	}
+	if pass, _ := filter.Validate("干净的文本"); !pass {
+		t.Errorf("validate clean text after compile, got %v", pass)
+	}
+}
@@ -0,0 +1,72 @@
+package sensitive
+
+// SetPriority 设置词语的优先级
+func SetPriority(priority int, words ...string) {
+	Default().SetPriority(priority, words...)
+}
+
+// SetPriority 设置词语的优先级，默认为0。同一段文字上命中的多个词相互重叠时，
+// 与更高优先级的命中重叠的词被丢弃，由优先级最高的词决定处理动作；
+// 优先级相同时仍按匹配策略从左到右选取。例如品牌词"苹果手机"设为较高优先级
+// 并归入ActionAllow的分类，即可避免其中的泛化词被处理。删除词语时优先级一并清除
+func (filter *Filter) SetPriority(priority int, words ...string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+
+	for _, word := range words {
+		filter.setMeta(word, func(meta *wordMeta) { meta.priority = priority })
+	}
+	if priority != 0 {
+		filter.usePriority = true
+	}
+}
+
+// Priority 返回词语的优先级
+func Priority(word string) int {
+	return Default().Priority(word)
+}
+
+// Priority 返回词语的优先级，未设置时为0
+func (filter *Filter) Priority(word string) int {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.meta[word].priority
+}
+
+// dropLowerPriority 丢弃与更高优先级命中重叠的命中，matches按起点升序排列，
+// 调用方需持有锁
+func (filter *Filter) dropLowerPriority(matches []Match) []Match {
+	var kept []Match
+	for i, m := range matches {
+		priority := filter.meta[m.Word].priority
+		dominated := false
+		for j, other := range matches {
+			if other.Start >= m.End {
+				break
+			}
+			if j == i || other.End <= m.Start {
+				continue
+			}
+			if filter.meta[other.Word].priority > priority {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// dropAllowed 丢弃分类动作为ActionAllow的命中，调用方需持有锁
+func (filter *Filter) dropAllowed(matches []Match) []Match {
+	kept := matches[:0]
+	for _, m := range matches {
+		if filter.actions[filter.meta[m.Word].category].Kind != ActionAllow {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
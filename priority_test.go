@@ -0,0 +1,52 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPriority(t *testing.T) {
+	filter := New()
+	filter.AddWordWithCategory("brand", "苹果手机")
+	filter.AddWord("手机", "苹果")
+	filter.SetCategoryAction("brand", Action{Kind: ActionAllow})
+
+	// 未设置优先级时按最长匹配，白名单词被放行但其中的泛化词仍会被找到
+	if got := filter.FindAll("买苹果手机"); !reflect.DeepEqual(got, []string{"苹果", "手机"}) {
+		t.Errorf("findall without priority, got %v", got)
+	}
+
+	filter.SetPriority(10, "苹果手机")
+	if filter.Priority("苹果手机") != 10 || filter.Priority("手机") != 0 {
+		t.Errorf("priority not recorded")
+	}
+	if got := filter.Replace("买苹果手机和苹果", '*'); got != "买苹果手机和**" {
+		t.Errorf("replace, got %s", got)
+	}
+	if got := filter.FindAll("买苹果手机"); got != nil {
+		t.Errorf("findall, got %v", got)
+	}
+	if ok, _ := filter.Validate("买苹果手机"); !ok {
+		t.Errorf("validate should pass")
+	}
+
+	// 优先级高的词决定动作
+	filter.SetPriority(20, "手机")
+	if got := filter.Replace("买苹果手机", '*'); got != "买苹果**" {
+		t.Errorf("higher priority generic term, got %s", got)
+	}
+}
+
+func TestPipelineAllow(t *testing.T) {
+	filter := New()
+	filter.AddWordWithCategory("brand", "苹果手机")
+	filter.AddWord("手机")
+
+	p, err := NewPipelineBuilder().Match(filter).Action("brand", Action{Kind: ActionAllow}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := p.Run("苹果手机和手机"); result.Text != "苹果手机和**" || len(result.Hits) != 1 {
+		t.Errorf("got %+v", result)
+	}
+}
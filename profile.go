@@ -0,0 +1,115 @@
+package sensitive
+
+import "errors"
+
+// ErrUnknownProfile 没有以该名称设置过配置方案
+var ErrUnknownProfile = errors.New("sensitive: unknown profile")
+
+// Profile 一个产品场景(如用户名、评论、搜索)的查询配置方案，包括检查的分类、
+// 等级下限、匹配策略、噪音处理和命中后的处理动作。同一个过滤器可以为不同
+// 场景设置严格程度不同的方案，查询时按名称选用
+type Profile struct {
+	Options
+	// Action ApplyProfile对命中的处理动作，设置了分类动作的词仍按分类动作处理。
+	// 零值为替换为'*'
+	Action Action
+}
+
+// SetProfile 为默认过滤器设置名为name的配置方案
+func SetProfile(name string, profile Profile) {
+	Default().SetProfile(name, profile)
+}
+
+// SetProfile 设置名为name的配置方案，已存在时覆盖
+func (filter *Filter) SetProfile(name string, profile Profile) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if filter.profiles == nil {
+		filter.profiles = make(map[string]Profile)
+	}
+	profile.Categories = append([]string(nil), profile.Categories...)
+	filter.profiles[name] = profile
+}
+
+// DelProfile 删除默认过滤器名为name的配置方案
+func DelProfile(name string) {
+	Default().DelProfile(name)
+}
+
+// DelProfile 删除名为name的配置方案
+func (filter *Filter) DelProfile(name string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	delete(filter.profiles, name)
+}
+
+// FindInProfile 按默认过滤器的配置方案检测敏感词
+func FindInProfile(name, text string) (bool, string, error) {
+	return Default().FindInProfile(name, text)
+}
+
+// FindInProfile 按名为name的配置方案检测敏感词，方案不存在时返回ErrUnknownProfile
+func (filter *Filter) FindInProfile(name, text string) (bool, string, error) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	profile, ok := filter.profiles[name]
+	if !ok {
+		return false, "", ErrUnknownProfile
+	}
+	opts := profile.Options
+	opts.MaxMatches = 1
+	matches := filter.optMatches(text, opts)
+	filter.count(text, len(matches) > 0)
+	if len(matches) == 0 {
+		return false, "", nil
+	}
+	return true, matches[0].Word, nil
+}
+
+// FindAllProfile 按默认过滤器的配置方案找到所有匹配词
+func FindAllProfile(name, text string) ([]string, error) {
+	return Default().FindAllProfile(name, text)
+}
+
+// FindAllProfile 按名为name的配置方案找到所有匹配词，结果去重，
+// 方案不存在时返回ErrUnknownProfile
+func (filter *Filter) FindAllProfile(name, text string) ([]string, error) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	profile, ok := filter.profiles[name]
+	if !ok {
+		return nil, ErrUnknownProfile
+	}
+	words := uniqueWords(filter.optMatches(text, profile.Options))
+	filter.count(text, len(words) > 0)
+	return words, nil
+}
+
+// ApplyProfile 按默认过滤器的配置方案处理敏感词
+func ApplyProfile(name, text string) (string, error) {
+	return Default().ApplyProfile(name, text)
+}
+
+// ApplyProfile 按名为name的配置方案找出命中并按方案的动作处理，
+// 方案不存在时返回ErrUnknownProfile
+func (filter *Filter) ApplyProfile(name, text string) (string, error) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	profile, ok := filter.profiles[name]
+	if !ok {
+		return "", ErrUnknownProfile
+	}
+	action := profile.Action
+	if action.Kind == ActionReplace && action.Rune == 0 {
+		action.Rune = '*'
+	}
+
+	input, err := filter.input(text)
+	if err != nil {
+		return "", err
+	}
+	matches := filter.optMatches(input, profile.Options)
+	result, _ := filter.rewriteMatches(input, matches, action)
+	filter.count(text, len(matches) > 0)
+	return result, nil
+}
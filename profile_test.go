@@ -0,0 +1,42 @@
+package sensitive
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	filter := New()
+	if err := filter.Load(strings.NewReader("笨蛋|abuse|1\n傻逼|abuse|3\n加微信|ad\n")); err != nil {
+		t.Fatal(err)
+	}
+	filter.SetProfile("username", Profile{Options: Options{CaseFold: true}})
+	filter.SetProfile("comment", Profile{
+		Options: Options{Categories: []string{"abuse"}, MinLevel: 2},
+		Action:  Action{Kind: ActionReplaceString, String: "[和谐]"},
+	})
+
+	text := "笨蛋 傻 逼 加微信"
+	if got, err := filter.FindAllProfile("username", text); err != nil || !reflect.DeepEqual(got, []string{"笨蛋", "傻逼", "加微信"}) {
+		t.Errorf("find all with username profile, got %v %v", got, err)
+	}
+	if got, err := filter.FindAllProfile("comment", text); err != nil || !reflect.DeepEqual(got, []string{"傻逼"}) {
+		t.Errorf("find all with comment profile, got %v %v", got, err)
+	}
+	if got, err := filter.ApplyProfile("comment", text); err != nil || got != "笨蛋 [和谐] 加微信" {
+		t.Errorf("apply comment profile, got %q %v", got, err)
+	}
+	if got, err := filter.ApplyProfile("username", "笨蛋"); err != nil || got != "**" {
+		t.Errorf("apply username profile, got %q %v", got, err)
+	}
+	if found, word, err := filter.FindInProfile("comment", "笨蛋"); err != nil || found {
+		t.Errorf("find in with comment profile, got %v %q %v", found, word, err)
+	}
+
+	filter.DelProfile("comment")
+	if _, err := filter.FindAllProfile("comment", text); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("deleted profile, got %v, expect %v", err, ErrUnknownProfile)
+	}
+}
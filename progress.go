@@ -0,0 +1,114 @@
+package sensitive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// loadChunkLines 分批加载时每次加锁处理的行数
+const loadChunkLines = 10000
+
+// LoadProgress 加载进度
+type LoadProgress struct {
+	// Lines 已处理的行数
+	Lines int
+	// Bytes 已读取的字节数
+	Bytes int64
+	// Total 词典的总字节数，未知时为0
+	Total int64
+}
+
+// LoadWithProgress 分批加载纯文本词典并报告进度
+func LoadWithProgress(rd io.Reader, fn func(LoadProgress)) error {
+	return Default().LoadWithProgress(rd, fn)
+}
+
+// LoadWithProgress 按行加载纯文本词典，格式同LoadWordDict，但不识别JSON、CSV、
+// gzip等格式和编码。读取和切分不持有锁，每loadChunkLines行加一次写锁写入并
+// 提交，加载超大词典时查询不会被长时间阻塞，查询在加载期间能看到已写入的部分。
+// 每批写入后调用fn报告进度，fn可以为nil
+func (filter *Filter) LoadWithProgress(rd io.Reader, fn func(LoadProgress)) error {
+	return filter.loadChunked("", rd, 0, fn)
+}
+
+// LoadWordDictWithProgress 分批加载词典文件并报告进度
+func LoadWordDictWithProgress(path string, fn func(LoadProgress)) error {
+	return Default().LoadWordDictWithProgress(path, fn)
+}
+
+// LoadWordDictWithProgress 同LoadWithProgress，从文件加载，进度中带有文件大小
+func (filter *Filter) LoadWordDictWithProgress(path string, fn func(LoadProgress)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return filter.recordLoad(path, err)
+	}
+	defer f.Close()
+
+	var total int64
+	if info, err := f.Stat(); err == nil {
+		total = info.Size()
+	}
+	return filter.loadChunked(path, f, total, fn)
+}
+
+// countingReader 统计已读取的字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+func (filter *Filter) loadChunked(source string, rd io.Reader, total int64, fn func(LoadProgress)) error {
+	filter.beginLoad(source)
+
+	filter.mu.RLock()
+	splitter := &lineSplitter{max: filter.maxLineLength}
+	filter.mu.RUnlock()
+
+	var (
+		counter  = &countingReader{r: rd}
+		scanner  = bufio.NewScanner(counter)
+		progress = LoadProgress{Total: total}
+		lines    = make([]string, 0, loadChunkLines)
+	)
+	scanner.Buffer(make([]byte, 0, 4096), splitter.max+2)
+	scanner.Split(splitter.split)
+
+	flush := func() {
+		filter.mu.Lock()
+		for _, line := range lines {
+			filter.addPlainLine(line, "", nil)
+		}
+		filter.commit()
+		filter.mu.Unlock()
+
+		progress.Lines += len(lines)
+		progress.Bytes = counter.n
+		lines = lines[:0]
+		if fn != nil {
+			fn(progress)
+		}
+	}
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) == loadChunkLines {
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return filter.recordLoad(source, err)
+	}
+	flush()
+
+	if splitter.oversized > 0 {
+		return filter.recordLoad(source, fmt.Errorf("%w: %d lines skipped", ErrLineTooLong, splitter.oversized))
+	}
+	return filter.recordLoad(source, nil)
+}
@@ -0,0 +1,57 @@
+package sensitive
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadWithProgress(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < loadChunkLines*2+5; i++ {
+		fmt.Fprintf(&b, "词%d\n", i)
+	}
+	b.WriteString("!词0\n")
+	content := b.String()
+
+	path := filepath.Join(t.TempDir(), "dict.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		filter   = New()
+		progress []LoadProgress
+	)
+	err := filter.LoadWordDictWithProgress(path, func(p LoadProgress) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("progress reports, got %d", len(progress))
+	}
+	last := progress[len(progress)-1]
+	if expect := (LoadProgress{Lines: loadChunkLines*2 + 6, Bytes: int64(len(content)), Total: int64(len(content))}); last != expect {
+		t.Errorf("last progress, got %+v, expect %+v", last, expect)
+	}
+	if progress[0].Lines != loadChunkLines {
+		t.Errorf("first progress, got %+v", progress[0])
+	}
+	if filter.HasWord("词0") || !filter.HasWord("词20004") {
+		t.Errorf("words after loading with progress")
+	}
+	if state := filter.LoadState(); state.Status != LoadReady || state.Source != path {
+		t.Errorf("load state, got %+v", state)
+	}
+
+	filter.SetMaxLineLength(4)
+	if err := filter.LoadWithProgress(strings.NewReader("短\n很长很长的行\n"), nil); !errors.Is(err, ErrLineTooLong) {
+		t.Errorf("load with oversized line, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package sensitive
+
+// Redundancy 一个可删除的冗余词，Word中已包含更短的词By
+type Redundancy struct {
+	Word string `json:"word"`
+	By   string `json:"by"`
+}
+
+// Redundant 返回默认过滤器词典中的冗余词
+func Redundant() []Redundancy {
+	return Default().Redundant()
+}
+
+// Redundant 按字典序返回词典中被更短的词覆盖的词，例如已有"色情"时的"色情网站"。
+// 检测时包含较短词的文本必然命中较短的词，这些词通常可以删除以精简词典。
+// By为Word中最先出现的较短词
+func (filter *Filter) Redundant() []Redundancy {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	m := filter.matcher()
+	var redundant []Redundancy
+	m.Walk(func(word string) bool {
+		length := len([]rune(word))
+		for _, match := range m.matchIndex(word, MatchShortest, true) {
+			if match.End-match.Start < length {
+				redundant = append(redundant, Redundancy{Word: word, By: match.Word})
+				break
+			}
+		}
+		return true
+	})
+	return redundant
+}
@@ -0,0 +1,28 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedundant(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情", "色情网站", "黄色色情", "网站", "赌博")
+
+	want := []Redundancy{
+		{Word: "色情网站", By: "色情"},
+		{Word: "黄色色情", By: "色情"},
+	}
+	if got := filter.Redundant(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	filter.Compile()
+	if got := filter.Redundant(); !reflect.DeepEqual(got, want) {
+		t.Errorf("compiled, got %v, want %v", got, want)
+	}
+
+	if got := New().Redundant(); got != nil {
+		t.Errorf("empty, got %v", got)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build tinygo || sensitive_noregexp
+
+package sensitive
+
+// 在TinyGo或指定sensitive_noregexp标签编译时不引入regexp包。去噪只能使用
+// UpdateNoiseRunes的字符集合和SetNoiseFunc，接受正则的方法返回ErrUnsupported，
+// FindLinks和SetSkipLinks不识别链接
+
+// regex 不支持正则时的占位类型，不会有非nil的值
+type regex = *noRegexp
+
+type noRegexp struct{}
+
+// compileRegex 总是返回ErrUnsupported
+func compileRegex(expr string) (regex, error) {
+	return nil, ErrUnsupported
+}
+
+func (*noRegexp) MatchString(s string) bool { return false }
+
+func (*noRegexp) FindAllStringIndex(s string, n int) [][]int { return nil }
+
+func (*noRegexp) ReplaceAllString(src, repl string) string { return src }
+
+func (*noRegexp) ReplaceAll(src, repl []byte) []byte { return src }
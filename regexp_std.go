@@ -0,0 +1,13 @@
+//go:build !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import "regexp"
+
+// regex 噪音、例外规则、跨行分隔等使用的正则表达式
+type regex = *regexp.Regexp
+
+// compileRegex 编译正则表达式
+func compileRegex(expr string) (regex, error) {
+	return regexp.Compile(expr)
+}
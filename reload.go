@@ -0,0 +1,56 @@
+package sensitive
+
+import "strings"
+
+// Reload 从sources重新构建默认过滤器的词典
+func Reload(sources ...SourceConfig) error {
+	return Default().Reload(sources...)
+}
+
+// Reload 在一个新的词典中加载全部sources，都成功后才一次性替换当前的
+// 全部词语及其分类等信息；任一来源失败时保留原有词典并返回错误。
+// 替换期间查询不会看到只加载了一部分的词典
+func (filter *Filter) Reload(sources ...SourceConfig) error {
+	filter.mu.RLock()
+	tmp := New()
+	tmp.retry = filter.retry
+	tmp.maxLineLength = filter.maxLineLength
+	filter.mu.RUnlock()
+
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.name())
+	}
+	name := strings.Join(names, ",")
+
+	filter.beginLoad(name)
+	for _, source := range sources {
+		if err := source.load(tmp); err != nil {
+			return filter.recordLoad(name, err)
+		}
+	}
+	filter.swap(tmp)
+	return filter.recordLoad(name, nil)
+}
+
+// swap 用tmp的词典替换当前词典，保持当前的编译状态
+func (filter *Filter) swap(tmp *Filter) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	switch {
+	case filter.store != nil:
+		filter.resetStore(tmp.trie.Walk)
+	case filter.da != nil:
+		filter.setCompiled(NewDoubleArray(tmp.trie))
+	default:
+		filter.trie = tmp.trie
+	}
+	filter.meta = tmp.meta
+	filter.usePriority = tmp.usePriority
+	filter.deadlines = nil
+	filter.schedules = nil
+	filter.rebuildPrefilter()
+	filter.pending = Delta{Reset: true}
+	filter.commit()
+}
@@ -0,0 +1,37 @@
+//go:build !js && !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReloadOnSignal 收到SIGHUP时用Reload从sources重新构建filter的词典，
+// 返回停止监听的函数。加载失败时保留原有词典，错误可通过LoadState查看
+func ReloadOnSignal(filter *Filter, sources ...SourceConfig) (stop func()) {
+	return reloadOn(filter, sources, syscall.SIGHUP)
+}
+
+func reloadOn(filter *Filter, sources []SourceConfig, sig os.Signal) (stop func()) {
+	var (
+		signals = make(chan os.Signal, 1)
+		done    = make(chan struct{})
+	)
+	signal.Notify(signals, sig)
+	go func() {
+		for {
+			select {
+			case <-signals:
+				filter.Reload(sources...)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,37 @@
+package sensitive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	words := filepath.Join(dir, "words.txt")
+	ads := filepath.Join(dir, "ads.txt")
+	os.WriteFile(words, []byte("色情\n"), 0o644)
+	os.WriteFile(ads, []byte("加微信\n"), 0o644)
+	sources := []SourceConfig{{File: words}, {File: ads, Category: "ad"}}
+
+	filter := New()
+	filter.AddWord("旧词")
+	filter.Compile()
+	if err := filter.Reload(sources...); err != nil {
+		t.Fatal(err)
+	}
+	if filter.HasWord("旧词") || !filter.HasWord("色情") || filter.Category("加微信") != "ad" {
+		t.Errorf("got %v", filter.Words())
+	}
+	if filter.da == nil {
+		t.Errorf("compiled filter should stay compiled")
+	}
+
+	os.Remove(ads)
+	if err := filter.Reload(sources...); err == nil {
+		t.Fatal("expect error for missing source")
+	}
+	if !filter.HasWord("加微信") || filter.LoadState().Status != LoadFailed {
+		t.Errorf("failed reload should keep the dictionary, got %v %+v", filter.Words(), filter.LoadState())
+	}
+}
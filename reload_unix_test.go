@@ -0,0 +1,29 @@
+//go:build unix && !tinygo && !sensitive_noregexp
+
+package sensitive
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	os.WriteFile(path, []byte("色情\n"), 0o644)
+
+	filter := New()
+	stop := reloadOn(filter, []SourceConfig{{File: path}}, syscall.SIGUSR1)
+	defer stop()
+
+	syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	deadline := time.Now().Add(time.Second)
+	for !filter.HasWord("色情") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !filter.HasWord("色情") {
+		t.Errorf("not reloaded, got %v", filter.Words())
+	}
+}
@@ -0,0 +1,63 @@
+package sensitive
+
+// RepeatCollapser 将连续重复的同一字符折叠为一个的归一化器，用于发现
+// "fuuuuuck"、"傻傻傻逼"之类的变体。命中还原到原文时覆盖被折叠的整段重复
+type RepeatCollapser struct {
+	threshold int
+}
+
+// NewRepeatCollapser 返回折叠连续出现至少threshold次的字符的归一化器，
+// threshold小于2时按2处理。threshold为2时"book"会被折叠为"bok"，
+// 词典中含有叠字时应取更大的值
+func NewRepeatCollapser(threshold int) *RepeatCollapser {
+	if threshold < 2 {
+		threshold = 2
+	}
+	return &RepeatCollapser{threshold: threshold}
+}
+
+// Normalize 折叠重复字符，折叠后的字符对应重复的第一个字符
+func (rc *RepeatCollapser) Normalize(runes []rune, index []int) ([]rune, []int) {
+	var (
+		out    = make([]rune, 0, len(runes))
+		outIdx = make([]int, 0, len(index))
+	)
+	rc.collapse(runes, func(from, to int) {
+		out = append(out, runes[from])
+		outIdx = append(outIdx, index[from])
+	})
+	return out, outIdx
+}
+
+// normalizeSpan 折叠后的字符对应整段重复
+func (rc *RepeatCollapser) normalizeSpan(runes []rune, start, end []int) ([]rune, []int, []int) {
+	var (
+		out      = make([]rune, 0, len(runes))
+		outStart = make([]int, 0, len(start))
+		outEnd   = make([]int, 0, len(end))
+	)
+	rc.collapse(runes, func(from, to int) {
+		out = append(out, runes[from])
+		outStart = append(outStart, start[from])
+		outEnd = append(outEnd, end[to-1])
+	})
+	return out, outStart, outEnd
+}
+
+// collapse 对折叠后的每个字符调用emit，runes[from:to]为它对应的字符
+func (rc *RepeatCollapser) collapse(runes []rune, emit func(from, to int)) {
+	for i := 0; i < len(runes); {
+		j := i + 1
+		for j < len(runes) && runes[j] == runes[i] {
+			j++
+		}
+		if j-i >= rc.threshold {
+			emit(i, j)
+		} else {
+			for k := i; k < j; k++ {
+				emit(k, k+1)
+			}
+		}
+		i = j
+	}
+}
@@ -0,0 +1,47 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepeatCollapser(t *testing.T) {
+	rc := NewRepeatCollapser(3)
+	runes, index := rc.Normalize([]rune("fuuuuck book"), []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11})
+	if string(runes) != "fuck book" {
+		t.Errorf("normalize, got %s", string(runes))
+	}
+	if expect := []int{0, 1, 5, 6, 7, 8, 9, 10, 11}; !reflect.DeepEqual(index, expect) {
+		t.Errorf("normalize index, got %v, expect %v", index, expect)
+	}
+
+	if runes, _ := NewRepeatCollapser(0).Normalize([]rune("book"), []int{0, 1, 2, 3}); string(runes) != "bok" {
+		t.Errorf("normalize with threshold 2, got %s", string(runes))
+	}
+}
+
+func TestPipelineCollapseRepeats(t *testing.T) {
+	filter := New()
+	filter.AddWord("fuck", "傻逼")
+
+	p, err := NewPipelineBuilder().
+		RemoveNoise(`\s+`).
+		CollapseRepeats(2).
+		Match(filter).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := p.Run("fuuuuck! 傻傻傻 逼逼逼，好")
+	if expect := "*******! *******，好"; res.Text != expect {
+		t.Errorf("run text, got %s, expect %s", res.Text, expect)
+	}
+	expectHits := []Hit{
+		{Match: Match{Word: "fuuuuck", Start: 0, End: 7, ByteStart: 0, ByteEnd: 7, UTF16Start: 0, UTF16End: 7}},
+		{Match: Match{Word: "傻傻傻 逼逼逼", Start: 9, End: 16, ByteStart: 9, ByteEnd: 28, UTF16Start: 9, UTF16End: 16}},
+	}
+	if !reflect.DeepEqual(res.Hits, expectHits) {
+		t.Errorf("run hits, got %v, expect %v", res.Hits, expectHits)
+	}
+}
@@ -0,0 +1,107 @@
+package sensitive
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// LoadReport 加载词典时的统计，用于发现词典中的问题
+type LoadReport struct {
+	// Lines 读取的行数，结构化格式为条目数
+	Lines int
+	// Added 新加入的词数
+	Added int
+	// Trimmed 去除了首尾空白后加入的行数
+	Trimmed int
+	// Duplicates 与词典中已有的词或之前的行重复而跳过的行数
+	Duplicates int
+	// Empty 空行数
+	Empty int
+	// Comments 以#开头的注释行数
+	Comments int
+	// Removed 以!开头、删除词语的行数
+	Removed int
+	// Malformed 指令格式错误(如等级不是整数)而跳过的行数
+	Malformed int
+	// InvalidUTF8 不是合法UTF-8而被拒绝的行数
+	InvalidUTF8 int
+	// Oversized 超过SetMaxLineLength而被跳过的行数
+	Oversized int
+}
+
+// LoadWithReport 加载词典并返回统计
+func LoadWithReport(rd io.Reader) (*LoadReport, error) {
+	return Default().LoadWithReport(rd)
+}
+
+// LoadWithReport 同Load，但会整理每一行：去除首尾空白，跳过空行、
+// 重复的词和不合法的UTF-8，并返回各项统计
+func (filter *Filter) LoadWithReport(rd io.Reader) (*LoadReport, error) {
+	report := &LoadReport{}
+	err := filter.load("", bufio.NewReader(rd), EncodingAuto, report)
+	return report, err
+}
+
+// LoadWordDictWithReport 加载敏感词字典并返回统计
+func LoadWordDictWithReport(path string) (*LoadReport, error) {
+	return Default().LoadWordDictWithReport(path)
+}
+
+// LoadWordDictWithReport 同LoadWithReport，从文件加载
+func (filter *Filter) LoadWordDictWithReport(path string) (*LoadReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, filter.recordLoad(path, err)
+	}
+	defer f.Close()
+
+	report := &LoadReport{}
+	err = filter.load(path, bufio.NewReader(f), EncodingAuto, report)
+	return report, err
+}
+
+// addLine 加入词典中的一行，report为nil时去除首尾空白后加入，否则整理后加入并统计，
+// 调用方需持有写锁
+func (filter *Filter) addLine(line, category string, report *LoadReport) {
+	word := strings.TrimSpace(line)
+	if report != nil {
+		var ok bool
+		if word, ok = report.check(filter, line); !ok {
+			return
+		}
+		report.Added++
+	} else if word == "" {
+		return
+	}
+
+	filter.addWords(word)
+	if category != "" {
+		filter.setMeta(word, func(meta *wordMeta) { meta.category = category })
+	}
+}
+
+// check 整理一行并统计，返回要加入的词，调用方需持有锁
+func (report *LoadReport) check(filter *Filter, line string) (string, bool) {
+	report.Lines++
+	if !utf8.ValidString(line) {
+		report.InvalidUTF8++
+		return "", false
+	}
+
+	word := strings.TrimSpace(line)
+	switch {
+	case word == "":
+		report.Empty++
+		return "", false
+	case filter.matcher().Has(word):
+		report.Duplicates++
+		return "", false
+	}
+	if word != line {
+		report.Trimmed++
+	}
+	return word, true
+}
@@ -0,0 +1,38 @@
+package sensitive
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadWithReport(t *testing.T) {
+	filter := New()
+	filter.AddWord("已有")
+
+	content := "垃圾\n  东西 \n\n# 注释\n垃圾\n已有\n\xff\xfe\n坏人"
+	report, err := filter.LoadWithReport(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := LoadReport{Lines: 8, Added: 3, Trimmed: 1, Duplicates: 2, Empty: 1, Comments: 1, InvalidUTF8: 1}
+	if *report != expect {
+		t.Errorf("got %+v, expect %+v", *report, expect)
+	}
+	if !filter.trie.Has("东西") || filter.trie.Has("  东西 ") {
+		t.Errorf("line should be trimmed")
+	}
+}
+
+func TestLoadWithReportJSON(t *testing.T) {
+	filter := New()
+	report, err := filter.LoadWithReport(strings.NewReader(`[{"word": " 加微信", "category": "ad"}, {"word": ""}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Lines != 2 || report.Added != 1 || report.Empty != 1 {
+		t.Errorf("got %+v", *report)
+	}
+	if filter.Category("加微信") != "ad" {
+		t.Errorf("category not set")
+	}
+}
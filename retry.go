@@ -0,0 +1,160 @@
+package sensitive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy 加载网络词典失败时的重试策略
+type RetryPolicy struct {
+	// MaxRetries 最多重试次数，0表示不重试
+	MaxRetries int
+	// InitialBackoff 第一次重试前的等待时间
+	InitialBackoff time.Duration
+	// MaxBackoff 等待时间的上限，0表示不限制
+	MaxBackoff time.Duration
+	// Multiplier 每次重试后等待时间的倍数，<1时按2处理
+	Multiplier float64
+	// Jitter 等待时间的随机浮动比例，取值[0,1]，如0.2表示在±20%内浮动
+	Jitter float64
+}
+
+// DefaultRetryPolicy 常用的重试策略：最多重试3次，等待0.5s、1s、2s左右
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// ErrTimeout 请求或读取网络词典超时，可用errors.Is判断
+var ErrTimeout = errors.New("sensitive: timeout")
+
+// FetchError 请求或读取网络词典失败，Err为底层的错误，超时时errors.Is(err, ErrTimeout)为true
+type FetchError struct {
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("sensitive: fetch %s: %v", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使errors.Is(err, ErrTimeout)可以判断超时
+func (e *FetchError) Is(target error) bool {
+	return target == ErrTimeout && isTimeout(e.Err)
+}
+
+// fetchBody 将读取响应内容时的错误包装为*FetchError
+type fetchBody struct {
+	io.ReadCloser
+	url string
+}
+
+func (body fetchBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = &FetchError{URL: body.url, Err: err}
+	}
+	return n, err
+}
+
+// IsRetryable用到的HTTP状态码，与net/http中的同名常量相同
+const (
+	statusRequestTimeout  = 408
+	statusTooManyRequests = 429
+	statusNotImplemented  = 501
+)
+
+// HTTPError 请求网络词典时服务端返回了错误状态码
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("sensitive: %s: %d %s", e.URL, e.StatusCode, statusText(e.StatusCode))
+}
+
+// Retryable 408、429及5xx(501除外)视为临时错误，可以重试
+func (e *HTTPError) Retryable() bool {
+	switch e.StatusCode {
+	case statusRequestTimeout, statusTooManyRequests:
+		return true
+	case statusNotImplemented:
+		return false
+	}
+	return e.StatusCode >= 500
+}
+
+// IsRetryable 判断加载网络词典的错误是否为临时错误：可重试的HTTP状态码、
+// 超时和连接失败等网络错误返回true，其余(如404)返回false
+func IsRetryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Retryable()
+	}
+	return isNetError(err) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// SetRetryPolicy 设置加载网络词典的重试策略
+func SetRetryPolicy(policy RetryPolicy) {
+	Default().SetRetryPolicy(policy)
+}
+
+// SetRetryPolicy 设置LoadNetWordDict等加载网络词典时的重试策略，
+// 只有IsRetryable的错误才会重试
+func (filter *Filter) SetRetryPolicy(policy RetryPolicy) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.retry = policy
+}
+
+// fetch 按重试策略请求url
+func (filter *Filter) fetch(url string, timeout time.Duration) (io.ReadCloser, error) {
+	filter.mu.RLock()
+	policy := filter.retry
+	filter.mu.RUnlock()
+
+	filter.beginLoad(url)
+	backoff := policy.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		body, err := fetch(url, timeout)
+		if err == nil {
+			return body, nil
+		}
+		if attempt >= policy.MaxRetries || !IsRetryable(err) {
+			return nil, filter.recordLoad(url, err)
+		}
+
+		time.Sleep(policy.jitter(backoff))
+		backoff = policy.next(backoff)
+	}
+}
+
+func (policy RetryPolicy) next(backoff time.Duration) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	backoff = time.Duration(float64(backoff) * multiplier)
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	return backoff
+}
+
+func (policy RetryPolicy) jitter(backoff time.Duration) time.Duration {
+	if policy.Jitter <= 0 {
+		return backoff
+	}
+	return time.Duration(float64(backoff) * (1 + policy.Jitter*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,90 @@
+package sensitive
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadNetWordDictRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("垃圾\n"))
+	}))
+	defer srv.Close()
+
+	filter := New()
+	err := filter.LoadNetWordDict(srv.URL)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway || !IsRetryable(err) {
+		t.Fatalf("without retry, got %v", err)
+	}
+
+	filter.SetRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, Jitter: 0.5})
+	if err := filter.LoadNetWordDict(srv.URL); err != nil {
+		t.Fatalf("with retry, got %v", err)
+	}
+	if calls != 3 || !filter.trie.Has("垃圾") {
+		t.Errorf("calls %d, expect 3", calls)
+	}
+}
+
+func TestLoadNetWordDictPermanentError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	filter := New()
+	filter.SetRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond})
+	err := filter.LoadNetWordDict(srv.URL)
+	if err == nil || IsRetryable(err) {
+		t.Errorf("404 should be permanent, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("permanent error should not retry, calls %d", calls)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second}
+	if got := policy.next(time.Second); got != 2*time.Second {
+		t.Errorf("next, got %v", got)
+	}
+	if got := policy.next(2 * time.Second); got != 3*time.Second {
+		t.Errorf("capped, got %v", got)
+	}
+}
+
+func TestLoadNetWordDictTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	err := New().LoadNetWordDictTimeout(srv.URL, 10*time.Millisecond)
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.URL != srv.URL {
+		t.Fatalf("expect *FetchError, got %v", err)
+	}
+	if !errors.Is(err, ErrTimeout) || !IsRetryable(err) {
+		t.Errorf("expect retryable timeout, got %v", err)
+	}
+
+	close(release)
+	srv.Close()
+	err = New().LoadNetWordDictTimeout(srv.URL, time.Second)
+	if !errors.As(err, &fetchErr) || errors.Is(err, ErrTimeout) {
+		t.Errorf("expect non-timeout *FetchError, got %v", err)
+	}
+}
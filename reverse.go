@@ -0,0 +1,61 @@
+package sensitive
+
+import "sort"
+
+// SetMatchReversed 设置默认过滤器是否检查倒写的词
+func SetMatchReversed(enable bool) {
+	Default().SetMatchReversed(enable)
+}
+
+// SetMatchReversed 开启后同时在逐字倒序的文本上匹配，发现"词感敏"之类
+// 倒着写的词。倒写的命中报告词典中的词，位置为原文中倒写的那一段
+func (filter *Filter) SetMatchReversed(enable bool) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.reverse = enable
+}
+
+// reversedMatches 在倒序的text上匹配，返回还原到原文位置的全部命中，
+// 按起点、终点升序排列，调用方需持有锁
+func (filter *Filter) reversedMatches(text string) []Match {
+	runes := []rune(text)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	found := filter.matcher().matchIndex(string(runes), MatchDefault, true)
+
+	matches := make([]Match, len(found))
+	for i, m := range found {
+		matches[i] = Match{Word: m.Word, Start: len(runes) - m.End, End: len(runes) - m.Start}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End < matches[j].End
+	})
+	return matches
+}
+
+// mergeMatches 合并两组按起点、终点升序排列的命中，区间相同的只保留a中的
+func mergeMatches(a, b []Match) []Match {
+	merged := make([]Match, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i].Start == b[j].Start && a[i].End == b[j].End:
+			merged = append(merged, a[i])
+			i++
+			j++
+		case a[i].Start < b[j].Start || a[i].Start == b[j].Start && a[i].End < b[j].End:
+			merged = append(merged, a[i])
+			i++
+		default:
+			merged = append(merged, b[j])
+			j++
+		}
+	}
+	merged = append(merged, a[i:]...)
+	return append(merged, b[j:]...)
+}
@@ -0,0 +1,30 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchReversed(t *testing.T) {
+	filter := New()
+	filter.AddWord("敏感词", "上海", "abba")
+
+	if found, _ := filter.FindIn("这是词感敏"); found {
+		t.Errorf("find reversed before enabled")
+	}
+
+	filter.SetMatchReversed(true)
+	if found, word := filter.FindIn("这是词感敏"); !found || word != "敏感词" {
+		t.Errorf("find reversed, got %v %s", found, word)
+	}
+	if got := filter.Replace("海上有敏感词，词感敏", '*'); got != "**有***，***" {
+		t.Errorf("replace reversed, got %s", got)
+	}
+	expect := []Match{{Word: "上海", Start: 0, End: 2, ByteStart: 0, ByteEnd: 6, UTF16Start: 0, UTF16End: 2}, {Word: "abba", Start: 3, End: 7, ByteStart: 7, ByteEnd: 11, UTF16Start: 3, UTF16End: 7}}
+	if got := filter.FindAllWithIndex("海上 abba"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("find all reversed, got %v, expect %v", got, expect)
+	}
+	if got := filter.Freeze().FilterWord("词感敏!"); got != "!" {
+		t.Errorf("frozen filter reversed, got %s", got)
+	}
+}
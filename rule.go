@@ -0,0 +1,154 @@
+package sensitive
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrInvalidRule 共现规则不合法
+var ErrInvalidRule = errors.New("sensitive: invalid rule")
+
+// Rule 共现规则：Terms中的每个词都出现在不超过Distance个字符的范围内时命中。
+// Terms只用于该规则，不需要也不会加入词典，单独出现时不算命中
+type Rule struct {
+	Name     string
+	Terms    []string
+	Distance int
+}
+
+// RuleMatch 一次规则命中，Start和End为覆盖所有词的rune区间，左闭右开
+type RuleMatch struct {
+	Rule    string
+	Start   int
+	End     int
+	Matches []Match
+}
+
+// AddRule 添加共现规则
+func AddRule(rule Rule) error {
+	return Default().AddRule(rule)
+}
+
+// AddRule 添加共现规则，同名规则会被替换。
+// 规则名为空、没有词、含空词或Distance不大于0时返回ErrInvalidRule
+func (filter *Filter) AddRule(rule Rule) error {
+	if rule.Name == "" || len(rule.Terms) == 0 || rule.Distance <= 0 {
+		return ErrInvalidRule
+	}
+	for _, term := range rule.Terms {
+		if term == "" {
+			return ErrInvalidRule
+		}
+	}
+
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if filter.rules == nil {
+		filter.rules = make(map[string]Rule)
+	}
+	filter.rules[rule.Name] = rule
+	filter.rebuildRuleTrie()
+	return nil
+}
+
+// DelRule 删除共现规则
+func DelRule(name string) {
+	Default().DelRule(name)
+}
+
+// DelRule 删除共现规则
+func (filter *Filter) DelRule(name string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	delete(filter.rules, name)
+	filter.rebuildRuleTrie()
+}
+
+// rebuildRuleTrie 用全部规则的词重建规则Trie，调用方需持有写锁
+func (filter *Filter) rebuildRuleTrie() {
+	filter.ruleTrie = NewTrie()
+	for _, rule := range filter.rules {
+		filter.ruleTrie.Add(rule.Terms...)
+	}
+}
+
+// FindRules 找出文本中命中的共现规则
+func FindRules(text string) []RuleMatch {
+	return Default().FindRules(text)
+}
+
+// FindRules 找出文本中命中的共现规则，同一规则的多次命中互不重叠，
+// 结果按规则名、位置排序
+func (filter *Filter) FindRules(text string) []RuleMatch {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	if len(filter.rules) == 0 {
+		return nil
+	}
+	text, err := filter.input(text)
+	if err != nil {
+		return nil
+	}
+
+	terms := filter.ruleTrie.FindAllWithIndex(text, MatchDefault, true)
+	var results []RuleMatch
+	for _, name := range sortedRuleNames(filter.rules) {
+		results = append(results, matchRule(filter.rules[name], terms)...)
+	}
+	return results
+}
+
+// matchRule 在按位置排列的词出现中查找规则的命中
+func matchRule(rule Rule, terms []Match) []RuleMatch {
+	var (
+		results []RuleMatch
+		last    = make(map[string]Match, len(rule.Terms))
+		wanted  = make(map[string]struct{}, len(rule.Terms))
+	)
+	for _, term := range rule.Terms {
+		wanted[term] = struct{}{}
+	}
+
+	for _, m := range terms {
+		if _, ok := wanted[m.Word]; !ok {
+			continue
+		}
+		last[m.Word] = m
+
+		var (
+			matches = make([]Match, 0, len(rule.Terms))
+			start   = m.Start
+			end     = m.End
+		)
+		for _, term := range rule.Terms {
+			occ, ok := last[term]
+			if !ok || m.End-occ.Start > rule.Distance {
+				matches = nil
+				break
+			}
+			matches = append(matches, occ)
+			if occ.Start < start {
+				start = occ.Start
+			}
+			if occ.End > end {
+				end = occ.End
+			}
+		}
+		if matches == nil || end-start > rule.Distance {
+			continue
+		}
+
+		results = append(results, RuleMatch{Rule: rule.Name, Start: start, End: end, Matches: matches})
+		last = make(map[string]Match, len(rule.Terms))
+	}
+	return results
+}
+
+func sortedRuleNames(rules map[string]Rule) []string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
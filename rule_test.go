@@ -0,0 +1,32 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindRules(t *testing.T) {
+	filter := New()
+	if err := filter.AddRule(Rule{Name: "contact", Terms: []string{"加", "微信"}, Distance: 6}); err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.AddRule(Rule{Name: "empty"}); err != ErrInvalidRule {
+		t.Errorf("add invalid rule, got %v, expect %v", err, ErrInvalidRule)
+	}
+
+	expect := []RuleMatch{{Rule: "contact", Start: 2, End: 7, Matches: []Match{{Word: "加", Start: 2, End: 3, ByteStart: 6, ByteEnd: 9, UTF16Start: 2, UTF16End: 3}, {Word: "微信", Start: 5, End: 7, ByteStart: 15, ByteEnd: 21, UTF16Start: 5, UTF16End: 7}}}}
+	if got := filter.FindRules("快来加我的微信号"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("find rules, got %v, expect %v", got, expect)
+	}
+	if got := filter.FindRules("加油，明天再说，今天没空，有事发微信"); got != nil {
+		t.Errorf("find rules with terms too far apart, got %v", got)
+	}
+	if found, _ := filter.FindIn("加"); found {
+		t.Errorf("rule terms should not become dictionary words")
+	}
+
+	filter.DelRule("contact")
+	if got := filter.FindRules("快来加我的微信号"); got != nil {
+		t.Errorf("find rules after delete, got %v", got)
+	}
+}
@@ -0,0 +1,153 @@
+package sensitive
+
+import (
+	"bufio"
+	"io"
+)
+
+// runeWindow 从io.RuneReader读取时每次处理的字符数
+const runeWindow = 4096
+
+// FindInRuneReader 检测rd中的敏感词
+func FindInRuneReader(rd io.RuneReader) (bool, string, error) {
+	return Default().FindInRuneReader(rd)
+}
+
+// FindInRuneReader 逐段读取rd并检测敏感词，找到第一个后即停止读取。
+// 不会把全部内容读入内存，适合bufio.Reader或自定义的解码器。
+// 不去除噪音，命中按匹配策略选取(MatchDefault按最长匹配处理)
+func (filter *Filter) FindInRuneReader(rd io.RuneReader) (found bool, word string, err error) {
+	err = filter.scanRuneReader(rd, func(segment []rune, matches []Match) bool {
+		if len(matches) > 0 {
+			found, word = true, matches[0].Word
+			return false
+		}
+		return true
+	})
+	return found, word, err
+}
+
+// FilterRuneReader 删除rd中的敏感词并写入w
+func FilterRuneReader(w io.Writer, rd io.RuneReader) error {
+	return Default().FilterRuneReader(w, rd)
+}
+
+// FilterRuneReader 逐段读取rd，删除其中的敏感词后写入w，匹配规则同FindInRuneReader
+func (filter *Filter) FilterRuneReader(w io.Writer, rd io.RuneReader) error {
+	return filter.rewriteRuneReader(w, rd, -1)
+}
+
+// ReplaceRuneReader 替换rd中的敏感词并写入w
+func ReplaceRuneReader(w io.Writer, rd io.RuneReader, repl rune) error {
+	return Default().ReplaceRuneReader(w, rd, repl)
+}
+
+// ReplaceRuneReader 同FilterRuneReader，将敏感词逐字符替换为repl
+func (filter *Filter) ReplaceRuneReader(w io.Writer, rd io.RuneReader, repl rune) error {
+	return filter.rewriteRuneReader(w, rd, repl)
+}
+
+// rewriteRuneReader repl为-1时删除敏感词
+func (filter *Filter) rewriteRuneReader(w io.Writer, rd io.RuneReader, repl rune) error {
+	var (
+		bw       = bufio.NewWriter(w)
+		writeErr error
+	)
+	err := filter.scanRuneReader(rd, func(segment []rune, matches []Match) bool {
+		left := 0
+		for _, m := range matches {
+			writeRunes(bw, segment[left:m.Start])
+			if repl >= 0 {
+				for i := m.Start; i < m.End; i++ {
+					bw.WriteRune(repl)
+				}
+			}
+			left = m.End
+		}
+		_, writeErr = writeRunes(bw, segment[left:])
+		return writeErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	return bw.Flush()
+}
+
+func writeRunes(w *bufio.Writer, runes []rune) (int, error) {
+	for _, r := range runes {
+		if _, err := w.WriteRune(r); err != nil {
+			return 0, err
+		}
+	}
+	return len(runes), nil
+}
+
+// scanRuneReader 每读取runeWindow个字符处理一次，末尾可能与之后的内容组成敏感词的
+// 最长词长度减一个字符留到下一次处理。fn依次收到互不重叠的各段及段内的命中，
+// 返回false时停止读取
+func (filter *Filter) scanRuneReader(rd io.RuneReader, fn func(segment []rune, matches []Match) bool) error {
+	var (
+		keep = filter.LongestWord() - 1
+		buf  = make([]rune, 0, runeWindow+keep)
+	)
+	for {
+		r, _, err := rd.ReadRune()
+		if err == nil {
+			buf = append(buf, r)
+			if len(buf) < runeWindow+keep {
+				continue
+			}
+		} else if err != io.EOF {
+			return err
+		}
+		final := err == io.EOF
+
+		cut := len(buf)
+		if !final {
+			cut -= keep
+		}
+		if cut > 0 {
+			matches := filter.segmentMatches(buf, cut)
+			n := cut
+			if len(matches) > 0 && matches[len(matches)-1].End > n {
+				n = matches[len(matches)-1].End
+			}
+			if !fn(buf[:n], matches) {
+				return nil
+			}
+			buf = append(buf[:0], buf[n:]...)
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// segmentMatches 返回runes中从cut之前开始的互不重叠的命中
+func (filter *Filter) segmentMatches(runes []rune, cut int) []Match {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	text := string(runes)
+	if filter.skip(text) {
+		return nil
+	}
+	var all []Match
+	if filter.spanMode() {
+		all = filter.matches(text)
+	} else {
+		all = filter.matcher().matchIndex(text, filter.bytesPolicy(), false)
+	}
+
+	matches := all[:0]
+	for _, m := range all {
+		if m.Start >= cut {
+			break
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
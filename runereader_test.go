@@ -0,0 +1,54 @@
+package sensitive
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestRuneReader(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情", "色情网站", "赌博")
+
+	found, word, err := filter.FindInRuneReader(strings.NewReader("你好，色情网站"))
+	if err != nil || !found || word != "色情网站" {
+		t.Errorf("find in, got %v %s %v", found, word, err)
+	}
+	if found, _, _ := filter.FindInRuneReader(strings.NewReader("你好")); found {
+		t.Errorf("find in clean text, got true")
+	}
+
+	var out strings.Builder
+	if err := filter.ReplaceRuneReader(&out, strings.NewReader("看色情网站和赌博"), '*'); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "看****和**" {
+		t.Errorf("replace, got %s", out.String())
+	}
+
+	out.Reset()
+	filter.FilterRuneReader(&out, strings.NewReader("看色情网站和赌博"))
+	if out.String() != "看和" {
+		t.Errorf("filter, got %s", out.String())
+	}
+}
+
+func TestRuneReaderAcrossWindows(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情网站")
+
+	// 敏感词跨过读取窗口的边界
+	for _, pad := range []int{runeWindow - 2, runeWindow, runeWindow + 1, 3*runeWindow - 1} {
+		text := strings.Repeat("好", pad) + "色情网站" + strings.Repeat("好", pad)
+		var out strings.Builder
+		if err := filter.ReplaceRuneReader(&out, bufio.NewReader(strings.NewReader(text)), '*'); err != nil {
+			t.Fatal(err)
+		}
+		if want := strings.Repeat("好", pad) + "****" + strings.Repeat("好", pad); out.String() != want {
+			t.Errorf("pad %d, got %d runes", pad, len([]rune(out.String())))
+		}
+		if found, word, _ := filter.FindInRuneReader(strings.NewReader(text)); !found || word != "色情网站" {
+			t.Errorf("pad %d, find in got %v %s", pad, found, word)
+		}
+	}
+}
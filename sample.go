@@ -0,0 +1,82 @@
+package sensitive
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// sampleContext 抽样片段中命中词前后保留的字符数
+const sampleContext = 20
+
+// Sample 一次被抽中的命中
+type Sample struct {
+	// Words 命中的全部词语，已去重
+	Words []string
+	// Snippet 第一个命中词前后的一段原文，其中的命中词均已替换为*，
+	// 截断处以...标出
+	Snippet string
+}
+
+// SetSampler 设置默认过滤器的命中抽样
+func SetSampler(rate float64, fn func(Sample)) {
+	Default().SetSampler(rate, fn)
+}
+
+// SetSampler 对FindIn、FindAll、Replace等查询中命中了敏感词的调用，
+// 以rate(0到1)的概率调用fn，用于在不记录全部违规内容的前提下积累评估数据。
+// fn为nil或rate<=0时关闭抽样。fn在查询时同步调用，不能再调用filter的方法
+func (filter *Filter) SetSampler(rate float64, fn func(Sample)) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if rate <= 0 {
+		fn = nil
+	}
+	filter.sampler = fn
+	filter.sampleRate = rate
+}
+
+// maybeSample 按概率对命中了敏感词的text抽样，调用方需持有锁
+func (filter *Filter) maybeSample(text string) {
+	if filter.sampler == nil || rand.Float64() >= filter.sampleRate {
+		return
+	}
+
+	matches := filter.findAllWithIndex(text)
+	if len(matches) == 0 {
+		// 去掉噪音之后才命中
+		text = filter.removeNoise(text)
+		matches = filter.findAllWithIndex(text)
+	}
+	if len(matches) == 0 {
+		return
+	}
+	filter.sampler(Sample{Words: uniqueWords(matches), Snippet: snippet(text, matches)})
+}
+
+// snippet 截取第一个命中前后各sampleContext个字符，并遮盖其中的命中
+func snippet(text string, matches []Match) string {
+	runes := []rune(text)
+	start, end := matches[0].Start-sampleContext, matches[0].End+sampleContext
+	if start < 0 {
+		start = 0
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	for _, m := range matches {
+		for i := m.Start; i < m.End; i++ {
+			runes[i] = '*'
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	b.WriteString(string(runes[start:end]))
+	if end < len(runes) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
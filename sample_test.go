@@ -0,0 +1,40 @@
+package sensitive
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSampler(t *testing.T) {
+	filter := New()
+	filter.AddWord("色情", "赌博")
+
+	var samples []Sample
+	filter.SetSampler(1, func(s Sample) { samples = append(samples, s) })
+
+	filter.FindIn("你好")
+	filter.Replace("看色情和赌博", '*')
+	filter.FindIn("色|情")
+	want := []Sample{
+		{Words: []string{"色情", "赌博"}, Snippet: "看**和**"},
+		{Words: []string{"色情"}, Snippet: "**"},
+	}
+	if !reflect.DeepEqual(samples, want) {
+		t.Errorf("got %+v", samples)
+	}
+
+	samples = nil
+	long := strings.Repeat("一", 30) + "色情" + strings.Repeat("二", 30)
+	filter.FindAll(long)
+	if len(samples) != 1 || samples[0].Snippet != "..."+strings.Repeat("一", 20)+"**"+strings.Repeat("二", 20)+"..." {
+		t.Errorf("long text, got %+v", samples)
+	}
+
+	samples = nil
+	filter.SetSampler(0, func(s Sample) { samples = append(samples, s) })
+	filter.FindIn("色情")
+	if samples != nil {
+		t.Errorf("disabled sampler, got %+v", samples)
+	}
+}
@@ -0,0 +1,132 @@
+package sensitive
+
+import "time"
+
+// Schedule 词语的生效时间。From、To限定日期范围，零值表示该端不限；
+// DailyFrom、DailyTo限定每天生效的时段(距当天零点的时长)，两者相等表示全天，
+// DailyFrom大于DailyTo表示跨过零点，如22:00到次日06:00
+type Schedule struct {
+	From      time.Time
+	To        time.Time
+	DailyFrom time.Duration
+	DailyTo   time.Duration
+	// Location 计算每天时段所用的时区，nil表示time.Local
+	Location *time.Location
+}
+
+// Active 判断t时刻是否在生效时间内
+func (s Schedule) Active(t time.Time) bool {
+	if !s.From.IsZero() && t.Before(s.From) {
+		return false
+	}
+	if !s.To.IsZero() && !t.Before(s.To) {
+		return false
+	}
+	if s.DailyFrom == s.DailyTo {
+		return true
+	}
+
+	offset := t.Sub(s.midnight(t))
+	if s.DailyFrom < s.DailyTo {
+		return offset >= s.DailyFrom && offset < s.DailyTo
+	}
+	return offset >= s.DailyFrom || offset < s.DailyTo
+}
+
+// next 返回t之后生效状态可能发生变化的最早时刻，不会再变化时返回零值
+func (s Schedule) next(t time.Time) time.Time {
+	var next time.Time
+	consider := func(c time.Time) {
+		if c.After(t) && (next.IsZero() || c.Before(next)) {
+			next = c
+		}
+	}
+
+	consider(s.From)
+	consider(s.To)
+	if s.DailyFrom != s.DailyTo && (s.To.IsZero() || t.Before(s.To)) {
+		midnight := s.midnight(t)
+		for _, day := range []int{0, 1} {
+			base := midnight.AddDate(0, 0, day)
+			consider(base.Add(s.DailyFrom))
+			consider(base.Add(s.DailyTo))
+		}
+	}
+	return next
+}
+
+func (s Schedule) midnight(t time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// AddWordSchedule 添加只在指定时间内生效的敏感词
+func AddWordSchedule(word string, schedules ...Schedule) {
+	Default().AddWordSchedule(word, schedules...)
+}
+
+// AddWordSchedule 添加只在schedules中任一时间段内生效的敏感词。
+// 过滤器内部的定时器在边界时刻自动启用或停用该词，无需外部调度；
+// 之后用AddWord或DelWord操作同一个词会取消其生效时间设置，词的分类等附加
+// 信息保留。被拦截器拒绝时不做修改
+func (filter *Filter) AddWordSchedule(word string, schedules ...Schedule) {
+	if _, err := filter.intercept(MutationAdd, []string{word}); err != nil {
+		return
+	}
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	delete(filter.deadlines, word)
+	if filter.schedules == nil {
+		filter.schedules = make(map[string][]Schedule)
+	}
+	filter.schedules[word] = schedules
+	filter.applySchedules(time.Now())
+	filter.commit()
+	filter.resetTimer()
+}
+
+// applySchedules 按now启用或停用设置了生效时间的词，调用方需持有写锁
+func (filter *Filter) applySchedules(now time.Time) bool {
+	changed := false
+	for word, schedules := range filter.schedules {
+		active := false
+		for _, s := range schedules {
+			if s.Active(now) {
+				active = true
+				break
+			}
+		}
+
+		if has := filter.matcher().Has(word); active && !has {
+			filter.mutable().Add(word)
+			if filter.prefilter != nil {
+				filter.prefilter.addWord(word)
+			}
+			filter.record([]string{word}, nil)
+			changed = true
+		} else if !active && has {
+			filter.mutable().Del(word)
+			filter.record(nil, []string{word})
+			changed = true
+		}
+	}
+	return changed
+}
+
+// nextTransition 返回所有生效时间中最早的下一个边界时刻
+func (filter *Filter) nextTransition(now time.Time) time.Time {
+	var next time.Time
+	for _, schedules := range filter.schedules {
+		for _, s := range schedules {
+			if c := s.next(now); !c.IsZero() && (next.IsZero() || c.Before(next)) {
+				next = c
+			}
+		}
+	}
+	return next
+}
@@ -0,0 +1,78 @@
+package sensitive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleActive(t *testing.T) {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		Schedule Schedule
+		At       time.Time
+		Expect   bool
+	}{
+		{Schedule{From: day, To: day.AddDate(0, 0, 1)}, day.Add(time.Hour), true},
+		{Schedule{From: day, To: day.AddDate(0, 0, 1)}, day.AddDate(0, 0, 1), false},
+		{Schedule{DailyFrom: 9 * time.Hour, DailyTo: 18 * time.Hour, Location: time.UTC}, day.Add(12 * time.Hour), true},
+		{Schedule{DailyFrom: 9 * time.Hour, DailyTo: 18 * time.Hour, Location: time.UTC}, day.Add(20 * time.Hour), false},
+		{Schedule{DailyFrom: 22 * time.Hour, DailyTo: 6 * time.Hour, Location: time.UTC}, day.Add(time.Hour), true},
+		{Schedule{DailyFrom: 22 * time.Hour, DailyTo: 6 * time.Hour, Location: time.UTC}, day.Add(12 * time.Hour), false},
+	}
+
+	for i, tc := range testcases {
+		if got := tc.Schedule.Active(tc.At); got != tc.Expect {
+			t.Errorf("case %d active at %v, got %v, expect %v", i, tc.At, got, tc.Expect)
+		}
+	}
+}
+
+func TestAddWordSchedule(t *testing.T) {
+	filter := New()
+	now := time.Now()
+	filter.AddWordSchedule("活动", Schedule{From: now.Add(-time.Hour), To: now.Add(30 * time.Millisecond)})
+	filter.AddWordSchedule("未来", Schedule{From: now.Add(time.Hour)})
+
+	if got := filter.Replace("活动未来", '*'); got != "**未来" {
+		t.Errorf("replace during window, got %s, expect **未来", got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if filter.Replace("活动未来", '*') == "活动未来" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("replace after window, got %s, expect 活动未来", filter.Replace("活动未来", '*'))
+}
+
+func TestAddWordScheduleKeepsMeta(t *testing.T) {
+	filter := New()
+	filter.AddWordWithCategory("ad", "活动")
+	filter.SetPriority(5, "活动")
+
+	var deltas []Delta
+	filter.Watch(func(delta Delta) { deltas = append(deltas, delta) })
+	filter.AddWordSchedule("活动", Schedule{From: time.Now().Add(-time.Hour)})
+	if filter.Category("活动") != "ad" || filter.meta["活动"].priority != 5 {
+		t.Errorf("schedule dropped meta, got %+v", filter.meta["活动"])
+	}
+	if len(deltas) != 1 || len(deltas[0].Added)+len(deltas[0].Removed) != 0 {
+		t.Errorf("active word removed and added again, got %+v", deltas)
+	}
+
+	filter.AddWordSchedule("活动", Schedule{From: time.Now().Add(time.Hour)})
+	if filter.Replace("活动", '*') != "活动" || filter.Category("活动") != "ad" {
+		t.Errorf("inactive word, got %s %q", filter.Replace("活动", '*'), filter.Category("活动"))
+	}
+	if last := deltas[len(deltas)-1]; len(last.Removed) != 1 || last.Removed[0] != "活动" {
+		t.Errorf("deactivation not recorded, got %+v", last)
+	}
+
+	// 没有定时的词之后停止定时器
+	filter.DelWord("活动")
+	if filter.timer.Stop() {
+		t.Errorf("timer still running without schedules")
+	}
+}
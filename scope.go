@@ -0,0 +1,92 @@
+package sensitive
+
+// FindAllIn 在默认过滤器中只查找属于categories分类的词
+func FindAllIn(text string, categories ...string) []string {
+	return Default().FindAllIn(text, categories...)
+}
+
+// FindAllIn 同FindAll，只查找属于categories分类的词，未设置分类的词属于
+// 空分类""。同一个过滤器可以按场景(用户名、聊天、评论等)只检查相关的分类，
+// categories为空时同FindAll
+func (filter *Filter) FindAllIn(text string, categories ...string) []string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	if len(categories) == 0 {
+		words := filter.findAll(text)
+		filter.count(text, len(words) > 0)
+		return words
+	}
+
+	var words []string
+	if input, err := filter.input(text); err == nil && !filter.skip(input) {
+		matches := filter.scopedMatches(input, categorySet(categories))
+		if filter.policy != MatchDefault {
+			matches = selectMatches(matches, filter.policy)
+		}
+		words = uniqueWords(matches)
+	}
+	filter.count(text, len(words) > 0)
+	return words
+}
+
+// ReplaceIn 在默认过滤器中只和谐属于categories分类的词
+func ReplaceIn(text string, repl rune, categories ...string) string {
+	return Default().ReplaceIn(text, repl, categories...)
+}
+
+// ReplaceIn 同Replace，只和谐属于categories分类的词，其他分类的词原样保留，
+// categories为空时同Replace
+func (filter *Filter) ReplaceIn(text string, repl rune, categories ...string) string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	if len(categories) == 0 {
+		result := filter.replaceCached(text, repl)
+		filter.count(text, result != text)
+		return result
+	}
+
+	var result string
+	if input, err := filter.input(text); err == nil {
+		result = input
+		if !filter.skip(input) {
+			result, _ = filter.rewriteMatches(input, filter.scopedSelect(input, categorySet(categories)), Action{Kind: ActionReplace, Rune: repl})
+		}
+	}
+	filter.count(text, result != text)
+	return result
+}
+
+// scopedMatches 返回属于categories的全部有效命中，含相互重叠的，调用方需持有锁
+func (filter *Filter) scopedMatches(text string, categories map[string]struct{}) []Match {
+	all := filter.allMatches(text)
+	kept := all[:0]
+	for _, m := range all {
+		if _, ok := categories[filter.meta[m.Word].category]; ok {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// scopedSelect 在属于categories的命中中按匹配策略从左到右选出互不重叠的
+// 命中，设置了跨过噪音匹配时在去噪后的文本上匹配。先按分类筛选再选取，
+// 因此不会因与其他分类的词重叠而漏掉，调用方需持有锁
+func (filter *Filter) scopedSelect(text string, categories map[string]struct{}) []Match {
+	if !filter.throughNoise {
+		return selectMatches(filter.scopedMatches(text, categories), filter.policy)
+	}
+	runes, index := normalize(text, filter.noiseNormalizers())
+	matches := selectMatches(filter.scopedMatches(string(runes), categories), filter.policy)
+	for i, m := range matches {
+		matches[i].Start, matches[i].End = index[m.Start], index[m.End-1]+1
+	}
+	return matches
+}
+
+func categorySet(categories []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(categories))
+	for _, category := range categories {
+		set[category] = struct{}{}
+	}
+	return set
+}
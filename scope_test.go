@@ -0,0 +1,38 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCategoryScopedQueries(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	filter.AddWordWithCategory("ad", "加微信", "微信号")
+	filter.AddWordWithCategory("abuse", "信号差")
+
+	text := "垃圾，加微信号，信号差"
+	if got := filter.FindAllIn(text, "ad"); !reflect.DeepEqual(got, []string{"加微信", "微信号"}) {
+		t.Errorf("find all in ad, got %v", got)
+	}
+	if got := filter.FindAllIn(text, "", "abuse"); !reflect.DeepEqual(got, []string{"垃圾", "信号差"}) {
+		t.Errorf("find all in uncategorized and abuse, got %v", got)
+	}
+	if got := filter.FindAllIn(text); len(got) != 4 {
+		t.Errorf("find all in without categories, got %v", got)
+	}
+
+	// "微信号"与"加微信"重叠，不属于abuse分类时不影响"信号差"
+	if got := filter.ReplaceIn(text, '*', "abuse"); got != "垃圾，加微信号，***" {
+		t.Errorf("replace in abuse, got %s", got)
+	}
+	if got := filter.ReplaceIn("加微信号码", '*', "abuse"); got != "加微信号码" {
+		t.Errorf("replace in abuse without hit, got %s", got)
+	}
+
+	filter.UpdateNoisePattern(`x`)
+	filter.SetMatchThroughNoise(true)
+	if got := filter.ReplaceIn("垃x圾加x微信", '*', "ad"); got != "垃x圾****" {
+		t.Errorf("replace in through noise, got %s", got)
+	}
+}
@@ -0,0 +1,33 @@
+package sensitive
+
+// DefaultSeparators 常被插在字间规避检测的分隔字符：标点、间隔号和各种横线
+const DefaultSeparators = ".,-_~=+/\\|:;'\"`·•・‧∙，。、．－—–―～：；…"
+
+// SetSeparators 设置默认过滤器的分隔字符
+func SetSeparators(chars string) {
+	Default().SetSeparators(chars)
+}
+
+// SetSeparators 设置匹配时忽略的分隔字符，一般为DefaultSeparators。设置后
+// "法-轮-功"、"F.U.C.K"之类在字间插入分隔的写法也能命中，命中区间为原文中
+// 从词首到词尾的整段，Replace等方法处理整段，词两侧的分隔不受影响。
+// 分隔与噪音互不影响，Replace、FilterWord不开启SetMatchThroughNoise也会忽略分隔；
+// 含有分隔字符的词不再能命中。chars为空时取消
+func (filter *Filter) SetSeparators(chars string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.separators = newRuneSet(chars)
+}
+
+// joinNormalizers 返回匹配前删除跨行分隔和分隔字符的归一化器，调用方需持有锁
+func (filter *Filter) joinNormalizers() []Normalizer {
+	var normalizers []Normalizer
+	if filter.lineJoin != nil {
+		normalizers = append(normalizers, &RegexpRemover{re: filter.lineJoin})
+	}
+	if filter.separators != nil {
+		normalizers = append(normalizers, RuneRemover(filter.separators.has))
+	}
+	return normalizers
+}
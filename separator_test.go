@@ -0,0 +1,49 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeparators(t *testing.T) {
+	filter := New()
+	filter.AddWord("法轮功", "fuck")
+
+	text := "说说法-轮-功吧，f.u.c.k."
+	if got := filter.FindAll(text); got != nil {
+		t.Errorf("find all before separators, got %v", got)
+	}
+
+	filter.SetSeparators(DefaultSeparators)
+	if got := filter.FindAll(text); !reflect.DeepEqual(got, []string{"法轮功", "fuck"}) {
+		t.Errorf("find all with separators, got %v", got)
+	}
+	expect := []Match{
+		{Word: "法轮功", Start: 2, End: 7, ByteStart: 6, ByteEnd: 17, UTF16Start: 2, UTF16End: 7},
+		{Word: "fuck", Start: 9, End: 16, ByteStart: 23, ByteEnd: 30, UTF16Start: 9, UTF16End: 16},
+	}
+	if got := filter.FindAllWithIndex(text); !reflect.DeepEqual(got, expect) {
+		t.Errorf("find all with index, got %v, expect %v", got, expect)
+	}
+	// 词两侧的分隔保持原样
+	if got := filter.Replace(text, '*'); got != "说说*****吧，*******." {
+		t.Errorf("replace with separators, got %s", got)
+	}
+	if ok, word := filter.Validate("法·轮·功"); ok || word != "法轮功" {
+		t.Errorf("validate with separators, got %v %s", ok, word)
+	}
+
+	// 与跨行匹配同时使用
+	if err := filter.SetLineJoin(DefaultLineJoin); err != nil {
+		t.Fatal(err)
+	}
+	if got := filter.Replace("法-\n轮—功", '*'); got != "******" {
+		t.Errorf("replace with separators and line join, got %q", got)
+	}
+
+	filter.SetSeparators("")
+	filter.SetLineJoin("")
+	if got := filter.FindAll(text); got != nil {
+		t.Errorf("find all after separators disabled, got %v", got)
+	}
+}
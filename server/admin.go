@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// ErrNoReloader 没有通过SetReloader设置重新加载的方式
+var ErrNoReloader = errors.New("server: reload is not configured")
+
+// ListWordsRequest 列出词语的请求，Limit<=0表示不限制
+type ListWordsRequest struct {
+	Prefix string `json:"prefix,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// ListWordsResponse 按字典序排列的词语及当前词典版本
+type ListWordsResponse struct {
+	Words   []string `json:"words"`
+	Version uint64   `json:"version"`
+}
+
+// ImportRequest 批量导入请求，Dictionary为词典文件的内容，支持Load的全部格式
+type ImportRequest struct {
+	Dictionary string `json:"dictionary"`
+}
+
+// ImportResponse 导入后的词典版本及加载统计
+type ImportResponse struct {
+	Version uint64                `json:"version"`
+	Report  *sensitive.LoadReport `json:"report"`
+}
+
+// VersionRequest 查询词典版本的请求
+type VersionRequest struct{}
+
+// RollbackRequest 回滚到指定版本的请求
+type RollbackRequest struct {
+	Version uint64 `json:"version"`
+}
+
+// ReloadRequest 重新加载词典的请求
+type ReloadRequest struct{}
+
+// SetReloader 设置Reload使用的重新加载方式，例如重新读取配置中的词典来源
+func (s *Service) SetReloader(fn func(ctx context.Context, filter *sensitive.Filter) error) {
+	s.reload = fn
+}
+
+// ListWords 列出词典中以Prefix开头的词
+func (s *Service) ListWords(ctx context.Context, req *ListWordsRequest) (*ListWordsResponse, error) {
+	version := s.filter.Version()
+	words := s.filter.WordsWithPrefix(req.Prefix, req.Limit)
+	if words == nil {
+		words = []string{}
+	}
+	return &ListWordsResponse{Words: words, Version: version}, nil
+}
+
+// ImportWords 批量导入词典，导入前为当前词典生成快照，之后可用Rollback撤销。
+// 词典中要添加和删除的词都先经过过滤器的拦截器，任一被拒绝时不导入
+func (s *Service) ImportWords(ctx context.Context, req *ImportRequest) (*ImportResponse, error) {
+	if err := s.checkImport(req.Dictionary); err != nil {
+		return nil, err
+	}
+	s.filter.Snapshot()
+	report, err := s.filter.LoadWithReport(strings.NewReader(req.Dictionary))
+	if err != nil {
+		return nil, err
+	}
+	return &ImportResponse{Version: s.filter.Version(), Report: report}, nil
+}
+
+// checkImport 在临时过滤器中解析dictionary，用拦截器检查其中添加和删除的词
+func (s *Service) checkImport(dictionary string) error {
+	var (
+		stage   = sensitive.New()
+		removed []string
+	)
+	stage.Watch(func(delta sensitive.Delta) {
+		removed = append(removed, delta.Removed...)
+	})
+	if err := stage.Load(strings.NewReader(dictionary)); err != nil {
+		return err
+	}
+	if err := s.filter.CheckMutation(sensitive.MutationAdd, stage.Words()...); err != nil {
+		return err
+	}
+	return s.filter.CheckMutation(sensitive.MutationDel, removed...)
+}
+
+// GetVersion 返回当前词典版本
+func (s *Service) GetVersion(ctx context.Context, req *VersionRequest) (*WordsResponse, error) {
+	return &WordsResponse{Version: s.filter.Version()}, nil
+}
+
+// Rollback 将词典回滚到之前的版本
+func (s *Service) Rollback(ctx context.Context, req *RollbackRequest) (*WordsResponse, error) {
+	if err := s.filter.Rollback(req.Version); err != nil {
+		return nil, err
+	}
+	return &WordsResponse{Version: s.filter.Version()}, nil
+}
+
+// Reload 使用SetReloader设置的方式重新加载词典，加载前同样会生成快照
+func (s *Service) Reload(ctx context.Context, req *ReloadRequest) (*WordsResponse, error) {
+	if s.reload == nil {
+		return nil, ErrNoReloader
+	}
+	s.filter.Snapshot()
+	if err := s.reload(ctx, s.filter); err != nil {
+		return nil, err
+	}
+	return &WordsResponse{Version: s.filter.Version()}, nil
+}
+
+// Authenticator 判断请求是否有权访问管理接口
+type Authenticator func(r *http.Request) bool
+
+// BearerToken 返回校验"Authorization: Bearer <token>"请求头的Authenticator，
+// token为空时拒绝所有请求
+func BearerToken(token string) Authenticator {
+	return func(r *http.Request) bool {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+	}
+}
+
+// NewAdminHandler 返回管理词典的HTTP/JSON接口，所有请求都需通过auth校验，
+// auth为nil时拒绝所有请求：
+//
+//	POST /v1/admin/words/list    ListWordsRequest -> ListWordsResponse
+//	POST /v1/admin/words/add     WordsRequest     -> WordsResponse
+//	POST /v1/admin/words/delete  WordsRequest     -> WordsResponse
+//	POST /v1/admin/words/import  ImportRequest    -> ImportResponse
+//	POST /v1/admin/version       VersionRequest   -> WordsResponse
+//	POST /v1/admin/rollback      RollbackRequest  -> WordsResponse
+//	POST /v1/admin/reload        ReloadRequest    -> WordsResponse
+func NewAdminHandler(s *Service, auth Authenticator) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/admin/words/list", unary(s.ListWords))
+	mux.HandleFunc("/v1/admin/words/add", unary(s.AddWords))
+	mux.HandleFunc("/v1/admin/words/delete", unary(s.DelWords))
+	mux.HandleFunc("/v1/admin/words/import", unary(s.ImportWords))
+	mux.HandleFunc("/v1/admin/version", unary(s.GetVersion))
+	mux.HandleFunc("/v1/admin/rollback", unary(s.Rollback))
+	mux.HandleFunc("/v1/admin/reload", unary(s.Reload))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth == nil || !auth(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+func adminPost(t *testing.T, url, token, body string, rsp interface{}) int {
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if rsp != nil {
+		json.NewDecoder(res.Body).Decode(rsp)
+	}
+	return res.StatusCode
+}
+
+func TestAdminHandler(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("色情", "赌博")
+	s := NewService(filter)
+	srv := httptest.NewServer(NewAdminHandler(s, BearerToken("secret")))
+	defer srv.Close()
+
+	if code := adminPost(t, srv.URL+"/v1/admin/version", "wrong", `{}`, nil); code != http.StatusUnauthorized {
+		t.Errorf("wrong token, got %d", code)
+	}
+
+	var list ListWordsResponse
+	if code := adminPost(t, srv.URL+"/v1/admin/words/list", "secret", `{"prefix":"色"}`, &list); code != http.StatusOK {
+		t.Fatalf("list, got %d", code)
+	}
+	if !reflect.DeepEqual(list.Words, []string{"色情"}) || list.Version != 1 {
+		t.Errorf("list, got %+v", list)
+	}
+
+	var imported ImportResponse
+	adminPost(t, srv.URL+"/v1/admin/words/import", "secret", `{"dictionary":"色情\n东西\n"}`, &imported)
+	if imported.Version != 2 || imported.Report == nil || imported.Report.Added != 1 || imported.Report.Duplicates != 1 {
+		t.Errorf("import, got %+v %+v", imported, imported.Report)
+	}
+
+	var version WordsResponse
+	if code := adminPost(t, srv.URL+"/v1/admin/reload", "secret", `{}`, &version); code != http.StatusBadRequest {
+		t.Errorf("reload without reloader, got %d", code)
+	}
+	s.SetReloader(func(ctx context.Context, filter *sensitive.Filter) error {
+		filter.ResetWords("新词")
+		return nil
+	})
+	adminPost(t, srv.URL+"/v1/admin/reload", "secret", `{}`, &version)
+	if version.Version != 3 || !reflect.DeepEqual(filter.Words(), []string{"新词"}) {
+		t.Errorf("reload, got %+v %v", version, filter.Words())
+	}
+
+	adminPost(t, srv.URL+"/v1/admin/rollback", "secret", `{"version":2}`, &version)
+	if !filter.HasWord("东西") {
+		t.Errorf("rollback, got %v", filter.Words())
+	}
+
+	if code := adminPost(t, srv.URL+"/v1/admin/version", "secret", `{}`, nil); code != http.StatusOK {
+		t.Errorf("version, got %d", code)
+	}
+}
+
+func TestAdminHandlerInterceptor(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("色情")
+	filter.SetInterceptor(func(op sensitive.MutationOp, word string) error {
+		if word == "色情" || word == "保护词" {
+			return errors.New("protected word")
+		}
+		return nil
+	})
+	srv := httptest.NewServer(NewAdminHandler(NewService(filter), BearerToken("secret")))
+	defer srv.Close()
+
+	for _, c := range []struct{ path, body string }{
+		{"/v1/admin/words/add", `{"words":["东西","保护词"]}`},
+		{"/v1/admin/words/delete", `{"words":["色情"]}`},
+		{"/v1/admin/words/import", `{"dictionary":"东西\n!色情\n"}`},
+	} {
+		if code := adminPost(t, srv.URL+c.path, "secret", c.body, nil); code != http.StatusBadRequest {
+			t.Errorf("%s rejected by interceptor, got %d", c.path, code)
+		}
+	}
+	if !reflect.DeepEqual(filter.Words(), []string{"色情"}) {
+		t.Errorf("rejected mutations should not apply, got %v", filter.Words())
+	}
+
+	if code := adminPost(t, srv.URL+"/v1/admin/words/import", "secret", `{"dictionary":"东西\n"}`, nil); code != http.StatusOK || !filter.HasWord("东西") {
+		t.Errorf("allowed import, got %d", code)
+	}
+}
+
+func TestAdminHandlerWithoutAuth(t *testing.T) {
+	srv := httptest.NewServer(NewAdminHandler(NewService(sensitive.New()), nil))
+	defer srv.Close()
+	if code := adminPost(t, srv.URL+"/v1/admin/version", "", `{}`, nil); code != http.StatusUnauthorized {
+		t.Errorf("got %d", code)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build go1.21
+
+package server
+
+import "net/http"
+
+// enableFullDuplex 允许HTTP/1.x的handler在写响应的同时继续读取请求体
+func enableFullDuplex(w http.ResponseWriter) error {
+	return http.NewResponseController(w).EnableFullDuplex()
+}
@@ -0,0 +1,10 @@
+//go:build !go1.21
+
+package server
+
+import "net/http"
+
+// enableFullDuplex Go 1.21之前的HTTP/1.x服务端不支持边读请求体边写响应
+func enableFullDuplex(w http.ResponseWriter) error {
+	return http.ErrNotSupported
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// DefaultHeartbeat 订阅连接上没有更新时发送心跳的默认间隔
+const DefaultHeartbeat = 15 * time.Second
+
+// NewHandler 返回Service的HTTP/JSON接口：
+//
+//	POST /v1/check         CheckRequest  -> CheckResponse
+//	POST /v1/filter        FilterRequest -> FilterResponse
+//	POST /v1/words/add     WordsRequest  -> WordsResponse
+//	POST /v1/words/delete  WordsRequest  -> WordsResponse
+//	POST /v1/filter/stream 请求体和响应均为以换行分隔的JSON，逐行为
+//	                       FilterChunk和FilterChunkResponse，见FilterStream
+//	GET  /v1/watch         以换行分隔的JSON持续输出Delta，请求头Accept为
+//	                       text/event-stream时改用Server-Sent Events
+func NewHandler(s *Service) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/check", unary(s.Check))
+	mux.HandleFunc("/v1/filter", unary(s.Filter))
+	mux.HandleFunc("/v1/words/add", unary(s.AddWords))
+	mux.HandleFunc("/v1/words/delete", unary(s.DelWords))
+	mux.HandleFunc("/v1/filter/stream", s.serveFilterStream)
+	mux.HandleFunc("/v1/watch", s.serveWatch)
+	return mux
+}
+
+// SetHeartbeat 设置订阅连接上没有更新时发送心跳的间隔，默认为DefaultHeartbeat，
+// 需要在开始服务之前设置
+func (s *Service) SetHeartbeat(d time.Duration) {
+	if d > 0 {
+		s.heartbeat = d
+	}
+}
+
+// unary 将一个一元方法包装为POST JSON接口
+func unary[Req, Rsp any](fn func(context.Context, *Req) (*Rsp, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+			return
+		}
+
+		req := new(Req)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		rsp, err := fn(r.Context(), req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, rsp)
+	}
+}
+
+func (s *Service) serveWatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+
+	sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+
+	var (
+		mu         sync.Mutex
+		flusher, _ = w.(http.Flusher)
+	)
+	write := func(b []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	// 定期发送心跳，避免代理因连接空闲而断开，也让客户端能及时发现断线。
+	// 返回前等待心跳goroutine退出，handler返回后不能再写w
+	var (
+		ctx, cancel = context.WithCancel(r.Context())
+		interval    = s.heartbeat
+		done        = make(chan struct{})
+	)
+	defer func() {
+		cancel()
+		<-done
+	}()
+	go func() {
+		defer close(done)
+		heartbeat := []byte("\n")
+		if sse {
+			heartbeat = []byte(":\n\n")
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if write(heartbeat) != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	s.WatchDictionary(ctx, &WatchRequest{}, func(delta *sensitive.Delta) error {
+		data, err := json.Marshal(delta)
+		if err != nil {
+			return err
+		}
+		if sse {
+			return write([]byte(fmt.Sprintf("id: %d\nevent: delta\ndata: %s\n\n", delta.Version, data)))
+		}
+		return write(append(data, '\n'))
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
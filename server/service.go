@@ -0,0 +1,168 @@
+// Package server 将sensitive.Filter包装为HTTP/JSON远程过滤服务。
+//
+// Service的方法是各接口的实现，不依赖net/http，便于嵌入其他传输层；
+// NewHandler和NewAdminHandler将其发布为HTTP接口，请求和响应即本包中
+// 同名结构体的JSON编码。
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// watchBuffer 每个订阅者可以积压的增量个数，超出后改为推送完整词典
+const watchBuffer = 64
+
+// ErrEmptyWords 请求中没有词语
+var ErrEmptyWords = errors.New("server: no words in request")
+
+// CheckRequest 检测请求
+type CheckRequest struct {
+	Text string `json:"text"`
+}
+
+// CheckResponse 检测结果，Words为命中的全部敏感词，Reason为输入被拒绝的原因，
+// 输入被拒绝时Valid为false
+type CheckResponse struct {
+	Valid  bool     `json:"valid"`
+	Words  []string `json:"words,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// FilterRequest 过滤请求，Replacement为空时删除敏感词，
+// 否则用其第一个字符替换敏感词的每个字符
+type FilterRequest struct {
+	Text        string `json:"text"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// FilterResponse 过滤结果
+type FilterResponse struct {
+	Text  string   `json:"text"`
+	Words []string `json:"words,omitempty"`
+}
+
+// WordsRequest 增删词语请求
+type WordsRequest struct {
+	Words []string `json:"words"`
+}
+
+// WordsResponse 增删词语后的词典版本
+type WordsResponse struct {
+	Version uint64 `json:"version"`
+}
+
+// WatchRequest 订阅词典更新的请求
+type WatchRequest struct{}
+
+// Service 过滤服务
+type Service struct {
+	filter *sensitive.Filter
+	reload func(ctx context.Context, filter *sensitive.Filter) error
+	// heartbeat 订阅连接的心跳间隔，见SetHeartbeat
+	heartbeat time.Duration
+}
+
+// NewService 返回包装filter的过滤服务
+func NewService(filter *sensitive.Filter) *Service {
+	return &Service{filter: filter, heartbeat: DefaultHeartbeat}
+}
+
+// Check 检测文本是否含有敏感词，与Filter.Validate一样先去噪再匹配
+func (s *Service) Check(ctx context.Context, req *CheckRequest) (*CheckResponse, error) {
+	if _, err := s.filter.CheckInput(req.Text); err != nil {
+		return &CheckResponse{Reason: err.Error()}, nil
+	}
+	valid, _ := s.filter.Validate(req.Text)
+	rsp := &CheckResponse{Valid: valid}
+	if !valid {
+		rsp.Words = s.filter.FindAll(s.filter.RemoveNoise(req.Text))
+	}
+	return rsp, nil
+}
+
+// Filter 替换或删除文本中的敏感词
+func (s *Service) Filter(ctx context.Context, req *FilterRequest) (*FilterResponse, error) {
+	rsp := &FilterResponse{Words: s.filter.FindAll(req.Text)}
+	if req.Replacement == "" {
+		rsp.Text = s.filter.FilterWord(req.Text)
+	} else {
+		repl, _ := utf8.DecodeRuneInString(req.Replacement)
+		rsp.Text = s.filter.Replace(req.Text, repl)
+	}
+	return rsp, nil
+}
+
+// AddWords 添加敏感词
+func (s *Service) AddWords(ctx context.Context, req *WordsRequest) (*WordsResponse, error) {
+	if len(req.Words) == 0 {
+		return nil, ErrEmptyWords
+	}
+	if err := s.filter.TryApplyDelta(req.Words, nil); err != nil {
+		return nil, err
+	}
+	return &WordsResponse{Version: s.filter.Version()}, nil
+}
+
+// DelWords 删除敏感词
+func (s *Service) DelWords(ctx context.Context, req *WordsRequest) (*WordsResponse, error) {
+	if len(req.Words) == 0 {
+		return nil, ErrEmptyWords
+	}
+	if err := s.filter.TryApplyDelta(nil, req.Words); err != nil {
+		return nil, err
+	}
+	return &WordsResponse{Version: s.filter.Version()}, nil
+}
+
+// WatchDictionary 先用send推送一次Reset为true的完整词典，之后持续推送增量，
+// 直到ctx结束或send返回错误。订阅者处理过慢、积压过多时改为再推送一次完整词典。
+// 增量按顺序推送且增删都是幂等的，版本号不大于完整词典版本的增量可以忽略
+func (s *Service) WatchDictionary(ctx context.Context, req *WatchRequest, send func(*sensitive.Delta) error) error {
+	var (
+		deltas = make(chan sensitive.Delta, watchBuffer)
+		lagged = make(chan struct{}, 1)
+	)
+	cancel := s.filter.Watch(func(delta sensitive.Delta) {
+		select {
+		case deltas <- delta:
+		default:
+			select {
+			case lagged <- struct{}{}:
+			default:
+			}
+		}
+	})
+	defer cancel()
+
+	if err := send(s.full()); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-lagged:
+			for len(deltas) > 0 {
+				<-deltas
+			}
+			if err := send(s.full()); err != nil {
+				return err
+			}
+		case delta := <-deltas:
+			if err := send(&delta); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// full 返回完整词典
+func (s *Service) full() *sensitive.Delta {
+	version := s.filter.Version()
+	return &sensitive.Delta{Version: version, Reset: true, Added: s.filter.Words()}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+func post(t *testing.T, url, body string, rsp interface{}) int {
+	res, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	json.NewDecoder(res.Body).Decode(rsp)
+	return res.StatusCode
+}
+
+func TestHandler(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("垃圾")
+	srv := httptest.NewServer(NewHandler(NewService(filter)))
+	defer srv.Close()
+
+	var check CheckResponse
+	post(t, srv.URL+"/v1/check", `{"text":"真垃圾"}`, &check)
+	if check.Valid || !reflect.DeepEqual(check.Words, []string{"垃圾"}) {
+		t.Errorf("check, got %+v", check)
+	}
+
+	// 与Validate一致，去噪后再匹配
+	check = CheckResponse{}
+	post(t, srv.URL+"/v1/check", `{"text":"真垃 圾"}`, &check)
+	if valid, _ := filter.Validate("真垃 圾"); check.Valid != valid || !reflect.DeepEqual(check.Words, []string{"垃圾"}) {
+		t.Errorf("check with noise, got %+v", check)
+	}
+
+	filter.SetMaxTextLen(6, false)
+	check = CheckResponse{}
+	post(t, srv.URL+"/v1/check", `{"text":"好垃圾"}`, &check)
+	if check.Valid || check.Reason == "" {
+		t.Errorf("check rejected input, got %+v", check)
+	}
+	filter.SetMaxTextLen(0, false)
+
+	var words WordsResponse
+	if code := post(t, srv.URL+"/v1/words/add", `{"words":["东西"]}`, &words); code != http.StatusOK || words.Version != 2 {
+		t.Errorf("add words, got %d %+v", code, words)
+	}
+	if code := post(t, srv.URL+"/v1/words/add", `{"words":[]}`, &words); code != http.StatusBadRequest {
+		t.Errorf("add empty words, got %d", code)
+	}
+
+	var filtered FilterResponse
+	post(t, srv.URL+"/v1/filter", `{"text":"垃圾东西啊","replacement":"*"}`, &filtered)
+	if filtered.Text != "****啊" {
+		t.Errorf("filter, got %+v", filtered)
+	}
+}
+
+func TestWatchDictionary(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("垃圾")
+	srv := httptest.NewServer(NewHandler(NewService(filter)))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/watch", nil)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	scanner := bufio.NewScanner(res.Body)
+	var delta sensitive.Delta
+	if !scanner.Scan() || json.Unmarshal(scanner.Bytes(), &delta) != nil {
+		t.Fatalf("read full dictionary: %v", scanner.Err())
+	}
+	if !delta.Reset || !reflect.DeepEqual(delta.Added, []string{"垃圾"}) {
+		t.Errorf("full dictionary, got %+v", delta)
+	}
+
+	filter.AddWord("东西")
+	delta = sensitive.Delta{}
+	if !scanner.Scan() || json.Unmarshal(scanner.Bytes(), &delta) != nil {
+		t.Fatalf("read delta: %v", scanner.Err())
+	}
+	if delta.Reset || !reflect.DeepEqual(delta.Added, []string{"东西"}) || delta.Version != 2 {
+		t.Errorf("delta, got %+v", delta)
+	}
+}
+
+func TestWatchServerSentEvents(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("垃圾")
+	service := NewService(filter)
+	service.SetHeartbeat(10 * time.Millisecond)
+	srv := httptest.NewServer(NewHandler(service))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/watch", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content type %q", ct)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	var lines []string
+	for len(lines) < 4 && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if lines[0] != "id: 1" || lines[1] != "event: delta" || !strings.HasPrefix(lines[2], "data: ") || lines[3] != "" {
+		t.Fatalf("first event, got %q", lines)
+	}
+	var delta sensitive.Delta
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &delta); err != nil || !delta.Reset {
+		t.Errorf("first event data, got %+v %v", delta, err)
+	}
+
+	// 空闲时应收到以:开头的心跳注释
+	if !scanner.Scan() || scanner.Text() != ":" {
+		t.Errorf("heartbeat, got %q", scanner.Text())
+	}
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// FilterChunk 流式过滤请求中的一段文本，Replacement只在第一段中生效，
+// 含义同FilterRequest
+type FilterChunk struct {
+	Text        string `json:"text"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// ChunkMatch 流式过滤中的一次命中，Offset为命中词在整个流中的rune下标
+type ChunkMatch struct {
+	Word   string `json:"word"`
+	Offset int64  `json:"offset"`
+}
+
+// FilterChunkResponse 过滤后的一段文本及其中的命中。输出的分段与输入不一一对应，
+// 按顺序拼接全部Text即为过滤后的完整文本
+type FilterChunkResponse struct {
+	Text    string       `json:"text"`
+	Matches []ChunkMatch `json:"matches,omitempty"`
+}
+
+// FilterStream 流式过滤：recv逐段读取文本直到返回io.EOF，过滤后的文本和命中
+// 随时通过send返回，不需要在内存中保留完整的文档。每段末尾可能与下一段组成
+// 敏感词的部分(最长词长度减一个字符，连同其间的分隔，见Filter.CarryLen)
+// 会留到下一段一起处理。过滤器设置了
+// SetMaxTextLen时，每次查询的文本(一段加上留下的部分)超过上限则返回
+// sensitive.ErrTextTooLong，不会把未检查的文本原样发出
+func (s *Service) FilterStream(ctx context.Context, recv func() (*FilterChunk, error), send func(*FilterChunkResponse) error) error {
+	var (
+		keep    = s.filter.LongestWord() - 1
+		pending []rune
+		offset  int64
+		repl    rune
+		first   = true
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunk, err := recv()
+		final := err == io.EOF
+		if err != nil && !final {
+			return err
+		}
+
+		if chunk != nil {
+			if first && chunk.Replacement != "" {
+				repl, _ = utf8.DecodeRuneInString(chunk.Replacement)
+			}
+			first = false
+			pending = append(pending, []rune(chunk.Text)...)
+		}
+
+		cut := len(pending)
+		if !final {
+			cut -= s.filter.CarryLen(string(pending), keep)
+		}
+		if cut > 0 {
+			rsp, n, err := s.filterPrefix(pending, cut, offset, repl)
+			if err != nil {
+				return err
+			}
+			if err := send(rsp); err != nil {
+				return err
+			}
+			pending = append(pending[:0], pending[n:]...)
+			offset += int64(n)
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// filterPrefix 过滤runes中从cut之前开始的全部命中，返回过滤后的文本及处理了的
+// rune数。跨过cut的命中一并处理，因此处理的长度可能大于cut。文本会被过滤器
+// 拒绝或截断时返回错误
+func (s *Service) filterPrefix(runes []rune, cut int, offset int64, repl rune) (*FilterChunkResponse, int, error) {
+	var (
+		rsp  = &FilterChunkResponse{}
+		out  []rune
+		left = 0
+		text = string(runes)
+	)
+	if truncated, err := s.filter.CheckInput(text); err != nil {
+		return nil, 0, err
+	} else if truncated {
+		return nil, 0, sensitive.ErrTextTooLong
+	}
+	for _, m := range s.filter.FindAllWithIndex(text) {
+		if m.Start >= cut {
+			break
+		}
+		if m.Start < left {
+			continue
+		}
+		out = append(out, runes[left:m.Start]...)
+		if repl != 0 {
+			for i := m.Start; i < m.End; i++ {
+				out = append(out, repl)
+			}
+		}
+		rsp.Matches = append(rsp.Matches, ChunkMatch{Word: m.Word, Offset: offset + int64(m.Start)})
+		left = m.End
+	}
+	if left < cut {
+		out = append(out, runes[left:cut]...)
+		left = cut
+	}
+	rsp.Text = string(out)
+	return rsp, left, nil
+}
+
+// serveFilterStream 以换行分隔的JSON承载FilterStream：请求体逐行为FilterChunk，
+// 响应逐行为FilterChunkResponse，每行写出后立即发送。开始输出后出错时
+// 以一行{"error": ...}结束响应
+func (s *Service) serveFilterStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	// HTTP/1.x默认在开始写响应前读完请求体，需要开启全双工才能边读边写
+	if err := enableFullDuplex(w); err != nil && r.ProtoMajor < 2 {
+		writeError(w, http.StatusNotImplemented, err.Error())
+		return
+	}
+
+	var (
+		dec        = json.NewDecoder(r.Body)
+		enc        = json.NewEncoder(w)
+		flusher, _ = w.(http.Flusher)
+		sent       bool
+	)
+	recv := func() (*FilterChunk, error) {
+		chunk := new(FilterChunk)
+		if err := dec.Decode(chunk); err != nil {
+			return nil, err
+		}
+		return chunk, nil
+	}
+	send := func(rsp *FilterChunkResponse) error {
+		if !sent {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			sent = true
+		}
+		if err := enc.Encode(rsp); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+	if err := s.FilterStream(r.Context(), recv, send); err != nil {
+		if !sent {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		enc.Encode(map[string]string{"error": err.Error()})
+	}
+}
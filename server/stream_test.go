@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+func filterStream(t *testing.T, s *Service, replacement string, chunks ...string) (string, []ChunkMatch) {
+	var (
+		i       int
+		out     strings.Builder
+		matches []ChunkMatch
+	)
+	recv := func() (*FilterChunk, error) {
+		if i == len(chunks) {
+			return nil, io.EOF
+		}
+		i++
+		return &FilterChunk{Text: chunks[i-1], Replacement: replacement}, nil
+	}
+	send := func(rsp *FilterChunkResponse) error {
+		out.WriteString(rsp.Text)
+		matches = append(matches, rsp.Matches...)
+		return nil
+	}
+	if err := s.FilterStream(context.Background(), recv, send); err != nil {
+		t.Fatal(err)
+	}
+	return out.String(), matches
+}
+
+func TestFilterStream(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("垃圾", "色情网站")
+	s := NewService(filter)
+
+	// 敏感词被切分在不同的段中
+	got, matches := filterStream(t, s, "*", "这是垃", "圾，那是色", "情", "网站。", "")
+	if got != "这是**，那是****。" {
+		t.Errorf("got %q", got)
+	}
+	want := []ChunkMatch{{Word: "垃圾", Offset: 2}, {Word: "色情网站", Offset: 7}}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("matches, got %+v", matches)
+	}
+
+	if got, _ := filterStream(t, s, "", "垃", "圾桶"); got != "桶" {
+		t.Errorf("delete, got %q", got)
+	}
+
+	long := strings.Repeat("好", 100000)
+	if got, matches := filterStream(t, s, "*", long, "垃圾", long); got != long+"**"+long || len(matches) != 1 || matches[0].Offset != 100000 {
+		t.Errorf("long text, got %d runes %+v", len([]rune(got)), matches)
+	}
+
+	// 开启分隔字符后，跨段的词在原文中可以比词长更长
+	filter.SetSeparators("-")
+	chunks := []string{"这是垃", "-----", "-----圾，色-情", "-网-站"}
+	if got, matches := filterStream(t, s, "*", chunks...); got != filter.Replace(strings.Join(chunks, ""), '*') || len(matches) != 2 {
+		t.Errorf("separators across chunks, got %q %+v", got, matches)
+	}
+	filter.SetSeparators("")
+
+	// 超过SetMaxTextLen的文本不能原样发出
+	filter.SetMaxTextLen(9, false)
+	var sent bool
+	recv := func() (*FilterChunk, error) { return &FilterChunk{Text: "好好垃圾"}, nil }
+	send := func(*FilterChunkResponse) error { sent = true; return nil }
+	if err := s.FilterStream(context.Background(), recv, send); err != sensitive.ErrTextTooLong || sent {
+		t.Errorf("rejected chunk, got %v, sent %v", err, sent)
+	}
+}
+
+func TestFilterStreamHandler(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("垃圾")
+	srv := httptest.NewServer(NewHandler(NewService(filter)))
+	defer srv.Close()
+
+	// 边发送边接收，每段的结果不需要等待请求体结束
+	pr, pw := io.Pipe()
+	go pw.Write([]byte(`{"text":"这是垃","replacement":"*"}` + "\n"))
+	res, err := http.Post(srv.URL+"/v1/filter/stream", "application/x-ndjson", pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	dec := json.NewDecoder(res.Body)
+	var rsp FilterChunkResponse
+	if err := dec.Decode(&rsp); err != nil || rsp.Text != "这是" {
+		t.Fatalf("first chunk, got %+v %v", rsp, err)
+	}
+	pw.Write([]byte(`{"text":"圾啊"}` + "\n"))
+	rsp = FilterChunkResponse{}
+	if err := dec.Decode(&rsp); err != nil || rsp.Text != "**" || !reflect.DeepEqual(rsp.Matches, []ChunkMatch{{Word: "垃圾", Offset: 2}}) {
+		t.Fatalf("second chunk, got %+v %v", rsp, err)
+	}
+	pw.Close()
+	rsp = FilterChunkResponse{}
+	if err := dec.Decode(&rsp); err != nil || rsp.Text != "啊" {
+		t.Errorf("last chunk, got %+v %v", rsp, err)
+	}
+
+	filter.SetMaxTextLen(3, false)
+	if code := post(t, srv.URL+"/v1/filter/stream", `{"text":"好垃圾"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("rejected stream, got %d", code)
+	}
+}
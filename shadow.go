@@ -0,0 +1,117 @@
+package sensitive
+
+import "sync/atomic"
+
+// Divergence 候选词典与线上词典在同一段文本上的结果差异
+type Divergence struct {
+	Text string
+	// Added 只有候选词典命中的词
+	Added []string
+	// Removed 只有线上词典命中的词
+	Removed []string
+}
+
+// ShadowStats 影子评估的统计
+type ShadowStats struct {
+	// Compared 比较过的文本数
+	Compared uint64
+	// Diverged 结果不一致的文本数
+	Diverged uint64
+}
+
+// ShadowFilter 影子模式：查询结果始终来自线上词典，同时用候选词典检查
+// 同样的文本并报告差异，用于在真实流量上验证词典更新，确认无误后再上线
+type ShadowFilter struct {
+	live      *Filter
+	candidate *Filter
+	report    func(Divergence)
+	compared  atomic.Uint64
+	diverged  atomic.Uint64
+}
+
+// NewShadowFilter 返回以live为线上词典、candidate为候选词典的影子过滤器，
+// 结果不一致时同步调用report
+func NewShadowFilter(live, candidate *Filter, report func(Divergence)) *ShadowFilter {
+	return &ShadowFilter{live: live, candidate: candidate, report: report}
+}
+
+// FindIn 用线上词典检测敏感词
+func (sf *ShadowFilter) FindIn(text string) (bool, string) {
+	found, word := sf.live.FindIn(text)
+	sf.compare(text, sf.live.words(text))
+	return found, word
+}
+
+// Validate 用线上词典检测字符串是否合法
+func (sf *ShadowFilter) Validate(text string) (bool, string) {
+	valid, word := sf.live.Validate(text)
+	sf.compare(text, sf.live.words(text))
+	return valid, word
+}
+
+// FindAll 用线上词典找到所有匹配词
+func (sf *ShadowFilter) FindAll(text string) []string {
+	words := sf.live.FindAll(text)
+	sf.compare(text, words)
+	return words
+}
+
+// Replace 用线上词典和谐敏感词
+func (sf *ShadowFilter) Replace(text string, repl rune) string {
+	result := sf.live.Replace(text, repl)
+	sf.compare(text, sf.live.words(text))
+	return result
+}
+
+// FilterWord 用线上词典过滤敏感词
+func (sf *ShadowFilter) FilterWord(text string) string {
+	result := sf.live.FilterWord(text)
+	sf.compare(text, sf.live.words(text))
+	return result
+}
+
+// Stats 返回影子评估的统计
+func (sf *ShadowFilter) Stats() ShadowStats {
+	return ShadowStats{Compared: sf.compared.Load(), Diverged: sf.diverged.Load()}
+}
+
+// compare 用候选词典检查text，与线上词典的命中liveWords比较
+func (sf *ShadowFilter) compare(text string, liveWords []string) {
+	sf.compared.Add(1)
+
+	var (
+		candidateWords = sf.candidate.words(text)
+		d              = Divergence{Text: text}
+		live           = make(map[string]struct{}, len(liveWords))
+		candidate      = make(map[string]struct{}, len(candidateWords))
+	)
+	for _, word := range liveWords {
+		live[word] = struct{}{}
+	}
+	for _, word := range candidateWords {
+		candidate[word] = struct{}{}
+		if _, ok := live[word]; !ok {
+			d.Added = append(d.Added, word)
+		}
+	}
+	for _, word := range liveWords {
+		if _, ok := candidate[word]; !ok {
+			d.Removed = append(d.Removed, word)
+		}
+	}
+	if len(d.Added) == 0 && len(d.Removed) == 0 {
+		return
+	}
+
+	sf.diverged.Add(1)
+	if sf.report != nil {
+		sf.report(d)
+	}
+}
+
+// words 同FindAll，但不计入查询统计
+func (filter *Filter) words(text string) []string {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.findAll(text)
+}
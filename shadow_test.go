@@ -0,0 +1,41 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShadowFilter(t *testing.T) {
+	live, candidate := New(), New()
+	live.AddWord("色情", "旧词")
+	candidate.AddWord("色情", "新词")
+
+	var reports []Divergence
+	sf := NewShadowFilter(live, candidate, func(d Divergence) {
+		reports = append(reports, d)
+	})
+
+	if found, word := sf.FindIn("旧词和新词"); !found || word != "旧词" {
+		t.Errorf("shadow find in should use the live filter, got %v %s", found, word)
+	}
+	if got := sf.Replace("色情", '*'); got != "**" {
+		t.Errorf("shadow replace, got %s", got)
+	}
+	if words := sf.FindAll("新词"); words != nil {
+		t.Errorf("shadow find all should use the live filter, got %v", words)
+	}
+
+	expect := []Divergence{
+		{Text: "旧词和新词", Added: []string{"新词"}, Removed: []string{"旧词"}},
+		{Text: "新词", Added: []string{"新词"}},
+	}
+	if !reflect.DeepEqual(reports, expect) {
+		t.Errorf("divergences, got %v, expect %v", reports, expect)
+	}
+	if stats := sf.Stats(); stats != (ShadowStats{Compared: 3, Diverged: 2}) {
+		t.Errorf("shadow stats, got %+v", stats)
+	}
+	if stats := live.Stats(); stats.Queries != 3 {
+		t.Errorf("live queries should only count the served calls, got %d", stats.Queries)
+	}
+}
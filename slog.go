@@ -0,0 +1,57 @@
+//go:build go1.21
+
+package sensitive
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SetLogger 设置默认过滤器的日志。需要Go 1.21及以上版本编译，
+// 更早的版本中没有SetLogger
+func SetLogger(logger *slog.Logger) {
+	Default().SetLogger(logger)
+}
+
+// SetLogger 用logger记录词典的加载结果(成功为Info级别，失败为Error级别)，
+// 以及Debug级别的命中事件。命中事件只包含命中的词和文本长度，不记录原文。
+// logger为nil时不记录
+func (filter *Filter) SetLogger(logger *slog.Logger) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if logger == nil {
+		filter.events = nil
+		return
+	}
+	filter.events = slogEvents{logger: logger}
+}
+
+type slogEvents struct {
+	logger *slog.Logger
+}
+
+func (e slogEvents) loadFinished(state LoadState, version uint64, elapsed time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("source", state.Source),
+		slog.Uint64("version", version),
+		slog.Duration("elapsed", elapsed),
+	}
+	if state.Err != nil {
+		attrs = append(attrs, slog.Any("error", state.Err))
+		e.logger.LogAttrs(context.Background(), slog.LevelError, "sensitive: dictionary load failed", attrs...)
+		return
+	}
+	e.logger.LogAttrs(context.Background(), slog.LevelInfo, "sensitive: dictionary loaded", attrs...)
+}
+
+func (e slogEvents) matchEnabled() bool {
+	return e.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+func (e slogEvents) matchFound(words []string, textLength int) {
+	e.logger.LogAttrs(context.Background(), slog.LevelDebug, "sensitive: match",
+		slog.Any("words", words),
+		slog.Int("text_length", textLength),
+	)
+}
@@ -0,0 +1,50 @@
+//go:build go1.21
+
+package sensitive
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	filter := New()
+	filter.SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	filter.Load(strings.NewReader("色情\n"))
+	filter.LoadWordDict("no/such/dict.txt")
+	filter.FindIn("你好")
+	filter.FindIn("看色情")
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records: %s", len(records), buf.String())
+	}
+	if records[0]["level"] != "INFO" || records[0]["version"] != float64(1) {
+		t.Errorf("load, got %v", records[0])
+	}
+	if records[1]["level"] != "ERROR" || records[1]["source"] != "no/such/dict.txt" || records[1]["error"] == nil {
+		t.Errorf("load failure, got %v", records[1])
+	}
+	if records[2]["level"] != "DEBUG" || records[2]["text_length"] != float64(len("看色情")) {
+		t.Errorf("match, got %v", records[2])
+	}
+
+	buf.Reset()
+	filter.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	filter.FindIn("看色情")
+	if buf.Len() != 0 {
+		t.Errorf("match events should be skipped above debug level, got %s", buf.String())
+	}
+}
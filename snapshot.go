@@ -0,0 +1,112 @@
+package sensitive
+
+import "errors"
+
+// DefaultMaxSnapshots 默认保留的快照个数
+const DefaultMaxSnapshots = 16
+
+// ErrUnknownVersion 要回滚的版本没有对应的快照
+var ErrUnknownVersion = errors.New("sensitive: unknown dictionary version")
+
+// Snapshot 词典在某个版本上的只读副本，可以像FrozenFilter一样直接查询。
+// 长时间运行的批处理任务持有快照查询，期间原过滤器上的AddWord、DelWord、
+// Load等修改不会影响快照，始终看到同一份词典
+type Snapshot struct {
+	version uint64
+	*FrozenFilter
+}
+
+// Version 返回快照对应的词典版本
+func (snap *Snapshot) Version() uint64 {
+	return snap.version
+}
+
+// clone 复制双数组，使副本不再引用内存映射
+func (da *DoubleArray) clone() *DoubleArray {
+	return &DoubleArray{
+		alphabet: append([]rune(nil), da.alphabet...),
+		base:     append([]int32(nil), da.base...),
+		check:    append([]int32(nil), da.check...),
+		ends:     append([]uint64(nil), da.ends...),
+	}
+}
+
+// Version 返回词典当前的版本号
+func Version() uint64 {
+	return Default().Version()
+}
+
+// Version 返回词典当前的版本号，每次修改词典都会使其加一
+func (filter *Filter) Version() uint64 {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	return filter.version
+}
+
+// TakeSnapshot 为当前词典生成快照
+func TakeSnapshot() *Snapshot {
+	return Default().Snapshot()
+}
+
+// Snapshot 为当前词典生成只读快照并保留下来，之后可通过Rollback
+// 回到该版本。最多保留最近SetMaxSnapshots个快照，更早的被丢弃
+func (filter *Filter) Snapshot() *Snapshot {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	if snap, ok := filter.snapshots[filter.version]; ok {
+		return snap
+	}
+
+	snap := &Snapshot{version: filter.version, FrozenFilter: &FrozenFilter{filter: filter.frozen()}}
+	if filter.snapshots == nil {
+		filter.snapshots = make(map[uint64]*Snapshot)
+	}
+	filter.snapshots[snap.version] = snap
+	filter.snapshotOrder = append(filter.snapshotOrder, snap.version)
+	filter.trimSnapshots()
+	return snap
+}
+
+// trimSnapshots 丢弃超出数量限制的旧快照，调用方需持有写锁
+func (filter *Filter) trimSnapshots() {
+	for len(filter.snapshotOrder) > filter.maxSnapshots {
+		delete(filter.snapshots, filter.snapshotOrder[0])
+		filter.snapshotOrder = filter.snapshotOrder[1:]
+	}
+}
+
+// Rollback 将词典回滚到指定版本
+func Rollback(version uint64) error {
+	return Default().Rollback(version)
+}
+
+// Rollback 将词典回滚到version对应快照的内容，不需要重新加载数据源。
+// 回滚本身也是一次修改，版本号会继续递增而不是回到version
+func (filter *Filter) Rollback(version uint64) error {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	snap, ok := filter.snapshots[version]
+	if !ok {
+		return ErrUnknownVersion
+	}
+	filter.setDict(snap.filter.da)
+	filter.rebuildPrefilter()
+	filter.pending = Delta{Reset: true}
+	filter.commit()
+	return nil
+}
+
+// SetMaxSnapshots 设置保留的快照个数
+func SetMaxSnapshots(n int) {
+	Default().SetMaxSnapshots(n)
+}
+
+// SetMaxSnapshots 设置保留的快照个数，超出时丢弃最早的快照
+func (filter *Filter) SetMaxSnapshots(n int) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.maxSnapshots = n
+	filter.trimSnapshots()
+}
@@ -0,0 +1,67 @@
+package sensitive
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRollback(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	snap := filter.Snapshot()
+	if snap.Version() != filter.Version() {
+		t.Errorf("snapshot version %d, expect %d", snap.Version(), filter.Version())
+	}
+
+	filter.DelWord("垃圾")
+	filter.AddWord("东西")
+	if got := filter.Replace("垃圾东西", '*'); got != "垃圾**" {
+		t.Errorf("replace before rollback, got %s, expect 垃圾**", got)
+	}
+
+	before := filter.Version()
+	if err := filter.Rollback(snap.Version()); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if got := filter.Replace("垃圾东西", '*'); got != "**东西" {
+		t.Errorf("replace after rollback, got %s, expect **东西", got)
+	}
+	if filter.Version() <= before {
+		t.Errorf("version should keep increasing after rollback, got %d", filter.Version())
+	}
+
+	if err := filter.Rollback(12345); err != ErrUnknownVersion {
+		t.Errorf("rollback unknown version, got %v, expect %v", err, ErrUnknownVersion)
+	}
+
+	filter.SetMaxSnapshots(0)
+	if err := filter.Rollback(snap.Version()); err != ErrUnknownVersion {
+		t.Errorf("rollback trimmed version, got %v, expect %v", err, ErrUnknownVersion)
+	}
+}
+
+func TestSnapshotQueriesDuringReload(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾", "东西")
+	snap := filter.Snapshot()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			filter.DelWord("垃圾")
+			filter.AddWord("坏人")
+			filter.Load(strings.NewReader("垃圾\n好人\n"))
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		if got := snap.Replace("垃圾东西好人", '*'); got != "****好人" {
+			t.Fatalf("snapshot should not change, got %s", got)
+		}
+		if found, _ := snap.FindIn("坏人"); found {
+			t.Fatalf("snapshot should not see later words")
+		}
+	}
+	<-done
+}
@@ -0,0 +1,60 @@
+package sensitive
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Source 词典来源，文件、URL、数据库、对象存储等均可实现，由Manager定时刷新
+type Source interface {
+	// Fetch 返回词典内容及其版本，版本与上次相同时Manager不再重新构建词典，
+	// 版本为空表示无法判断，由Manager比较内容。调用方负责关闭返回的内容
+	Fetch(ctx context.Context) (rc io.ReadCloser, version string, err error)
+}
+
+// SourceFunc 将函数转换为Source
+type SourceFunc func(ctx context.Context) (io.ReadCloser, string, error)
+
+// Fetch 调用fn
+func (fn SourceFunc) Fetch(ctx context.Context) (io.ReadCloser, string, error) {
+	return fn(ctx)
+}
+
+// FileSource 本地词典文件，以修改时间和大小作为版本
+type FileSource struct {
+	Path string
+}
+
+// Fetch 打开词典文件
+func (source FileSource) Fetch(ctx context.Context) (io.ReadCloser, string, error) {
+	f, err := os.Open(source.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, "", err
+	}
+	version := strconv.FormatInt(info.ModTime().UnixNano(), 10) + "-" + strconv.FormatInt(info.Size(), 10)
+	return f, version, nil
+}
+
+// URLSource 网络词典，以响应的ETag或Last-Modified作为版本
+type URLSource struct {
+	URL string
+	// Timeout 超时时间，为0时使用5秒
+	Timeout time.Duration
+}
+
+// Fetch 请求网络词典，错误与LoadNetWordDictTimeout相同
+func (source URLSource) Fetch(ctx context.Context) (io.ReadCloser, string, error) {
+	timeout := source.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return get(ctx, source.URL, timeout)
+}
@@ -0,0 +1,101 @@
+package sensitive
+
+// spanMode 判断是否需要逐个检查命中(分类动作、例外规则等)，调用方需持有锁
+func (filter *Filter) spanMode() bool {
+	return len(filter.actions) > 0 || len(filter.exceptions) > 0 || filter.skipLinks ||
+		filter.tokenizer != nil || filter.usePriority || filter.reverse || filter.lineJoin != nil ||
+		filter.separators != nil || filter.mergeSpans
+}
+
+// allMatches 返回text中所有位置上的全部有效命中(含相互重叠的)，
+// 按起点、终点升序排列。设置了跨行匹配或分隔字符时在删除分隔后的文本上匹配，
+// 命中的区间包括其中的分隔，调用方需持有锁
+func (filter *Filter) allMatches(text string) []Match {
+	normalizers := filter.joinNormalizers()
+	if len(normalizers) == 0 {
+		return filter.validMatches(text)
+	}
+	runes, index := normalize(text, normalizers)
+	matches := filter.validMatches(string(runes))
+	for i, m := range matches {
+		matches[i].Start, matches[i].End = index[m.Start], index[m.End-1]+1
+	}
+	return matches
+}
+
+// validMatches 同allMatches，不处理跨行匹配，调用方需持有锁
+func (filter *Filter) validMatches(text string) []Match {
+	matches := filter.matcher().matchIndex(text, MatchDefault, true)
+	if filter.reverse {
+		matches = mergeMatches(matches, filter.reversedMatches(text))
+	}
+	if filter.skipLinks && len(matches) > 0 {
+		matches = dropLinked(matches, FindLinks(text))
+	}
+	if filter.tokenizer != nil && len(matches) > 0 {
+		matches = dropCrossToken(matches, text, filter.tokenizer)
+	}
+	if len(filter.exceptions) > 0 && len(matches) > 0 {
+		offsets := runeOffsets(text)
+		kept := matches[:0]
+		for _, m := range matches {
+			if !filter.excepted(text, offsets, m) {
+				kept = append(kept, m)
+			}
+		}
+		matches = kept
+	}
+	if filter.usePriority && len(matches) > 0 {
+		matches = filter.dropLowerPriority(matches)
+	}
+	if len(filter.actions) > 0 && len(matches) > 0 {
+		matches = filter.dropAllowed(matches)
+	}
+	return matches
+}
+
+// matches 返回text中按匹配策略从左到右选出的互不重叠的有效命中，
+// MatchDefault按最长匹配处理，调用方需持有锁
+func (filter *Filter) matches(text string) []Match {
+	return selectMatches(filter.allMatches(text), filter.policy)
+}
+
+// selectMatches 从按起点、终点升序排列的命中中，从左到右选出互不重叠的命中
+func selectMatches(all []Match, policy MatchPolicy) []Match {
+	var (
+		selected []Match
+		cursor   = 0
+	)
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].Start == all[i].Start {
+			j++
+		}
+		if all[i].Start >= cursor {
+			m := all[j-1]
+			if policy == MatchShortest {
+				m = all[i]
+			}
+			selected = append(selected, m)
+			cursor = m.End
+		}
+		i = j
+	}
+	return selected
+}
+
+// uniqueWords 按出现顺序返回命中的词，去重
+func uniqueWords(matches []Match) []string {
+	var (
+		words []string
+		set   = make(map[string]struct{})
+	)
+	for _, m := range matches {
+		if _, ok := set[m.Word]; ok {
+			continue
+		}
+		set[m.Word] = struct{}{}
+		words = append(words, m.Word)
+	}
+	return words
+}
@@ -0,0 +1,68 @@
+package sensitive
+
+import (
+	"bufio"
+	"io"
+)
+
+// LoadConcurrent 在不阻塞查询的情况下加载词典
+func LoadConcurrent(rd io.Reader) error {
+	return Default().LoadConcurrent(rd)
+}
+
+// LoadConcurrent 同Load，但先在一个不加锁的临时词典中读取和解析全部内容，
+// 成功后才在一次写锁中把结果合并到当前词典，读取大文件或慢速来源期间查询
+// 不会被阻塞，也不会看到只加载了一部分的词典。词典文件中的删除指令同样生效；
+// 读取失败时当前词典保持不变。需要整体替换词典时使用Reload
+func (filter *Filter) LoadConcurrent(rd io.Reader) error {
+	filter.beginLoad("")
+	return filter.recordLoad("", filter.loadStaged(bufio.NewReader(rd)))
+}
+
+// loadStaged 在临时词典中加载buf并合并，调用方不能持有锁
+func (filter *Filter) loadStaged(buf *bufio.Reader) error {
+	filter.mu.RLock()
+	stage := New()
+	stage.maxLineLength = filter.maxLineLength
+	filter.mu.RUnlock()
+
+	removed := make(map[string]struct{})
+	stage.Watch(func(delta Delta) {
+		for _, word := range delta.Removed {
+			removed[word] = struct{}{}
+		}
+	})
+	if err := stage.loadFormat(buf, EncodingAuto, "", nil); err != nil {
+		return err
+	}
+
+	// 临时词典从空开始，其中最终保留的词即为依次执行全部指令后应存在的词，
+	// 被删除且没有再加入的词应从当前词典中删除
+	var added []string
+	stage.trie.Walk(func(word string) bool {
+		added = append(added, word)
+		delete(removed, word)
+		return true
+	})
+	deleted := make([]string, 0, len(removed))
+	for word := range removed {
+		deleted = append(deleted, word)
+	}
+
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.delWords(deleted...)
+	filter.addWords(added...)
+	for word, meta := range stage.meta {
+		filter.setMeta(word, func(m *wordMeta) {
+			if meta.category != "" {
+				m.category = meta.category
+			}
+			if meta.level != 0 {
+				m.level = meta.level
+			}
+		})
+	}
+	filter.commit()
+	return nil
+}
@@ -0,0 +1,82 @@
+package sensitive
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingReader 在release关闭前阻塞读取
+type blockingReader struct {
+	rd      io.Reader
+	release chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	<-r.release
+	return r.rd.Read(p)
+}
+
+func TestLoadConcurrent(t *testing.T) {
+	filter := New()
+	filter.AddWord("旧词", "垃圾")
+
+	rd := &blockingReader{
+		rd:      strings.NewReader("加微信|ad|3\n!旧词\n临时\n!临时\n"),
+		release: make(chan struct{}),
+	}
+	done := make(chan error)
+	go func() { done <- filter.LoadConcurrent(rd) }()
+
+	// 读取期间查询不被阻塞
+	query := make(chan bool)
+	go func() {
+		found, _ := filter.FindIn("真是垃圾")
+		query <- found
+	}()
+	select {
+	case found := <-query:
+		if !found {
+			t.Errorf("query during load should see the old dictionary")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("query blocked by LoadConcurrent")
+	}
+
+	close(rd.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	for word, expect := range map[string]bool{"加微信": true, "垃圾": true, "旧词": false, "临时": false} {
+		if got := filter.HasWord(word); got != expect {
+			t.Errorf("has %s, got %v, expect %v", word, got, expect)
+		}
+	}
+	if filter.Category("加微信") != "ad" || filter.Level("加微信") != 3 {
+		t.Errorf("meta not merged")
+	}
+	if !filter.Ready() {
+		t.Errorf("filter should be ready after loading")
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("broken")
+}
+
+func TestLoadConcurrentError(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	version := filter.Version()
+
+	if err := filter.LoadConcurrent(io.MultiReader(strings.NewReader("加微信\n"), errReader{})); err == nil {
+		t.Fatal("expect error")
+	}
+	if filter.HasWord("加微信") || filter.Version() != version {
+		t.Errorf("failed load should leave the dictionary unchanged")
+	}
+}
@@ -0,0 +1,66 @@
+package sensitive
+
+import "time"
+
+// Stats 过滤器的运行状态，供监控和调试使用
+type Stats struct {
+	// Words 词典中的词数
+	Words int
+	// Version 词典版本号
+	Version uint64
+	// LastLoad 最近一次成功加载词典的时间
+	LastLoad time.Time
+	// LoadErrors 加载词典失败的次数，包括下载和校验失败
+	LoadErrors uint64
+	// LastError 最近一次加载失败的错误，之后成功加载会清空
+	LastError string
+	// Queries 检测、过滤等查询的次数
+	Queries uint64
+	// Hits 命中了敏感词的查询次数
+	Hits uint64
+}
+
+// GetStats 返回默认过滤器的运行状态
+func GetStats() Stats {
+	return Default().Stats()
+}
+
+// Stats 返回过滤器的运行状态，词数需要遍历词典，不宜频繁调用
+func (filter *Filter) Stats() Stats {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+
+	stats := Stats{
+		Version:    filter.version,
+		LastLoad:   filter.loadState.LastSuccess,
+		LoadErrors: filter.loadErrors,
+		Queries:    filter.queries.Load(),
+		Hits:       filter.hits.Load(),
+	}
+	if filter.loadState.Err != nil {
+		stats.LastError = filter.loadState.Err.Error()
+	}
+	filter.matcher().Walk(func(string) bool {
+		stats.Words++
+		return true
+	})
+	return stats
+}
+
+// count 记录一次对text的查询，hit表示是否命中，命中时按SetSampler抽样，调用方需持有锁
+func (filter *Filter) count(text string, hit bool) {
+	filter.countSnapshot(filter, text, hit)
+}
+
+// countSnapshot 记录一次由snap完成的对text的查询，命中时按snap的配置抽样和
+// 记录命中事件。snap为ConcurrencySnapshot模式下发布的副本或filter本身
+func (filter *Filter) countSnapshot(snap *Filter, text string, hit bool) {
+	filter.queries.Add(1)
+	if hit {
+		filter.hits.Add(1)
+		snap.maybeSample(text)
+		if snap.events != nil && snap.events.matchEnabled() {
+			snap.events.matchFound(uniqueWords(snap.findAllWithIndex(text)), len(text))
+		}
+	}
+}
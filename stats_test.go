@@ -0,0 +1,42 @@
+package sensitive
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	filter := New()
+	if err := filter.Load(strings.NewReader("色情\n赌博\n")); err != nil {
+		t.Fatal(err)
+	}
+	filter.FindIn("色情网站")
+	filter.Replace("你好", '*')
+	filter.FindAll("赌博")
+
+	stats := filter.Stats()
+	if stats.Words != 2 || stats.Version != 1 || stats.LastLoad.IsZero() {
+		t.Errorf("got %+v", stats)
+	}
+	if stats.Queries != 3 || stats.Hits != 2 {
+		t.Errorf("queries %d hits %d, expect 3 2", stats.Queries, stats.Hits)
+	}
+
+	if err := filter.LoadWordDict("no/such/dict.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v", err)
+	}
+	if err := filter.LoadChecksum(strings.NewReader("东西"), "00"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("got %v", err)
+	}
+	stats = filter.Stats()
+	if stats.LoadErrors != 2 || !strings.Contains(stats.LastError, "checksum") {
+		t.Errorf("got %+v", stats)
+	}
+
+	filter.Load(strings.NewReader("东西"))
+	if stats = filter.Stats(); stats.LastError != "" || stats.LoadErrors != 2 {
+		t.Errorf("after successful load, got %+v", stats)
+	}
+}
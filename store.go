@@ -0,0 +1,212 @@
+package sensitive
+
+import (
+	"io"
+	"strings"
+)
+
+// WordStore 词典的存储后端。Filter负责加锁、归一化、分类等其余功能，
+// 只把词的增删和原始匹配交给WordStore，从而可以换用远程、持久化或压缩的
+// 存储。Filter持有写锁时调用Add、Del，持有读锁时调用Iterate、Match，
+// 因此实现本身不需要加锁，但Iterate和Match可能被并发调用。*Trie实现了WordStore
+type WordStore interface {
+	Add(words ...string)
+	Del(words ...string)
+	// Iterate 遍历所有词，fn返回false时停止
+	Iterate(fn func(word string) bool)
+	// Match 返回text中所有位置上的全部命中，包括相互重叠的，
+	// 按起点、终点升序排列，位置为rune下标
+	Match(text string) []Match
+}
+
+var _ WordStore = (*Trie)(nil)
+
+// Iterate 同Walk，实现WordStore
+func (tree *Trie) Iterate(fn func(word string) bool) {
+	tree.Walk(fn)
+}
+
+// Match 返回所有位置上的全部命中，实现WordStore
+func (tree *Trie) Match(text string) []Match {
+	return tree.matchIndex(text, MatchDefault, true)
+}
+
+// NewWithStore 返回以store为词典存储的过滤器。设置了自定义存储时Compile
+// 不生效，Freeze、SaveCompiled、DumpDOT以及通配符查询会先用store中的词
+// 临时构建一棵Trie
+func NewWithStore(store WordStore) *Filter {
+	filter := New()
+	filter.trie = nil
+	filter.store = store
+	return filter
+}
+
+// plainTrie 返回当前词典的Trie形式，设置了自定义存储时临时构建，调用方需持有锁
+func (filter *Filter) plainTrie() *Trie {
+	if filter.store == nil {
+		return filter.trie
+	}
+	return storeTrie(filter.store)
+}
+
+// resetStore 用walk遍历到的词替换自定义存储中的全部词，调用方需持有写锁
+func (filter *Filter) resetStore(walk func(fn func(word string) bool)) {
+	var old, words []string
+	filter.store.Iterate(func(word string) bool {
+		old = append(old, word)
+		return true
+	})
+	if walk != nil {
+		walk(func(word string) bool {
+			words = append(words, word)
+			return true
+		})
+	}
+	filter.store.Del(old...)
+	filter.store.Add(words...)
+}
+
+func storeTrie(store WordStore) *Trie {
+	tree := NewTrie()
+	store.Iterate(func(word string) bool {
+		tree.add(word)
+		return true
+	})
+	return tree
+}
+
+// storeMatcher 以WordStore.Match实现matcher，供设置了自定义存储的过滤器查询
+type storeMatcher struct {
+	store WordStore
+}
+
+func (sm storeMatcher) Has(word string) bool {
+	n := len([]rune(word))
+	for _, m := range sm.store.Match(word) {
+		if m.Start == 0 && m.End == n {
+			return true
+		}
+	}
+	return false
+}
+
+func (sm storeMatcher) Walk(fn func(word string) bool) {
+	sm.store.Iterate(fn)
+}
+
+func (sm storeMatcher) WalkPrefix(prefix string, fn func(word string) bool) {
+	sm.store.Iterate(func(word string) bool {
+		if strings.HasPrefix(word, prefix) {
+			return fn(word)
+		}
+		return true
+	})
+}
+
+func (sm storeMatcher) FindIn(text string) (bool, string) {
+	if all := sm.store.Match(text); len(all) > 0 {
+		return true, all[0].Word
+	}
+	return false, ""
+}
+
+func (sm storeMatcher) Validate(text string) (bool, string) {
+	found, word := sm.FindIn(text)
+	return !found, word
+}
+
+func (sm storeMatcher) ValidateWithWildcard(text string, wildcard rune) (bool, string) {
+	return storeTrie(sm.store).ValidateWithWildcard(text, wildcard)
+}
+
+func (sm storeMatcher) ValidateWithWildcardN(text string, wildcard rune, max int) (bool, string) {
+	return storeTrie(sm.store).ValidateWithWildcardN(text, wildcard, max)
+}
+
+// ReplaceWithPolicy 与Trie一致，MatchDefault替换所有命中(含相互重叠的)
+func (sm storeMatcher) ReplaceWithPolicy(text string, character rune, policy MatchPolicy) string {
+	runes := []rune(text)
+	for _, m := range sm.matchIndex(text, policy, policy == MatchDefault) {
+		for i := m.Start; i < m.End; i++ {
+			runes[i] = character
+		}
+	}
+	return string(runes)
+}
+
+// FilterWithPolicy 与Trie一致，MatchDefault从左到右删除最短的命中
+func (sm storeMatcher) FilterWithPolicy(text string, policy MatchPolicy) string {
+	if policy == MatchDefault {
+		policy = MatchShortest
+	}
+	var (
+		runes  = []rune(text)
+		result = make([]rune, 0, len(runes))
+		left   = 0
+	)
+	for _, m := range sm.matchIndex(text, policy, false) {
+		result = append(result, runes[left:m.Start]...)
+		left = m.End
+	}
+	return string(append(result, runes[left:]...))
+}
+
+// FindAllWithPolicy 与Trie一致，MatchDefault返回所有命中(含相互重叠的)的词
+func (sm storeMatcher) FindAllWithPolicy(text string, policy MatchPolicy) []string {
+	return uniqueWords(sm.matchIndex(text, policy, policy == MatchDefault))
+}
+
+func (sm storeMatcher) FindAllWithIndex(text string, policy MatchPolicy, overlap bool) []Match {
+	matches := sm.matchIndex(text, policy, overlap)
+	setOffsets(text, matches)
+	return matches
+}
+
+func (sm storeMatcher) DumpDOT(w io.Writer, maxDepth int) error {
+	return storeTrie(sm.store).DumpDOT(w, maxDepth)
+}
+
+func (sm storeMatcher) firstRunes() []rune {
+	var (
+		runes []rune
+		set   = make(map[rune]struct{})
+	)
+	sm.store.Iterate(func(word string) bool {
+		for _, r := range word {
+			if _, ok := set[r]; !ok {
+				set[r] = struct{}{}
+				runes = append(runes, r)
+			}
+			break
+		}
+		return true
+	})
+	return runes
+}
+
+func (sm storeMatcher) matchIndex(text string, policy MatchPolicy, overlap bool) []Match {
+	all := sm.store.Match(text)
+	if overlap {
+		return all
+	}
+	return selectMatches(all, policy)
+}
+
+func (sm storeMatcher) matchLimit(text string, policy MatchPolicy, limit int) []Match {
+	matches := sm.matchIndex(text, policy, false)
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func (sm storeMatcher) scanBytes(text []byte, policy MatchPolicy) [][2]int {
+	s := string(text)
+	matches := sm.matchIndex(s, policy, false)
+	setOffsets(s, matches)
+	spans := make([][2]int, 0, len(matches))
+	for _, m := range matches {
+		spans = append(spans, [2]int{m.ByteStart, m.ByteEnd})
+	}
+	return spans
+}
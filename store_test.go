@@ -0,0 +1,89 @@
+package sensitive
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// mapStore 用map保存词、逐个位置比较的WordStore
+type mapStore map[string]struct{}
+
+func (s mapStore) Add(words ...string) {
+	for _, word := range words {
+		s[word] = struct{}{}
+	}
+}
+
+func (s mapStore) Del(words ...string) {
+	for _, word := range words {
+		delete(s, word)
+	}
+}
+
+func (s mapStore) Iterate(fn func(word string) bool) {
+	for word := range s {
+		if !fn(word) {
+			return
+		}
+	}
+}
+
+func (s mapStore) Match(text string) []Match {
+	var (
+		runes   = []rune(text)
+		matches []Match
+	)
+	for i := range runes {
+		rest := string(runes[i:])
+		for word := range s {
+			if strings.HasPrefix(rest, word) {
+				matches = append(matches, Match{Word: word, Start: i, End: i + len([]rune(word))})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End < matches[j].End
+	})
+	return matches
+}
+
+func TestWordStore(t *testing.T) {
+	store := mapStore{}
+	filter := NewWithStore(store)
+	filter.AddWord("垃圾", "垃圾桶", "东西")
+
+	if len(store) != 3 {
+		t.Fatalf("words in store, got %d, expect 3", len(store))
+	}
+	if got := filter.Replace("这垃圾桶里的东西", '*'); got != "这***里的**" {
+		t.Errorf("replace with store, got %s", got)
+	}
+	if found, word := filter.FindIn("好垃圾"); !found || word != "垃圾" {
+		t.Errorf("find in with store, got %v %s", found, word)
+	}
+	if !filter.HasWord("东西") || filter.HasWord("东") {
+		t.Errorf("has with store")
+	}
+
+	frozen := filter.Freeze()
+	filter.DelWord("垃圾桶")
+	if _, ok := store["垃圾桶"]; ok {
+		t.Errorf("del word, still in store")
+	}
+	if got := filter.FindAll("垃圾桶"); !reflect.DeepEqual(got, []string{"垃圾"}) {
+		t.Errorf("find all after del, got %v", got)
+	}
+	if got := frozen.FindAll("垃圾桶"); !reflect.DeepEqual(got, []string{"垃圾", "垃圾桶"}) {
+		t.Errorf("frozen find all, got %v", got)
+	}
+
+	filter.ResetWords("新词")
+	if !reflect.DeepEqual(store, mapStore{"新词": {}}) {
+		t.Errorf("reset words, got %v", store)
+	}
+}
@@ -0,0 +1,128 @@
+// Package stream 从消息队列中消费文本，过滤后将结果写入另一个队列。
+//
+// 本包不依赖具体的消息队列客户端，只定义了Consumer和Producer两个接口。
+// 以Kafka为例，用segmentio/kafka-go时：
+//
+//	type consumer struct{ r *kafka.Reader }
+//
+//	func (c consumer) Fetch(ctx context.Context) (stream.Message, error) {
+//		m, err := c.r.FetchMessage(ctx)
+//		return stream.Message{Key: m.Key, Value: m.Value, Raw: m}, err
+//	}
+//
+//	func (c consumer) Commit(ctx context.Context, m stream.Message) error {
+//		return c.r.CommitMessages(ctx, m.Raw.(kafka.Message))
+//	}
+//
+// Producer同理包装kafka.Writer.WriteMessages即可。
+package stream
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// Message 队列中的一条消息
+type Message struct {
+	Key   []byte
+	Value []byte
+	// Raw 客户端原始的消息，供Commit使用
+	Raw interface{}
+}
+
+// Consumer 消费输入队列
+type Consumer interface {
+	// Fetch 阻塞读取下一条消息，ctx结束时返回错误
+	Fetch(ctx context.Context) (Message, error)
+	// Commit 确认消息已处理完毕
+	Commit(ctx context.Context, msg Message) error
+}
+
+// Producer 写入输出队列
+type Producer interface {
+	Produce(ctx context.Context, msg Message) error
+}
+
+// Result 写入输出队列的处理结果，JSON编码，Key与输入消息相同。
+// 输入被过滤器拒绝(如超过SetMaxTextLen)时Valid为false，Text为空，Reason为原因
+type Result struct {
+	Text   string   `json:"text"`
+	Valid  bool     `json:"valid"`
+	Words  []string `json:"words,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// Processor 逐条处理输入队列中的消息
+type Processor struct {
+	filter *sensitive.Filter
+	in     Consumer
+	out    Producer
+	repl   rune
+	// onlyHits 是否只输出命中了敏感词的消息
+	onlyHits bool
+}
+
+// NewProcessor 返回从in读取消息、用filter过滤后写入out的处理器，
+// 默认用*替换敏感词并输出全部消息
+func NewProcessor(filter *sensitive.Filter, in Consumer, out Producer) *Processor {
+	return &Processor{filter: filter, in: in, out: out, repl: '*'}
+}
+
+// SetReplacement 设置替换敏感词的字符，0表示删除敏感词
+func (p *Processor) SetReplacement(repl rune) {
+	p.repl = repl
+}
+
+// SetOnlyHits 设置是否只输出命中了敏感词的消息，其余消息直接确认
+func (p *Processor) SetOnlyHits(only bool) {
+	p.onlyHits = only
+}
+
+// Run 持续处理消息直到ctx结束或读写队列出错。每条消息在结果写入
+// 输出队列后才确认，因此是至少一次的语义
+func (p *Processor) Run(ctx context.Context) error {
+	for {
+		msg, err := p.in.Fetch(ctx)
+		if err != nil {
+			return err
+		}
+		if err := p.process(ctx, msg); err != nil {
+			return err
+		}
+		if err := p.in.Commit(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *Processor) process(ctx context.Context, msg Message) error {
+	text := string(msg.Value)
+	var result Result
+	if _, err := p.filter.CheckInput(text); err != nil {
+		result.Reason = err.Error()
+	} else {
+		result.Words = p.filter.FindAll(text)
+		result.Valid = len(result.Words) == 0
+	}
+	if result.Valid && p.onlyHits {
+		return nil
+	}
+
+	switch {
+	case result.Reason != "":
+	case result.Valid:
+		result.Text = text
+	case p.repl == 0:
+		result.Text = p.filter.FilterWord(text)
+	default:
+		result.Text = p.filter.Replace(text, p.repl)
+	}
+
+	value, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return p.out.Produce(ctx, Message{Key: msg.Key, Value: value})
+}
@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+type queue struct {
+	messages  []Message
+	committed int
+	produced  []Message
+}
+
+func (q *queue) Fetch(ctx context.Context) (Message, error) {
+	if len(q.messages) == 0 {
+		return Message{}, context.Canceled
+	}
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	return msg, nil
+}
+
+func (q *queue) Commit(ctx context.Context, msg Message) error {
+	q.committed++
+	return nil
+}
+
+func (q *queue) Produce(ctx context.Context, msg Message) error {
+	q.produced = append(q.produced, msg)
+	return nil
+}
+
+func TestProcessor(t *testing.T) {
+	filter := sensitive.New()
+	filter.AddWord("垃圾")
+
+	q := &queue{messages: []Message{
+		{Key: []byte("1"), Value: []byte("真垃圾")},
+		{Key: []byte("2"), Value: []byte("你好")},
+	}}
+	p := NewProcessor(filter, q, q)
+	if err := p.Run(context.Background()); err != context.Canceled {
+		t.Fatalf("got %v", err)
+	}
+	if q.committed != 2 || len(q.produced) != 2 {
+		t.Fatalf("committed %d produced %d", q.committed, len(q.produced))
+	}
+
+	var result Result
+	json.Unmarshal(q.produced[0].Value, &result)
+	want := Result{Text: "真**", Words: []string{"垃圾"}}
+	if string(q.produced[0].Key) != "1" || !reflect.DeepEqual(result, want) {
+		t.Errorf("got %+v", result)
+	}
+
+	q = &queue{messages: []Message{{Value: []byte("你好")}, {Value: []byte("垃圾")}}}
+	p = NewProcessor(filter, q, q)
+	p.SetOnlyHits(true)
+	p.SetReplacement(0)
+	p.Run(context.Background())
+	if q.committed != 2 || len(q.produced) != 1 {
+		t.Fatalf("only hits, committed %d produced %d", q.committed, len(q.produced))
+	}
+	json.Unmarshal(q.produced[0].Value, &result)
+	if result.Text != "" || result.Valid {
+		t.Errorf("only hits, got %+v", result)
+	}
+
+	// 被拒绝的输入视为不合法，不转发原文
+	filter.SetMaxTextLen(6, false)
+	q = &queue{messages: []Message{{Value: []byte("好垃圾")}}}
+	NewProcessor(filter, q, q).Run(context.Background())
+	result = Result{}
+	json.Unmarshal(q.produced[0].Value, &result)
+	if result.Valid || result.Text != "" || result.Reason == "" {
+		t.Errorf("rejected input, got %+v", result)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build tinygo || sensitive_noregexp
+
+package sensitive
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNoRegexpBuild(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	if got := filter.Replace("好 垃 圾", '*'); got != "好 垃 圾" {
+		t.Errorf("replace, got %s", got)
+	}
+	if found, word := filter.FindIn("好 垃 圾"); !found || word != "垃圾" {
+		t.Errorf("find in with rune set noise, got %v %s", found, word)
+	}
+
+	if err := filter.UpdateNoisePattern(`\s`); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("update noise pattern, got %v", err)
+	}
+	if err := filter.AddException("垃圾", "", "桶"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("add exception, got %v", err)
+	}
+	if err := filter.LoadNetWordDict("http://127.0.0.1/dict.txt"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("load net word dict, got %v", err)
+	}
+	if links := FindLinks("https://example.com"); links != nil {
+		t.Errorf("find links, got %v", links)
+	}
+}
@@ -0,0 +1,70 @@
+package sensitive
+
+import "unicode/utf8"
+
+// Tokenizer 分词器，返回的各个词按顺序拼接起来应等于原文，
+// jieba、gse等分词库的Cut方法都满足这一要求
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// TokenizerFunc 将函数适配为Tokenizer
+type TokenizerFunc func(text string) []string
+
+// Tokenize 调用函数本身
+func (fn TokenizerFunc) Tokenize(text string) []string {
+	return fn(text)
+}
+
+// SetTokenizer 设置分词器
+func SetTokenizer(tokenizer Tokenizer) {
+	Default().SetTokenizer(tokenizer)
+}
+
+// SetTokenizer 设置分词器，设置后只保留起止位置都落在词边界上的命中，
+// 避免敏感词横跨两个不相关的词造成误判。分词结果拼接后与原文不一致时
+// 不做限制。传入nil取消
+func (filter *Filter) SetTokenizer(tokenizer Tokenizer) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.configChanged()
+	filter.tokenizer = tokenizer
+}
+
+// boundaries 返回分词结果中所有词边界的rune下标，分词结果与原文不一致时返回nil
+func boundaries(text string, tokens []string) map[int]struct{} {
+	var (
+		set    = map[int]struct{}{0: {}}
+		offset = 0
+		runes  = 0
+	)
+	for _, token := range tokens {
+		if len(text)-offset < len(token) || text[offset:offset+len(token)] != token {
+			return nil
+		}
+		offset += len(token)
+		runes += utf8.RuneCountInString(token)
+		set[runes] = struct{}{}
+	}
+	if offset != len(text) {
+		return nil
+	}
+	return set
+}
+
+// dropCrossToken 去掉起止位置不在词边界上的命中
+func dropCrossToken(matches []Match, text string, tokenizer Tokenizer) []Match {
+	set := boundaries(text, tokenizer.Tokenize(text))
+	if set == nil {
+		return matches
+	}
+	kept := matches[:0]
+	for _, m := range matches {
+		_, start := set[m.Start]
+		_, end := set[m.End]
+		if start && end {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,35 @@
+package sensitive
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	filter := New()
+	filter.AddWord("上海", "海南")
+
+	segments := map[string][]string{
+		"我去上海南站": {"我", "去", "上海", "南站"},
+		"我去海南岛":  {"我", "去", "海南", "岛"},
+	}
+	filter.SetTokenizer(TokenizerFunc(func(text string) []string {
+		if tokens, ok := segments[text]; ok {
+			return tokens
+		}
+		return strings.Split(text, "")
+	}))
+
+	if got := filter.FindAll("我去上海南站"); !reflect.DeepEqual(got, []string{"上海"}) {
+		t.Errorf("findall with tokenizer, got %v, expect [上海]", got)
+	}
+	if got := filter.Replace("我去海南岛", '*'); got != "我去**岛" {
+		t.Errorf("replace with tokenizer, got %s, expect 我去**岛", got)
+	}
+
+	filter.SetTokenizer(TokenizerFunc(func(text string) []string { return []string{"不一致"} }))
+	if got := filter.FindAll("我去上海南站"); !reflect.DeepEqual(got, []string{"上海", "海南"}) {
+		t.Errorf("findall with mismatched tokens, got %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+#!/usr/bin/env python3
+"""Generate gb18030_table.go from Python's gb18030 codec.
+
+Usage: python3 tools/gen_gb18030.py > gb18030_table.go
+"""
+
+
+def decode(b):
+    try:
+        s = b.decode("gb18030")
+    except UnicodeDecodeError:
+        return None
+    return ord(s) if len(s) == 1 else None
+
+
+def main():
+    out = [
+        "// Code generated by tools/gen_gb18030.py. DO NOT EDIT.",
+        "",
+        "package sensitive",
+        "",
+        "// gb18030Double 双字节编码对应的字符，下标为(首字节-0x81)*191+(尾字节-0x40)，",
+        "// 尾字节0x7F及无对应字符的位置为U+FFFD",
+        "const gb18030Double = \"\" +",
+    ]
+    lines = []
+    for lead in range(0x81, 0xFF):
+        row = []
+        for trail in range(0x40, 0xFF):
+            cp = None if trail == 0x7F else decode(bytes([lead, trail]))
+            if cp is None:
+                cp = 0xFFFD
+            assert cp not in (0, 0xFEFF) and cp < 0x10000
+            row.append(chr(cp).replace("\\", "\\\\").replace('"', '\\"'))
+        lines.append('\t"%s"' % "".join(row))
+    out.append(" +\n".join(lines))
+    out.append("")
+
+    out.append("// gb18030Ranges 四字节编码中BMP部分的线性下标区间及其起始字符，")
+    out.append("// 区间内下标与字符一一递增对应")
+    out.append("var gb18030Ranges = [...]struct{ index, end, rune uint16 }{")
+    ranges = []
+    for idx in range(39420):
+        b4 = idx % 10
+        b3 = idx // 10 % 126
+        b2 = idx // 1260 % 10
+        b1 = idx // 12600
+        cp = decode(bytes([0x81 + b1, 0x30 + b2, 0x81 + b3, 0x30 + b4]))
+        if cp is None:
+            continue
+        if ranges and ranges[-1][1] + 1 == idx and ranges[-1][2] + idx - ranges[-1][0] == cp:
+            ranges[-1][1] = idx
+        else:
+            ranges.append([idx, idx, cp])
+    for start, end, cp in ranges:
+        out.append("\t{%d, %d, 0x%04X}," % (start, end, cp))
+    out.append("}")
+    print("\n".join(out))
+
+
+if __name__ == "__main__":
+    main()
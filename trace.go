@@ -0,0 +1,135 @@
+package sensitive
+
+import (
+	"context"
+	"io"
+)
+
+// Tracer 开始一个span，接口与OpenTelemetry的trace.Tracer对应，例如：
+//
+//	type otelTracer struct{ t trace.Tracer }
+//
+//	func (o otelTracer) Start(ctx context.Context, name string) (context.Context, sensitive.Span) {
+//		ctx, span := o.t.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+// 其中otelSpan的SetAttribute调用span.SetAttributes(attribute.Int64(key, value))
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span 一个进行中的span
+type Span interface {
+	SetAttribute(key string, value int64)
+	RecordError(err error)
+	End()
+}
+
+// span的属性名
+const (
+	AttrTextLength = "sensitive.text_length"
+	AttrMatchCount = "sensitive.match_count"
+	AttrVersion    = "sensitive.version"
+)
+
+// SetTracer 设置默认过滤器的Tracer
+func SetTracer(tracer Tracer) {
+	Default().SetTracer(tracer)
+}
+
+// SetTracer 设置LoadContext、FindAllContext和ReplaceContext使用的Tracer，
+// 为nil时不创建span
+func (filter *Filter) SetTracer(tracer Tracer) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.tracer = tracer
+}
+
+// LoadContext 同Load，另外创建名为sensitive.Load的span
+func LoadContext(ctx context.Context, rd io.Reader) error {
+	return Default().LoadContext(ctx, rd)
+}
+
+// LoadContext 同Load，设置了Tracer时创建名为sensitive.Load的span，
+// 记录加载后的词典版本和加载错误
+func (filter *Filter) LoadContext(ctx context.Context, rd io.Reader) error {
+	span := filter.startSpan(ctx, "sensitive.Load")
+	if span == nil {
+		return filter.Load(rd)
+	}
+	defer span.End()
+
+	err := filter.Load(rd)
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.SetAttribute(AttrVersion, int64(filter.Version()))
+	return err
+}
+
+// FindAllContext 同FindAll，另外创建名为sensitive.FindAll的span
+func FindAllContext(ctx context.Context, text string) []string {
+	return Default().FindAllContext(ctx, text)
+}
+
+// FindAllContext 同FindAll，设置了Tracer时创建名为sensitive.FindAll的span，
+// 记录文本长度、命中词数和词典版本
+func (filter *Filter) FindAllContext(ctx context.Context, text string) []string {
+	span := filter.startSpan(ctx, "sensitive.FindAll")
+	if span == nil {
+		return filter.FindAll(text)
+	}
+	defer span.End()
+
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	words := filter.findAll(text)
+	filter.count(text, len(words) > 0)
+	setSpanAttributes(span, text, len(words), filter.version)
+	return words
+}
+
+// ReplaceContext 同Replace，另外创建名为sensitive.Replace的span
+func ReplaceContext(ctx context.Context, text string, repl rune) string {
+	return Default().ReplaceContext(ctx, text, repl)
+}
+
+// ReplaceContext 同Replace，设置了Tracer时创建名为sensitive.Replace的span，
+// 记录文本长度、命中个数和词典版本。为了得到命中个数会额外查找一次
+func (filter *Filter) ReplaceContext(ctx context.Context, text string, repl rune) string {
+	span := filter.startSpan(ctx, "sensitive.Replace")
+	if span == nil {
+		return filter.Replace(text, repl)
+	}
+	defer span.End()
+
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	result := filter.replace(text, repl)
+	filter.count(text, result != text)
+	matches := 0
+	if result != text {
+		matches = len(filter.findAllWithIndex(text))
+	}
+	setSpanAttributes(span, text, matches, filter.version)
+	return result
+}
+
+// startSpan 设置了Tracer时开始一个span，否则返回nil
+func (filter *Filter) startSpan(ctx context.Context, name string) Span {
+	filter.mu.RLock()
+	tracer := filter.tracer
+	filter.mu.RUnlock()
+	if tracer == nil {
+		return nil
+	}
+	_, span := tracer.Start(ctx, name)
+	return span
+}
+
+func setSpanAttributes(span Span, text string, matches int, version uint64) {
+	span.SetAttribute(AttrTextLength, int64(len(text)))
+	span.SetAttribute(AttrMatchCount, int64(matches))
+	span.SetAttribute(AttrVersion, int64(version))
+}
@@ -0,0 +1,65 @@
+package sensitive
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]int64
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value int64) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)                { s.err = err }
+func (s *testSpan) End()                                 { s.ended = true }
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attrs: make(map[string]int64)}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestTracer(t *testing.T) {
+	filter := New()
+	ctx := context.Background()
+
+	// 没有设置Tracer时直接调用
+	filter.LoadContext(ctx, strings.NewReader("色情\n"))
+
+	tracer := &testTracer{}
+	filter.SetTracer(tracer)
+	filter.LoadContext(ctx, strings.NewReader("赌博\n"))
+	if words := filter.FindAllContext(ctx, "色情和赌博"); len(words) != 2 {
+		t.Errorf("find all, got %v", words)
+	}
+	if got := filter.ReplaceContext(ctx, "色情和赌博", '*'); got != "**和**" {
+		t.Errorf("replace, got %s", got)
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("got %d spans", len(tracer.spans))
+	}
+	for i, name := range []string{"sensitive.Load", "sensitive.FindAll", "sensitive.Replace"} {
+		if span := tracer.spans[i]; span.name != name || !span.ended {
+			t.Errorf("span %d, got %+v", i, span)
+		}
+	}
+	if got := tracer.spans[0].attrs; !reflect.DeepEqual(got, map[string]int64{AttrVersion: 2}) {
+		t.Errorf("load attributes, got %v", got)
+	}
+	want := map[string]int64{AttrTextLength: int64(len("色情和赌博")), AttrMatchCount: 2, AttrVersion: 2}
+	for _, span := range tracer.spans[1:] {
+		if !reflect.DeepEqual(span.attrs, want) {
+			t.Errorf("%s attributes, got %v", span.name, span.attrs)
+		}
+	}
+}
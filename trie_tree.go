@@ -1,5 +1,7 @@
 package sensitive
 
+import "sort"
+
 // Trie 短语组成的Trie树.
 type Trie struct {
 	Root *Node
@@ -68,161 +70,37 @@ func (tree *Trie) del(word string) {
 	}
 }
 
+// Has 判断word是否为树上的一个完整的词
+func (tree *Trie) Has(word string) bool {
+	return hasWord[*Node](tree, word)
+}
+
 // Replace 词语替换
 func (tree *Trie) Replace(text string, character rune) string {
-	var (
-		parent  = tree.Root
-		current *Node
-		runes   = []rune(text)
-		length  = len(runes)
-		left    = 0
-		found   bool
-	)
-
-	for position := 0; position < len(runes); position++ {
-		current, found = parent.Children[runes[position]]
-
-		if !found || (!current.IsPathEnd() && position == length-1) {
-			parent = tree.Root
-			position = left
-			left++
-			continue
-		}
-
-		// println(string(current.Character), current.IsPathEnd(), left)
-		if current.IsPathEnd() && left <= position {
-			for i := left; i <= position; i++ {
-				runes[i] = character
-			}
-		}
-
-		parent = current
-	}
-
+	runes := []rune(text)
+	replaceRunes[*Node](tree, runes, character)
 	return string(runes)
 }
 
 // Filter 直接过滤掉字符串中的敏感词
 func (tree *Trie) Filter(text string) string {
-	var (
-		parent      = tree.Root
-		current     *Node
-		left        = 0
-		found       bool
-		runes       = []rune(text)
-		length      = len(runes)
-		resultRunes = make([]rune, 0, length)
-	)
-
-	for position := 0; position < length; position++ {
-		current, found = parent.Children[runes[position]]
-
-		if !found || (!current.IsPathEnd() && position == length-1) {
-			resultRunes = append(resultRunes, runes[left])
-			parent = tree.Root
-			position = left
-			left++
-			continue
-		}
-
-		if current.IsPathEnd() {
-			left = position + 1
-			parent = tree.Root
-		} else {
-			parent = current
-		}
-
-	}
-
-	resultRunes = append(resultRunes, runes[left:]...)
-	return string(resultRunes)
+	return string(filterRunes[*Node](tree, nil, []rune(text)))
 }
 
 // Validate 验证字符串是否合法，如不合法则返回false和检测到
 // 的第一个敏感词
 func (tree *Trie) Validate(text string) (bool, string) {
-	const (
-		Empty = ""
-	)
-	var (
-		parent  = tree.Root
-		current *Node
-		runes   = []rune(text)
-		length  = len(runes)
-		left    = 0
-		found   bool
-	)
-
-	for position := 0; position < len(runes); position++ {
-		current, found = parent.Children[runes[position]]
-
-		if !found || (!current.IsPathEnd() && position == length-1) {
-			parent = tree.Root
-			position = left
-			left++
-			continue
-		}
-
-		if current.IsPathEnd() && left <= position {
-			return false, string(runes[left : position+1])
-		}
-
-		parent = current
-	}
-
-	return true, Empty
+	return validateRunes[*Node](tree, []rune(text))
 }
 
+// ValidateWithWildcard 验证字符串是否合法，词库中的wildcard字符可匹配任意一个字符
 func (tree *Trie) ValidateWithWildcard(text string, wildcard rune) (bool, string) {
-
-	runes := []rune(text)
-
-	for curl := 0; curl < len(runes); curl++ {
-
-		patter := ""
-		parent := tree.Root
-		if tree.dfs(runes, parent, curl, wildcard, "", &patter) {
-			return false, patter
-		}
-	}
-	return true, ""
+	return validateWithWildcard[*Node](tree, []rune(text), wildcard)
 }
 
-func (tree *Trie) dfs(runes []rune, parent *Node, curl int, wildcard rune, str string, patter *string) bool {
-
-	if parent == nil {
-		return false
-	}
-	if parent.IsPathEnd() {
-		*patter = str
-		return true
-	}
-	if curl >= len(runes) {
-		return false
-	}
-
-	// 匹配到了
-	if current, found := parent.Children[runes[curl]]; found {
-		if is1 := tree.dfs(runes, current, curl+1, wildcard, str+string(runes[curl]), patter); is1 {
-			return true
-		}
-	}
-
-	// 先看有没有*
-	if current1, found1 := parent.Children[wildcard]; found1 {
-
-		if is2 := tree.dfs(runes, current1, curl+1, wildcard, str+string(wildcard), patter); is2 {
-			return true
-		}
-
-		if current2, found2 := current1.Children[runes[curl]]; found2 {
-			if is3 := tree.dfs(runes, current2, curl+1, wildcard, str+string(wildcard)+string(runes[curl]), patter); is3 {
-				return true
-			}
-		}
-	}
-	return false
-
+// ValidateWithWildcardN 同ValidateWithWildcard，词库中的wildcard字符可匹配0到max个任意字符
+func (tree *Trie) ValidateWithWildcardN(text string, wildcard rune, max int) (bool, string) {
+	return validateWithWildcardN[*Node](tree, []rune(text), wildcard, max)
 }
 
 // FindIn 判断text中是否含有词库中的词
@@ -233,57 +111,57 @@ func (tree *Trie) FindIn(text string) (bool, string) {
 
 // FindAll 找有所有包含在词库中的词
 func (tree *Trie) FindAll(text string) []string {
-	var matches []string
-	var (
-		parent  = tree.Root
-		current *Node
-		runes   = []rune(text)
-		length  = len(runes)
-		left    = 0
-		found   bool
-	)
-
-	for position := 0; position < length; position++ {
-		current, found = parent.Children[runes[position]]
-
-		if !found {
-			parent = tree.Root
-			position = left
-			left++
-			continue
-		}
+	return findAllRunes[*Node](tree, []rune(text))
+}
 
-		if current.IsPathEnd() && left <= position {
-			matches = append(matches, string(runes[left:position+1]))
-		}
+// ReplaceWithPolicy 按匹配策略替换词语
+func (tree *Trie) ReplaceWithPolicy(text string, character rune, policy MatchPolicy) string {
+	return replaceWithPolicy[*Node](tree, text, character, policy)
+}
 
-		if position == length-1 {
-			parent = tree.Root
-			position = left
-			left++
-			continue
-		}
+// FilterWithPolicy 按匹配策略过滤词语
+func (tree *Trie) FilterWithPolicy(text string, policy MatchPolicy) string {
+	return filterWithPolicy[*Node](tree, text, policy)
+}
 
-		parent = current
-	}
+// FindAllWithPolicy 按匹配策略找出所有词语，结果去重
+func (tree *Trie) FindAllWithPolicy(text string, policy MatchPolicy) []string {
+	return findAllWithPolicy[*Node](tree, text, policy)
+}
 
-	var i = 0
-	if count := len(matches); count > 0 {
-		set := make(map[string]struct{})
-		for i < count {
-			_, ok := set[matches[i]]
-			if !ok {
-				set[matches[i]] = struct{}{}
-				i++
-				continue
-			}
-			count--
-			copy(matches[i:], matches[i+1:])
-		}
-		return matches[:count]
-	}
+// FindAllWithIndex 找出所有命中词及其位置。overlap为false时按策略从左到右
+// 取互不重叠的命中(MatchDefault按最长匹配处理)；为true时报告所有位置上
+// 的全部命中，包括相互重叠的词
+func (tree *Trie) FindAllWithIndex(text string, policy MatchPolicy, overlap bool) []Match {
+	matches := tree.matchIndex(text, policy, overlap)
+	setOffsets(text, matches)
+	return matches
+}
+
+// matchIndex FindAllWithIndex的实现，只计算rune下标
+func (tree *Trie) matchIndex(text string, policy MatchPolicy, overlap bool) []Match {
+	return findAllWithIndex[*Node](tree, text, policy, overlap)
+}
+
+func (tree *Trie) matchLimit(text string, policy MatchPolicy, limit int) []Match {
+	return matchLimit[*Node](tree, text, policy, limit)
+}
+
+func (tree *Trie) scanBytes(text []byte, policy MatchPolicy) [][2]int {
+	return scanBytes[*Node](tree, text, policy)
+}
+
+func (tree *Trie) start() *Node {
+	return tree.Root
+}
 
-	return nil
+func (tree *Trie) next(node *Node, r rune) (*Node, bool) {
+	next, ok := node.Children[r]
+	return next, ok
+}
+
+func (tree *Trie) end(node *Node) bool {
+	return node.IsPathEnd()
 }
 
 // NewNode 新建子节点
@@ -322,3 +200,49 @@ func (node *Node) IsPathEnd() bool {
 func (node *Node) SoftDel() {
 	node.isPathEnd = false
 }
+
+// MatchPolicy 同一位置命中多个词时的取舍策略
+type MatchPolicy int
+
+const (
+	// MatchDefault 保持各方法原有的匹配行为
+	MatchDefault MatchPolicy = iota
+	// MatchLongest 取同一位置上最长的词
+	MatchLongest
+	// MatchShortest 取同一位置上最短的词
+	MatchShortest
+)
+
+// Walk 按字典序遍历树上的所有词，fn返回false时停止
+func (tree *Trie) Walk(fn func(word string) bool) {
+	walkNode(tree.Root, nil, fn)
+}
+
+// WalkPrefix 按字典序遍历所有以prefix开头的词，fn返回false时停止
+func (tree *Trie) WalkPrefix(prefix string, fn func(word string) bool) {
+	if node, ok := descend[*Node](tree, prefix); ok {
+		walkNode(node, []rune(prefix), fn)
+	}
+}
+
+func walkNode(node *Node, prefix []rune, fn func(word string) bool) bool {
+	if node.IsPathEnd() && !fn(string(prefix)) {
+		return false
+	}
+
+	for _, r := range sortedKeys(node.Children) {
+		if !walkNode(node.Children[r], append(prefix, r), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(children map[rune]*Node) []rune {
+	keys := make([]rune, 0, len(children))
+	for r := range children {
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,33 @@
+package sensitive
+
+import "testing"
+
+func TestTryAddDelWord(t *testing.T) {
+	filter := New()
+	filter.SetMaxWordLength(4)
+
+	testcases := []struct {
+		Word          string
+		ExpectExisted bool
+		ExpectErr     error
+	}{
+		{"", false, ErrEmptyWord},
+		{" \t", false, ErrBlankWord},
+		{"一二三四五", false, ErrWordTooLong},
+		{"垃圾", false, nil},
+		{"垃圾", true, nil},
+	}
+
+	for _, tc := range testcases {
+		if existed, err := filter.TryAddWord(tc.Word); existed != tc.ExpectExisted || err != tc.ExpectErr {
+			t.Errorf("try add %q, got %v %v, expect %v %v", tc.Word, existed, err, tc.ExpectExisted, tc.ExpectErr)
+		}
+	}
+
+	if existed, err := filter.TryDelWord("垃圾"); !existed || err != nil {
+		t.Errorf("try del existing word, got %v %v", existed, err)
+	}
+	if existed, err := filter.TryDelWord("垃圾"); existed || err != nil {
+		t.Errorf("try del missing word, got %v %v", existed, err)
+	}
+}
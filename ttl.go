@@ -0,0 +1,80 @@
+package sensitive
+
+import "time"
+
+// AddWordTTL 添加一个在ttl后自动失效的敏感词
+func AddWordTTL(word string, ttl time.Duration) {
+	Default().AddWordTTL(word, ttl)
+}
+
+// AddWordTTL 添加一个在ttl后自动失效的敏感词，适用于只在活动期间敏感的词。
+// 到期后由后台定时器删除；期间再用AddWord添加同一个词会使其变为永久有效，
+// 用DelWord删除则同时取消定时。被拦截器拒绝时不做修改
+func (filter *Filter) AddWordTTL(word string, ttl time.Duration) {
+	if _, err := filter.intercept(MutationAdd, []string{word}); err != nil {
+		return
+	}
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	filter.addWords(word)
+	filter.commit()
+	if filter.deadlines == nil {
+		filter.deadlines = make(map[string]time.Time)
+	}
+	filter.deadlines[word] = time.Now().Add(ttl)
+	filter.resetTimer()
+}
+
+// resetTimer 按最早的过期时间或生效时间边界设置定时器，调用方需持有写锁
+func (filter *Filter) resetTimer() {
+	next := filter.nextTransition(time.Now())
+	for _, deadline := range filter.deadlines {
+		if next.IsZero() || deadline.Before(next) {
+			next = deadline
+		}
+	}
+	if next.IsZero() {
+		if filter.timer != nil {
+			filter.timer.Stop()
+		}
+		return
+	}
+
+	delay := time.Until(next)
+	if filter.timer == nil {
+		filter.timer = time.AfterFunc(delay, filter.onTimer)
+	} else {
+		filter.timer.Reset(delay)
+	}
+}
+
+// stopIdleTimer 没有带有效期或生效时间的词时停止定时器，调用方需持有写锁
+func (filter *Filter) stopIdleTimer() {
+	if filter.timer != nil && len(filter.deadlines) == 0 && len(filter.schedules) == 0 {
+		filter.timer.Stop()
+	}
+}
+
+// onTimer 删除所有已过期的词，并按当前时间启用或停用设置了生效时间的词
+func (filter *Filter) onTimer() {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	now := time.Now()
+	var expired []string
+	for word, deadline := range filter.deadlines {
+		if !deadline.After(now) {
+			expired = append(expired, word)
+		}
+	}
+	changed := filter.applySchedules(now)
+	if len(expired) > 0 {
+		filter.delWords(expired...)
+		changed = true
+	}
+	if changed {
+		filter.commit()
+	}
+	filter.resetTimer()
+}
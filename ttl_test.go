@@ -0,0 +1,26 @@
+package sensitive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddWordTTL(t *testing.T) {
+	filter := New()
+	filter.AddWordTTL("活动", 20*time.Millisecond)
+	filter.AddWordTTL("永久", 20*time.Millisecond)
+	filter.AddWord("永久")
+
+	if got := filter.Replace("活动永久", '*'); got != "****" {
+		t.Errorf("replace before expiry, got %s, expect ****", got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if filter.Replace("活动永久", '*') == "活动**" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("replace after expiry, got %s, expect 活动**", filter.Replace("活动永久", '*'))
+}
@@ -0,0 +1,48 @@
+package sensitive
+
+// DefaultUsernameSeparators ValidateUsername默认忽略的分隔字符
+const DefaultUsernameSeparators = "_-. "
+
+// SetUsernameSeparators 设置默认过滤器检查用户名时忽略的分隔字符
+func SetUsernameSeparators(chars string) {
+	Default().SetUsernameSeparators(chars)
+}
+
+// SetUsernameSeparators 设置ValidateUsername忽略的分隔字符，为空时不忽略任何字符。
+// 只影响ValidateUsername，与噪音设置相互独立
+func (filter *Filter) SetUsernameSeparators(chars string) {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	filter.usernameSeps = newRuneSet(chars)
+}
+
+// ValidateUsername 使用默认过滤器检查用户名
+func ValidateUsername(name string) (bool, string) {
+	return Default().ValidateUsername(name)
+}
+
+// ValidateUsername 检查用户名、ID等标识符是否包含敏感词，如不合法则返回false
+// 和检测到的第一个敏感词。按子串匹配：不区分大小写，折叠全角字符，忽略
+// SetUsernameSeparators设置的分隔字符，因此"xx_Fuck_123"、"f.u.c.k"均不合法。
+// 不使用噪音、分词和例外等针对正文的设置
+func (filter *Filter) ValidateUsername(name string) (bool, string) {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	valid, word := filter.validateUsername(name)
+	filter.count(name, !valid)
+	return valid, word
+}
+
+// validateUsername ValidateUsername的实现，调用方需持有锁
+func (filter *Filter) validateUsername(name string) (bool, string) {
+	name, err := filter.input(name)
+	if err != nil {
+		return false, ""
+	}
+	normalizers := []Normalizer{WidthFolder, CaseFolder}
+	if seps := filter.usernameSeps; seps != nil {
+		normalizers = append(normalizers, RuneRemover(seps.has))
+	}
+	runes, _ := normalize(name, normalizers)
+	return filter.matcher().Validate(string(runes))
+}
@@ -0,0 +1,49 @@
+package sensitive
+
+import "testing"
+
+func TestValidateUsername(t *testing.T) {
+	filter := New()
+	filter.AddWord("fuck", "傻逼")
+	// 按字母、数字的连续片段分词
+	filter.SetTokenizer(TokenizerFunc(func(text string) []string {
+		var tokens []string
+		for i := 0; i < len(text); {
+			j := i + 1
+			for j < len(text) && (text[j] >= '0' && text[j] <= '9') == (text[i] >= '0' && text[i] <= '9') {
+				j++
+			}
+			tokens = append(tokens, text[i:j])
+			i = j
+		}
+		return tokens
+	}))
+
+	testcases := []struct {
+		Name  string
+		Valid bool
+		Word  string
+	}{
+		{"xx_Fuck_123", false, "fuck"},
+		{"f.u.c.k", false, "fuck"},
+		{"ＦＵＣＫ", false, "fuck"},
+		{"傻_逼2024", false, "傻逼"},
+		{"lucky_duck", true, ""},
+	}
+	for _, tc := range testcases {
+		valid, word := filter.ValidateUsername(tc.Name)
+		if valid != tc.Valid || word != tc.Word {
+			t.Errorf("validate username %s, got %v %s, expect %v %s", tc.Name, valid, word, tc.Valid, tc.Word)
+		}
+	}
+
+	// 分词只影响正文查询
+	if valid, _ := filter.Validate("xxfuck123"); !valid {
+		t.Errorf("validate text with tokenizer, got invalid")
+	}
+
+	filter.SetUsernameSeparators("")
+	if valid, _ := filter.ValidateUsername("f.u.c.k"); !valid {
+		t.Errorf("validate username without separators, got invalid")
+	}
+}
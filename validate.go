@@ -0,0 +1,94 @@
+package sensitive
+
+// ValidationStatus ValidateN的检查结论
+type ValidationStatus int
+
+const (
+	// ValidationClean 没有敏感词
+	ValidationClean ValidationStatus = iota
+	// ValidationViolations 含有敏感词
+	ValidationViolations
+	// ValidationRejected 输入本身不可接受，没有检查内容，原因见ValidationResult.Err
+	ValidationRejected
+)
+
+func (s ValidationStatus) String() string {
+	switch s {
+	case ValidationViolations:
+		return "violations"
+	case ValidationRejected:
+		return "rejected"
+	}
+	return "clean"
+}
+
+// ValidationResult ValidateN的结果
+type ValidationResult struct {
+	Status ValidationStatus
+	// Words 按出现顺序排列的命中词，同一个词出现多次时重复记录
+	Words []string
+	// Err 输入被拒绝的原因
+	Err error
+	// Truncated 输入超过SetMaxTextLen的上限，只检查了截断后的部分
+	Truncated bool
+}
+
+// OK 是否没有敏感词且输入未被拒绝
+func (r ValidationResult) OK() bool {
+	return r.Status == ValidationClean
+}
+
+// ValidateN 使用默认过滤器检查text，最多收集n个命中
+func ValidateN(text string, n int) ValidationResult {
+	return Default().ValidateN(text, n)
+}
+
+// ValidateN 检查text并区分无敏感词、含敏感词和输入被拒绝三种结论，
+// 收集到n个命中后立即停止扫描，n不大于0时收集全部命中。命中按匹配策略
+// 从左到右选取、互不重叠，与Validate一样先去除噪音。只需判断是否合法时
+// n取1，开销与Validate相同
+func (filter *Filter) ValidateN(text string, n int) ValidationResult {
+	filter.mu.RLock()
+	defer filter.mu.RUnlock()
+	result := filter.validateN(text, n)
+	if result.Status != ValidationRejected {
+		filter.count(text, result.Status == ValidationViolations)
+	}
+	return result
+}
+
+// validateN ValidateN的实现，调用方需持有锁
+func (filter *Filter) validateN(text string, n int) ValidationResult {
+	truncated, _ := filter.checkInput(text)
+	text, err := filter.input(text)
+	if err != nil {
+		return ValidationResult{Status: ValidationRejected, Err: err}
+	}
+	text = filter.removeNoise(text)
+	if filter.skip(text) {
+		return ValidationResult{Truncated: truncated}
+	}
+
+	matches := filter.limitedMatches(text, n)
+	if len(matches) == 0 {
+		return ValidationResult{Truncated: truncated}
+	}
+	words := make([]string, len(matches))
+	for i, m := range matches {
+		words[i] = m.Word
+	}
+	return ValidationResult{Status: ValidationViolations, Words: words, Truncated: truncated}
+}
+
+// limitedMatches 按匹配策略从左到右返回text中前n个互不重叠的有效命中，
+// n不大于0时不限制。不需要逐个检查命中时找到n个即停止扫描，调用方需持有锁
+func (filter *Filter) limitedMatches(text string, n int) []Match {
+	if !filter.spanMode() {
+		return filter.matcher().matchLimit(text, filter.policy, n)
+	}
+	matches := filter.matches(text)
+	if n > 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
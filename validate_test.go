@@ -0,0 +1,37 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateN(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾", "东西", "坏")
+
+	text := "垃圾东西真坏，垃圾"
+	tests := []struct {
+		n      int
+		expect []string
+	}{
+		{1, []string{"垃圾"}},
+		{2, []string{"垃圾", "东西"}},
+		{0, []string{"垃圾", "东西", "坏", "垃圾"}},
+	}
+	for _, tt := range tests {
+		got := filter.ValidateN(text, tt.n)
+		if got.Status != ValidationViolations || got.OK() || !reflect.DeepEqual(got.Words, tt.expect) {
+			t.Errorf("validate %d, got %v %v, expect %v", tt.n, got.Status, got.Words, tt.expect)
+		}
+	}
+
+	if got := filter.ValidateN("干净的内容", 3); !got.OK() || got.Status.String() != "clean" || got.Words != nil {
+		t.Errorf("validate clean text, got %+v", got)
+	}
+
+	// 逐个检查命中时结果相同
+	filter.SetSkipLinks(true)
+	if got := filter.ValidateN(text, 2); !reflect.DeepEqual(got.Words, []string{"垃圾", "东西"}) {
+		t.Errorf("validate in span mode, got %v", got.Words)
+	}
+}
@@ -0,0 +1,135 @@
+// Package wal 词典修改的预写日志。
+//
+// 通过Log修改词典时，每次修改先以Entry的形式追加到Sink，写入成功后才应用到
+// 过滤器，日志中因此记录了谁在什么时候增删了哪些词，可用于审计；
+// 启动时用Replay重放日志即可恢复词典，也可以将日志发送给其他实例重放。
+// 直接调用Filter的方法所做的修改不经过Log，不会被记录。
+package wal
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+// Entry 一次词典修改
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor,omitempty"`
+	Added   []string  `json:"added,omitempty"`
+	Removed []string  `json:"removed,omitempty"`
+}
+
+// Apply 将修改应用到filter上
+func (e Entry) Apply(filter *sensitive.Filter) {
+	filter.ApplyDelta(e.Added, e.Removed)
+}
+
+// Sink 日志的存储，Append返回前应保证entry已持久化
+type Sink interface {
+	Append(entry Entry) error
+}
+
+// SinkFunc 将函数适配为Sink
+type SinkFunc func(entry Entry) error
+
+// Append 调用函数本身
+func (fn SinkFunc) Append(entry Entry) error {
+	return fn(entry)
+}
+
+// writerSink 每条日志写为一行JSON
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink 返回将日志逐行以JSON写入w的Sink，w为*os.File等实现了
+// Sync() error的类型时每条日志写入后都调用Sync
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Append(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if syncer, ok := s.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// Log 记录修改日志的词典写入入口
+type Log struct {
+	filter *sensitive.Filter
+	sink   Sink
+	// mu 保证日志的顺序与应用到filter的顺序一致
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// New 返回修改filter并将修改写入sink的Log
+func New(filter *sensitive.Filter, sink Sink) *Log {
+	return &Log{filter: filter, sink: sink, now: time.Now}
+}
+
+// AddWord 以actor的身份添加词语
+func (l *Log) AddWord(actor string, words ...string) error {
+	return l.ApplyDelta(actor, words, nil)
+}
+
+// DelWord 以actor的身份删除词语
+func (l *Log) DelWord(actor string, words ...string) error {
+	return l.ApplyDelta(actor, nil, words)
+}
+
+// ApplyDelta 以actor的身份原子地删除removed并添加added。
+// 先写日志，写入失败时不修改词典并返回错误
+func (l *Log) ApplyDelta(actor string, added, removed []string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := Entry{Time: l.now(), Actor: actor, Added: added, Removed: removed}
+	if err := l.sink.Append(entry); err != nil {
+		return err
+	}
+	entry.Apply(l.filter)
+	return nil
+}
+
+// Replay 按顺序将r中逐行JSON格式的日志应用到filter，返回应用的条数。
+// 日志末尾不完整的一行(如写入时进程崩溃)被忽略
+func Replay(filter *sensitive.Filter, r io.Reader) (int, error) {
+	var (
+		reader = bufio.NewReader(r)
+		n      = 0
+	)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return n, err
+		}
+		entry.Apply(filter)
+		n++
+	}
+}
@@ -0,0 +1,64 @@
+package wal
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterchanxyz/sensitive"
+)
+
+func TestLog(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		filter = sensitive.New()
+		log    = New(filter, NewWriterSink(&buf))
+		now    = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	)
+	log.now = func() time.Time { return now }
+
+	if err := log.AddWord("alice", "色情", "赌博"); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.DelWord("bob", "色情"); err != nil {
+		t.Fatal(err)
+	}
+	if words := filter.WordsWithPrefix("", 0); !reflect.DeepEqual(words, []string{"赌博"}) {
+		t.Errorf("words after log, got %v", words)
+	}
+
+	expect := `{"time":"2024-01-02T03:04:05Z","actor":"alice","added":["色情","赌博"]}
+{"time":"2024-01-02T03:04:05Z","actor":"bob","removed":["色情"]}
+`
+	if buf.String() != expect {
+		t.Errorf("log content, got %s, expect %s", buf.String(), expect)
+	}
+
+	// 重放日志，末尾不完整的一行被忽略
+	replayed := sensitive.New()
+	n, err := Replay(replayed, strings.NewReader(buf.String()+`{"time":`))
+	if err != nil || n != 2 {
+		t.Errorf("replay, got %d %v", n, err)
+	}
+	if words := replayed.WordsWithPrefix("", 0); !reflect.DeepEqual(words, []string{"赌博"}) {
+		t.Errorf("words after replay, got %v", words)
+	}
+
+	if _, err := Replay(replayed, strings.NewReader("bad\n")); err == nil {
+		t.Errorf("replay invalid line should fail")
+	}
+}
+
+func TestLogSinkError(t *testing.T) {
+	filter := sensitive.New()
+	log := New(filter, SinkFunc(func(Entry) error { return errors.New("disk full") }))
+	if err := log.AddWord("alice", "色情"); err == nil {
+		t.Errorf("add word with failing sink should fail")
+	}
+	if filter.HasWord("色情") {
+		t.Errorf("word should not be added when the log fails")
+	}
+}
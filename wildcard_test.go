@@ -0,0 +1,32 @@
+package sensitive
+
+import "testing"
+
+func TestValidateWithWildcardN(t *testing.T) {
+	filter := New()
+	filter.AddWord("傻*逼", "加*信")
+
+	testcases := []struct {
+		Text  string
+		Valid bool
+		Word  string
+	}{
+		{"你个傻逼", false, "傻*逼"},
+		{"你个傻**逼", false, "傻*逼"},
+		{"你个傻abc逼", false, "傻*逼"},
+		{"你个傻abcd逼", true, ""},
+		{"快加我的好微信", true, ""},
+		{"快加微信", false, "加*信"},
+	}
+	for _, tc := range testcases {
+		valid, word := filter.ValidateWithWildcardN(tc.Text, '*', 3)
+		if valid != tc.Valid || word != tc.Word {
+			t.Errorf("validate with wildcard %s, got %v %s, expect %v %s", tc.Text, valid, word, tc.Valid, tc.Word)
+		}
+	}
+
+	filter.Compile()
+	if valid, word := filter.ValidateWithWildcardN("傻1逼", '*', 1); valid || word != "傻*逼" {
+		t.Errorf("compiled validate with wildcard, got %v %s", valid, word)
+	}
+}
@@ -0,0 +1,135 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrPoolClosed 工作池已关闭
+var ErrPoolClosed = errors.New("sensitive: pool closed")
+
+// Job 提交给工作池的一段文本
+type Job struct {
+	// ID 由调用方设置，原样带回结果中，用于对应请求
+	ID   int64
+	Text string
+	// Repl 替换字符，为0时使用'*'
+	Repl rune
+}
+
+// JobResult 一个Job的处理结果
+type JobResult struct {
+	ID int64
+	// Text 和谐后的文本
+	Text    string
+	Matches []Match
+}
+
+// Pool 异步处理文本的工作池。Job经有界队列交给固定数量的goroutine，
+// 用FilterWithDetails处理后交给回调或结果通道
+type Pool struct {
+	filter  *Filter
+	jobs    chan Job
+	results chan JobResult
+	handler func(JobResult)
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewPool 启动workers个goroutine处理队列长度为queueSize的工作池。
+// handler不为nil时每个结果交给handler(在工作goroutine中调用)，
+// 否则发送到Results返回的通道，调用方必须持续读取
+func NewPool(filter *Filter, workers, queueSize int, handler func(JobResult)) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	p := &Pool{
+		filter:  filter,
+		jobs:    make(chan Job, queueSize),
+		handler: handler,
+	}
+	if handler == nil {
+		p.results = make(chan JobResult, queueSize)
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		repl := job.Repl
+		if repl == 0 {
+			repl = '*'
+		}
+		text, matches := p.filter.FilterWithDetails(job.Text, repl)
+		result := JobResult{ID: job.ID, Text: text, Matches: matches}
+		if p.handler != nil {
+			p.handler(result)
+		} else {
+			p.results <- result
+		}
+	}
+}
+
+// Submit 提交一个Job，队列已满时阻塞直到有空位或ctx结束
+func (p *Pool) Submit(ctx context.Context, job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit 提交一个Job，队列已满或工作池已关闭时立即返回false
+func (p *Pool) TrySubmit(job Job) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// Results 返回结果通道，NewPool时设置了handler则为nil。
+// Close处理完全部Job后关闭该通道
+func (p *Pool) Results() <-chan JobResult {
+	return p.results
+}
+
+// Close 停止接收新Job，等待已提交的Job全部处理完成后返回
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.jobs)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	if p.results != nil {
+		close(p.results)
+	}
+}
@@ -0,0 +1,85 @@
+package sensitive
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestPoolHandler(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+
+	var (
+		mu      sync.Mutex
+		results = make(map[int64]JobResult)
+	)
+	p := NewPool(filter, 4, 8, func(r JobResult) {
+		mu.Lock()
+		results[r.ID] = r
+		mu.Unlock()
+	})
+	for i := int64(0); i < 100; i++ {
+		if err := p.Submit(context.Background(), Job{ID: i, Text: "真垃圾"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.Close()
+
+	if len(results) != 100 {
+		t.Fatalf("got %d results, expect 100", len(results))
+	}
+	if r := results[7]; r.Text != "真**" || len(r.Matches) != 1 {
+		t.Errorf("got %+v", r)
+	}
+	if err := p.Submit(context.Background(), Job{Text: "x"}); err != ErrPoolClosed {
+		t.Errorf("submit after close, got %v", err)
+	}
+	if p.TrySubmit(Job{Text: "x"}) {
+		t.Errorf("try submit after close should fail")
+	}
+}
+
+func TestPoolResults(t *testing.T) {
+	filter := New()
+	filter.AddWord("垃圾")
+	p := NewPool(filter, 2, 2, nil)
+
+	go func() {
+		for i := int64(0); i < 10; i++ {
+			p.Submit(context.Background(), Job{ID: i, Text: "垃圾", Repl: '#'})
+		}
+		p.Close()
+	}()
+
+	n := 0
+	for r := range p.Results() {
+		if r.Text != "##" {
+			t.Errorf("got %s", r.Text)
+		}
+		n++
+	}
+	if n != 10 {
+		t.Errorf("got %d results, expect 10", n)
+	}
+}
+
+func TestPoolSubmitCanceled(t *testing.T) {
+	filter := New()
+	block := make(chan struct{})
+	p := NewPool(filter, 1, 0, func(JobResult) { <-block })
+	defer func() {
+		close(block)
+		p.Close()
+	}()
+
+	p.Submit(context.Background(), Job{Text: "a"})
+	if p.TrySubmit(Job{Text: "b"}) {
+		t.Errorf("full queue should reject")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Submit(ctx, Job{Text: "c"}); err != context.Canceled {
+		t.Errorf("got %v", err)
+	}
+}